@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/accountservice"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+func (h *accountHandler) CreateAPIKey(ctx context.Context, req *connect.Request[acctv1.CreateAPIKeyRequest]) (*connect.Response[acctv1.CreateAPIKeyResponse], error) {
+	r := req.Msg
+	ttl := time.Duration(r.GetTtlSeconds()) * time.Second
+
+	record, token, err := h.svc.CreateAPIKey(ctx, r.GetOrganizationId(), r.GetName(), r.GetScopes(), ttl)
+	if err != nil {
+		h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err)
+		return nil, apiKeyError(err)
+	}
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, nil, "apikey/"+record.ID)
+
+	resp := &acctv1.CreateAPIKeyResponse{
+		Key:   apiKeyToProto(record),
+		Token: token,
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *accountHandler) ListAPIKeys(ctx context.Context, req *connect.Request[acctv1.ListAPIKeysRequest]) (*connect.Response[acctv1.ListAPIKeysResponse], error) {
+	records, err := h.svc.ListAPIKeys(ctx, req.Msg.GetOrganizationId())
+	if err != nil {
+		return nil, apiKeyError(err)
+	}
+
+	resp := &acctv1.ListAPIKeysResponse{}
+	for i := range records {
+		resp.Keys = append(resp.Keys, apiKeyToProto(&records[i]))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *accountHandler) RotateAPIKey(ctx context.Context, req *connect.Request[acctv1.RotateAPIKeyRequest]) (*connect.Response[acctv1.RotateAPIKeyResponse], error) {
+	r := req.Msg
+
+	record, token, err := h.svc.RotateAPIKey(ctx, r.GetOrganizationId(), r.GetKeyId())
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err, "apikey/"+r.GetKeyId())
+	if err != nil {
+		return nil, apiKeyError(err)
+	}
+
+	resp := &acctv1.RotateAPIKeyResponse{
+		Key:   apiKeyToProto(record),
+		Token: token,
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *accountHandler) RevokeAPIKey(ctx context.Context, req *connect.Request[acctv1.RevokeAPIKeyRequest]) (*connect.Response[acctv1.RevokeAPIKeyResponse], error) {
+	r := req.Msg
+
+	record, err := h.svc.RevokeAPIKey(ctx, r.GetOrganizationId(), r.GetKeyId())
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err, "apikey/"+r.GetKeyId())
+	if err != nil {
+		return nil, apiKeyError(err)
+	}
+
+	return connect.NewResponse(&acctv1.RevokeAPIKeyResponse{Key: apiKeyToProto(record)}), nil
+}
+
+// apiKeyToProto converts a stored key to its API representation (without the hash).
+func apiKeyToProto(r *storage.APIKeyRecord) *acctv1.APIKey {
+	key := &acctv1.APIKey{
+		KeyId:          r.ID,
+		OrganizationId: r.OrganizationID,
+		Name:           r.Name,
+		Scopes:         r.Scopes,
+		CreatedAt:      timestamppb.New(r.CreatedAt),
+	}
+	if !r.RotatedAt.IsZero() {
+		key.RotatedAt = timestamppb.New(r.RotatedAt)
+	}
+	if !r.ExpiresAt.IsZero() {
+		key.ExpiresAt = timestamppb.New(r.ExpiresAt)
+	}
+	if !r.RevokedAt.IsZero() {
+		key.RevokedAt = timestamppb.New(r.RevokedAt)
+	}
+	return key
+}
+
+// apiKeyError maps unknown scopes to InvalidArgument and lookups that miss to NotFound.
+func apiKeyError(err error) error {
+	if errors.Is(err, accountservice.ErrInvalidScope) {
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return accountError(err)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/audit"
+)
+
+const defaultAuditPageSize = 100
+
+func (h *accountHandler) ListAuditEvents(ctx context.Context, req *connect.Request[acctv1.ListAuditEventsRequest]) (*connect.Response[acctv1.ListAuditEventsResponse], error) {
+	var after uint64
+	if token := req.Msg.GetPageToken(); token != "" {
+		v, err := strconv.ParseUint(token, 10, 64)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid page_token"))
+		}
+		after = v
+	}
+
+	pageSize := int(req.Msg.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultAuditPageSize
+	}
+
+	// Fetch one extra entry to know whether another page exists.
+	entries, err := h.audit.List(ctx, req.Msg.GetOrganizationId(), after, pageSize+1)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := &acctv1.ListAuditEventsResponse{}
+	if len(entries) > pageSize {
+		entries = entries[:pageSize]
+		resp.NextPageToken = strconv.FormatUint(entries[len(entries)-1].Sequence, 10)
+	}
+	for _, e := range entries {
+		resp.Events = append(resp.Events, auditEntryToProto(e))
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func (h *accountHandler) VerifyAuditLog(ctx context.Context, req *connect.Request[acctv1.VerifyAuditLogRequest]) (*connect.Response[acctv1.VerifyAuditLogResponse], error) {
+	result, err := h.audit.Verify(ctx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := &acctv1.VerifyAuditLogResponse{
+		Valid:                result.Valid,
+		RecordsChecked:       result.RecordsChecked,
+		FirstInvalidSequence: result.FirstInvalidSeq,
+		Reason:               result.Reason,
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func auditEntryToProto(e audit.Entry) *acctv1.AuditEvent {
+	return &acctv1.AuditEvent{
+		Sequence:       e.Sequence,
+		Time:           timestamppb.New(e.Event.Time),
+		CallerSubject:  e.Event.Caller.Subject,
+		CallerAddress:  e.Event.Caller.Address,
+		Action:         e.Event.Action,
+		OrganizationId: e.Event.OrganizationID,
+		RequestJson:    string(e.Event.Request),
+		ResourceIds:    e.Event.ResourceIDs,
+		Outcome:        e.Event.Outcome,
+		Error:          e.Event.Error,
+		PrevHash:       e.PrevHash,
+		Hash:           e.Hash,
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+
+	connect "connectrpc.com/connect"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+)
+
+func (h *accountHandler) ListClusters(ctx context.Context, req *connect.Request[acctv1.ListClustersRequest]) (*connect.Response[acctv1.ListClustersResponse], error) {
+	registry := h.svc.Clusters()
+
+	resp := &acctv1.ListClustersResponse{}
+	for _, c := range registry.All() {
+		info := &acctv1.Cluster{
+			Name:    c.Name,
+			Region:  c.Region,
+			Tiers:   c.Tiers,
+			Standby: c.Standby,
+		}
+
+		// An unreachable cluster is reported rather than failing the whole listing.
+		capacity, err := registry.Capacity(ctx, c)
+		if err != nil {
+			info.Error = err.Error()
+		} else {
+			info.AllocatableCpu = capacity.AllocatableCPU.String()
+			info.AllocatableMemory = capacity.AllocatableMemory.String()
+			info.CommittedCpu = capacity.CommittedCPU.String()
+			info.CommittedMemory = capacity.CommittedMemory.String()
+			info.TenantCount = int32(capacity.Tenants)
+		}
+		resp.Clusters = append(resp.Clusters, info)
+	}
+
+	return connect.NewResponse(resp), nil
+}
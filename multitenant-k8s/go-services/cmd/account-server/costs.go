@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/usage"
+)
+
+func (h *accountHandler) GetCostReport(ctx context.Context, req *connect.Request[acctv1.GetCostReportRequest]) (*connect.Response[acctv1.GetCostReportResponse], error) {
+	report, err := h.reporter.Report(ctx, req.Msg.GetBillingPeriod(), req.Msg.GetOrganizationId())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	resp := &acctv1.GetCostReportResponse{
+		BillingPeriod: report.BillingPeriod,
+		PeriodStart:   timestamppb.New(report.PeriodStart),
+		PeriodEnd:     timestamppb.New(report.PeriodEnd),
+		Currency:      report.Currency,
+		TotalCost:     report.TotalCost,
+	}
+	for _, line := range report.Lines {
+		resp.LineItems = append(resp.LineItems, costLineToProto(line))
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func (h *accountHandler) ExportCostReport(ctx context.Context, req *connect.Request[acctv1.ExportCostReportRequest]) (*connect.Response[acctv1.ExportCostReportResponse], error) {
+	report, err := h.reporter.Report(ctx, req.Msg.GetBillingPeriod(), req.Msg.GetOrganizationId())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp := &acctv1.ExportCostReportResponse{
+		BillingPeriod: report.BillingPeriod,
+		Filename:      fmt.Sprintf("cost-report-%s.csv", report.BillingPeriod),
+		Csv:           buf.Bytes(),
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func costLineToProto(line usage.CostLine) *acctv1.CostLineItem {
+	return &acctv1.CostLineItem{
+		OrganizationId: line.OrganizationID,
+		CpuCoreHours:   line.CPUCoreHours,
+		MemoryGibHours: line.MemoryGiBHours,
+		NodeHours:      line.NodeHours,
+		CpuCost:        line.CPUCost,
+		MemoryCost:     line.MemoryCost,
+		NodeCost:       line.NodeCost,
+		TotalCost:      line.TotalCost,
+	}
+}
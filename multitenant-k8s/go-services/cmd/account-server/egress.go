@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	connect "connectrpc.com/connect"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/accountservice"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/egress"
+)
+
+func (h *accountHandler) GetEgressAllowlist(ctx context.Context, req *connect.Request[acctv1.GetEgressAllowlistRequest]) (*connect.Response[acctv1.GetEgressAllowlistResponse], error) {
+	list, mode, err := h.svc.EgressAllowlist(ctx, req.Msg.GetOrganizationId())
+	if err != nil {
+		return nil, accountError(err)
+	}
+
+	resp := &acctv1.GetEgressAllowlistResponse{
+		OrganizationId: req.Msg.GetOrganizationId(),
+		Allowlist:      allowlistToProto(list),
+		Enforcement:    string(mode),
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *accountHandler) SetEgressAllowlist(ctx context.Context, req *connect.Request[acctv1.SetEgressAllowlistRequest]) (*connect.Response[acctv1.SetEgressAllowlistResponse], error) {
+	r := req.Msg
+	requested := egress.Allowlist{
+		Hostnames: r.GetAllowlist().GetHostnames(),
+		CIDRs:     r.GetAllowlist().GetCidrs(),
+	}
+
+	list, mode, err := h.svc.SetEgressAllowlist(ctx, r.GetOrganizationId(), requested)
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err)
+	if err != nil {
+		if errors.Is(err, accountservice.ErrInvalidEgress) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		return nil, accountError(err)
+	}
+
+	resp := &acctv1.SetEgressAllowlistResponse{
+		OrganizationId: r.GetOrganizationId(),
+		Allowlist:      allowlistToProto(list),
+		Enforcement:    string(mode),
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func allowlistToProto(list egress.Allowlist) *acctv1.EgressAllowlist {
+	return &acctv1.EgressAllowlist{
+		Hostnames: list.Hostnames,
+		Cidrs:     list.CIDRs,
+	}
+}
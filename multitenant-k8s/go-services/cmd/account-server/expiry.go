@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/accountservice"
+)
+
+func (h *accountHandler) SetAccountExpiry(ctx context.Context, req *connect.Request[acctv1.SetAccountExpiryRequest]) (*connect.Response[acctv1.SetAccountExpiryResponse], error) {
+	r := req.Msg
+
+	var trialEndsAt, planExpiresAt time.Time
+	if r.GetTrialEndsAt() != nil {
+		trialEndsAt = r.GetTrialEndsAt().AsTime()
+	}
+	if r.GetPlanExpiresAt() != nil {
+		planExpiresAt = r.GetPlanExpiresAt().AsTime()
+	}
+
+	record, err := h.svc.SetAccountExpiry(ctx, r.GetOrganizationId(), trialEndsAt, planExpiresAt)
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err)
+	if err != nil {
+		return nil, accountError(err)
+	}
+	return connect.NewResponse(&acctv1.SetAccountExpiryResponse{Account: accountToProto(record)}), nil
+}
+
+func (h *accountHandler) RevertExpiry(ctx context.Context, req *connect.Request[acctv1.RevertExpiryRequest]) (*connect.Response[acctv1.RevertExpiryResponse], error) {
+	r := req.Msg
+	if r.GetExtendDays() < 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("extend_days must not be negative"))
+	}
+
+	extend := time.Duration(r.GetExtendDays()) * 24 * time.Hour
+	record, err := h.svc.RevertExpiry(ctx, r.GetOrganizationId(), extend)
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err)
+	if err != nil {
+		if errors.Is(err, accountservice.ErrNotExpired) {
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		}
+		return nil, accountError(err)
+	}
+
+	return connect.NewResponse(&acctv1.RevertExpiryResponse{
+		Account:    accountToProto(record),
+		RevertedAt: timestamppb.New(record.UpdatedAt),
+	}), nil
+}
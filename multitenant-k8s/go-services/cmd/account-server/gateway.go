@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	acctconnect "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1/acctmanagementv1connect"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/gateway"
+)
+
+// restRoutes maps the REST/JSON API (see api/example-calls.js) onto the Connect
+// procedures. Bodies use snake_case field names and short enum values ("pro").
+var restRoutes = []gateway.Route{
+	{Method: http.MethodPost, Path: "/api/accounts", Procedure: acctconnect.AccountProvisioningServiceCreateAccountProcedure,
+		Request: &acctv1.CreateAccountRequest{}, Response: &acctv1.CreateAccountResponse{}, Summary: "Provision a tenant account"},
+	{Method: http.MethodGet, Path: "/api/accounts", Procedure: acctconnect.AccountProvisioningServiceListAccountsProcedure,
+		Request: &acctv1.ListAccountsRequest{}, Response: &acctv1.ListAccountsResponse{}, Summary: "List accounts"},
+	{Method: http.MethodPost, Path: "/api/accounts:import", Procedure: acctconnect.AccountProvisioningServiceImportAccountProcedure,
+		Request: &acctv1.ImportAccountRequest{}, Response: &acctv1.ImportAccountResponse{}, Summary: "Adopt an existing namespace as a tenant"},
+	{Method: http.MethodPost, Path: "/api/accounts:bulkImport", Procedure: acctconnect.AccountProvisioningServiceBulkImportAccountsProcedure,
+		Request: &acctv1.BulkImportAccountsRequest{}, Response: &acctv1.BulkImportAccountsResponse{}, Summary: "Adopt several existing namespaces"},
+	{Method: http.MethodGet, Path: "/api/accounts/{organization_id}", Procedure: acctconnect.AccountProvisioningServiceGetAccountProcedure,
+		Request: &acctv1.GetAccountRequest{}, Response: &acctv1.GetAccountResponse{}, Summary: "Get an account"},
+	{Method: http.MethodPatch, Path: "/api/accounts/{organization_id}", Procedure: acctconnect.AccountProvisioningServiceUpdateAccountProcedure,
+		Request: &acctv1.UpdateAccountRequest{}, Response: &acctv1.UpdateAccountResponse{}, Summary: "Change an account's plan tier or organization type"},
+	{Method: http.MethodDelete, Path: "/api/accounts/{organization_id}", Procedure: acctconnect.AccountProvisioningServiceDeleteAccountProcedure,
+		Request: &acctv1.DeleteAccountRequest{}, Response: &acctv1.DeleteAccountResponse{}, Summary: "Deprovision an account"},
+	{Method: http.MethodPost, Path: "/api/accounts/{organization_id}/suspend", Procedure: acctconnect.AccountProvisioningServiceSuspendAccountProcedure,
+		Request: &acctv1.SuspendAccountRequest{}, Response: &acctv1.SuspendAccountResponse{}, Summary: "Suspend an account"},
+	{Method: http.MethodPost, Path: "/api/accounts/{organization_id}/resume", Procedure: acctconnect.AccountProvisioningServiceResumeAccountProcedure,
+		Request: &acctv1.ResumeAccountRequest{}, Response: &acctv1.ResumeAccountResponse{}, Summary: "Resume a suspended account"},
+	{Method: http.MethodPut, Path: "/api/accounts/{organization_id}/expiry", Procedure: acctconnect.AccountProvisioningServiceSetAccountExpiryProcedure,
+		Request: &acctv1.SetAccountExpiryRequest{}, Response: &acctv1.SetAccountExpiryResponse{}, Summary: "Set or clear an account's trial end and plan expiry"},
+	{Method: http.MethodPost, Path: "/api/accounts/{organization_id}/expiry:revert", Procedure: acctconnect.AccountProvisioningServiceRevertExpiryProcedure,
+		Request: &acctv1.RevertExpiryRequest{}, Response: &acctv1.RevertExpiryResponse{}, Summary: "Undo an account's last expiry"},
+	{Method: http.MethodPost, Path: "/api/accounts/{organization_id}/failover", Procedure: acctconnect.AccountProvisioningServiceFailoverAccountProcedure,
+		Request: &acctv1.FailoverAccountRequest{}, Response: &acctv1.FailoverAccountResponse{}, Summary: "Fail an account over to the standby cluster"},
+	{Method: http.MethodGet, Path: "/api/accounts/{organization_id}/replication", Procedure: acctconnect.AccountProvisioningServiceGetReplicationStatusProcedure,
+		Request: &acctv1.GetReplicationStatusRequest{}, Response: &acctv1.GetReplicationStatusResponse{}, Summary: "Get an account's standby replication status"},
+	{Method: http.MethodGet, Path: "/api/accounts/{organization_id}/egress", Procedure: acctconnect.AccountProvisioningServiceGetEgressAllowlistProcedure,
+		Request: &acctv1.GetEgressAllowlistRequest{}, Response: &acctv1.GetEgressAllowlistResponse{}, Summary: "Get an account's egress allowlist"},
+	{Method: http.MethodPut, Path: "/api/accounts/{organization_id}/egress", Procedure: acctconnect.AccountProvisioningServiceSetEgressAllowlistProcedure,
+		Request: &acctv1.SetEgressAllowlistRequest{}, Response: &acctv1.SetEgressAllowlistResponse{}, Summary: "Replace an account's egress allowlist"},
+	{Method: http.MethodPost, Path: "/api/accounts/{organization_id}/domains", Procedure: acctconnect.AccountProvisioningServiceAddCustomDomainProcedure,
+		Request: &acctv1.AddCustomDomainRequest{}, Response: &acctv1.AddCustomDomainResponse{}, Summary: "Add or verify a custom domain"},
+	{Method: http.MethodPost, Path: "/api/accounts/{organization_id}/template:upgrade", Procedure: acctconnect.AccountProvisioningServiceUpgradeTemplateProcedure,
+		Request: &acctv1.UpgradeTemplateRequest{}, Response: &acctv1.UpgradeTemplateResponse{}, Summary: "Upgrade or switch an account's environment template"},
+	{Method: http.MethodPost, Path: "/api/accounts/{organization_id}/subnamespaces", Procedure: acctconnect.AccountProvisioningServiceCreateSubNamespaceProcedure,
+		Request: &acctv1.CreateSubNamespaceRequest{}, Response: &acctv1.CreateSubNamespaceResponse{}, Summary: "Create a sub-namespace"},
+	{Method: http.MethodGet, Path: "/api/accounts/{organization_id}/subnamespaces", Procedure: acctconnect.AccountProvisioningServiceListSubNamespacesProcedure,
+		Request: &acctv1.ListSubNamespacesRequest{}, Response: &acctv1.ListSubNamespacesResponse{}, Summary: "List sub-namespaces"},
+	{Method: http.MethodPost, Path: "/api/accounts/{organization_id}/api-keys", Procedure: acctconnect.AccountProvisioningServiceCreateAPIKeyProcedure,
+		Request: &acctv1.CreateAPIKeyRequest{}, Response: &acctv1.CreateAPIKeyResponse{}, Summary: "Mint an API key"},
+	{Method: http.MethodGet, Path: "/api/accounts/{organization_id}/api-keys", Procedure: acctconnect.AccountProvisioningServiceListAPIKeysProcedure,
+		Request: &acctv1.ListAPIKeysRequest{}, Response: &acctv1.ListAPIKeysResponse{}, Summary: "List API keys"},
+	{Method: http.MethodPost, Path: "/api/accounts/{organization_id}/api-keys/{key_id}/rotate", Procedure: acctconnect.AccountProvisioningServiceRotateAPIKeyProcedure,
+		Request: &acctv1.RotateAPIKeyRequest{}, Response: &acctv1.RotateAPIKeyResponse{}, Summary: "Rotate an API key's secret"},
+	{Method: http.MethodDelete, Path: "/api/accounts/{organization_id}/api-keys/{key_id}", Procedure: acctconnect.AccountProvisioningServiceRevokeAPIKeyProcedure,
+		Request: &acctv1.RevokeAPIKeyRequest{}, Response: &acctv1.RevokeAPIKeyResponse{}, Summary: "Revoke an API key"},
+	{Method: http.MethodPost, Path: "/api/accounts/{organization_id}/invitations", Procedure: acctconnect.AccountProvisioningServiceInviteMemberProcedure,
+		Request: &acctv1.InviteMemberRequest{}, Response: &acctv1.InviteMemberResponse{}, Summary: "Invite a user to the organization"},
+	{Method: http.MethodPost, Path: "/api/invitations:accept", Procedure: acctconnect.AccountProvisioningServiceAcceptInvitationProcedure,
+		Request: &acctv1.AcceptInvitationRequest{}, Response: &acctv1.AcceptInvitationResponse{}, Summary: "Accept an invitation with its token"},
+	{Method: http.MethodGet, Path: "/api/accounts/{organization_id}/members", Procedure: acctconnect.AccountProvisioningServiceListMembersProcedure,
+		Request: &acctv1.ListMembersRequest{}, Response: &acctv1.ListMembersResponse{}, Summary: "List members and pending invitations"},
+	{Method: http.MethodDelete, Path: "/api/accounts/{organization_id}/members/{email}", Procedure: acctconnect.AccountProvisioningServiceRemoveMemberProcedure,
+		Request: &acctv1.RemoveMemberRequest{}, Response: &acctv1.RemoveMemberResponse{}, Summary: "Remove a member or revoke their invitation"},
+	{Method: http.MethodGet, Path: "/api/replication", Procedure: acctconnect.AccountProvisioningServiceGetReplicationStatusProcedure,
+		Request: &acctv1.GetReplicationStatusRequest{}, Response: &acctv1.GetReplicationStatusResponse{}, Summary: "Get standby replication status of every account"},
+	{Method: http.MethodGet, Path: "/api/clusters", Procedure: acctconnect.AccountProvisioningServiceListClustersProcedure,
+		Request: &acctv1.ListClustersRequest{}, Response: &acctv1.ListClustersResponse{}, Summary: "List registered clusters"},
+	{Method: http.MethodGet, Path: "/api/templates", Procedure: acctconnect.AccountProvisioningServiceListTemplatesProcedure,
+		Request: &acctv1.ListTemplatesRequest{}, Response: &acctv1.ListTemplatesResponse{}, Summary: "List environment templates"},
+	{Method: http.MethodGet, Path: "/api/costs", Procedure: acctconnect.AccountProvisioningServiceGetCostReportProcedure,
+		Request: &acctv1.GetCostReportRequest{}, Response: &acctv1.GetCostReportResponse{}, Summary: "Get the cost report for a billing period"},
+	{Method: http.MethodPost, Path: "/api/costs:export", Procedure: acctconnect.AccountProvisioningServiceExportCostReportProcedure,
+		Request: &acctv1.ExportCostReportRequest{}, Response: &acctv1.ExportCostReportResponse{}, Summary: "Export the cost report"},
+	{Method: http.MethodGet, Path: "/api/audit/events", Procedure: acctconnect.AccountProvisioningServiceListAuditEventsProcedure,
+		Request: &acctv1.ListAuditEventsRequest{}, Response: &acctv1.ListAuditEventsResponse{}, Summary: "List audit log entries"},
+	{Method: http.MethodPost, Path: "/api/audit:verify", Procedure: acctconnect.AccountProvisioningServiceVerifyAuditLogProcedure,
+		Request: &acctv1.VerifyAuditLogRequest{}, Response: &acctv1.VerifyAuditLogResponse{}, Summary: "Verify the audit log hash chain"},
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/accountservice"
+)
+
+func (h *accountHandler) ImportAccount(ctx context.Context, req *connect.Request[acctv1.ImportAccountRequest]) (*connect.Response[acctv1.ImportAccountResponse], error) {
+	r := req.Msg
+	result, err := h.svc.ImportAccount(ctx, importRequest(r))
+	if err != nil {
+		h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err)
+		return nil, importError(err)
+	}
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, nil, result.Namespace, result.IAMRoleARN)
+
+	return connect.NewResponse(importResultToProto(result)), nil
+}
+
+func (h *accountHandler) BulkImportAccounts(ctx context.Context, req *connect.Request[acctv1.BulkImportAccountsRequest]) (*connect.Response[acctv1.BulkImportAccountsResponse], error) {
+	reqs := make([]accountservice.ImportRequest, 0, len(req.Msg.GetAccounts()))
+	for _, r := range req.Msg.GetAccounts() {
+		reqs = append(reqs, importRequest(r))
+	}
+
+	resp := &acctv1.BulkImportAccountsResponse{}
+	for i, res := range h.svc.ImportAccounts(ctx, reqs) {
+		entry := &acctv1.ImportAccountResult{OrganizationId: res.OrganizationID}
+		if res.Err != nil {
+			h.audit.RecordRPC(ctx, req.Spec().Procedure, res.OrganizationID, req.Msg.GetAccounts()[i], res.Err)
+			entry.Error = res.Err.Error()
+			resp.Failed++
+		} else {
+			h.audit.RecordRPC(ctx, req.Spec().Procedure, res.OrganizationID, req.Msg.GetAccounts()[i], nil, res.Result.Namespace, res.Result.IAMRoleARN)
+			entry.Account = importResultToProto(res.Result)
+			resp.Imported++
+		}
+		resp.Results = append(resp.Results, entry)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func importRequest(r *acctv1.ImportAccountRequest) accountservice.ImportRequest {
+	return accountservice.ImportRequest{
+		OrganizationID:   r.GetOrganizationId(),
+		Namespace:        r.GetNamespace(),
+		OrganizationType: r.GetOrganizationType(),
+		PlanTier:         r.GetPlanTier(),
+		S3Bucket:         r.GetS3Bucket(),
+		Cluster:          r.GetCluster(),
+		IAMRoleARN:       r.GetIamRoleArn(),
+	}
+}
+
+func importResultToProto(result *accountservice.AccountProvisioningResult) *acctv1.ImportAccountResponse {
+	return &acctv1.ImportAccountResponse{
+		OrganizationId: result.OrganizationID,
+		Namespace:      result.Namespace,
+		Cluster:        result.Cluster,
+		IamRoleArn:     result.IAMRoleARN,
+		S3Bucket:       result.S3Bucket,
+		S3Prefix:       result.S3Prefix,
+		ResourceQuota:  result.ResourceQuota,
+		CreatedAt:      timestamppb.New(result.CreatedAt),
+	}
+}
+
+// importError maps bad input to InvalidArgument and double registration to AlreadyExists.
+func importError(err error) error {
+	switch {
+	case errors.Is(err, accountservice.ErrInvalidImport):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, accountservice.ErrAccountExists):
+		return connect.NewError(connect.CodeAlreadyExists, err)
+	}
+	return accountError(err)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/accountservice"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/ingress"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+func (h *accountHandler) AddCustomDomain(ctx context.Context, req *connect.Request[acctv1.AddCustomDomainRequest]) (*connect.Response[acctv1.AddCustomDomainResponse], error) {
+	r := req.Msg
+
+	domain, err := h.svc.AddCustomDomain(ctx, r.GetOrganizationId(), r.GetDomain())
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err)
+	if err != nil {
+		switch {
+		case errors.Is(err, accountservice.ErrInvalidDomain):
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		case errors.Is(err, accountservice.ErrIngressDisabled):
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		case errors.Is(err, accountservice.ErrDomainTaken):
+			return nil, connect.NewError(connect.CodeAlreadyExists, err)
+		}
+		return nil, accountError(err)
+	}
+
+	return connect.NewResponse(&acctv1.AddCustomDomainResponse{
+		OrganizationId: r.GetOrganizationId(),
+		Domain:         customDomainToProto(*domain),
+	}), nil
+}
+
+func customDomainToProto(d storage.CustomDomain) *acctv1.CustomDomain {
+	pb := &acctv1.CustomDomain{
+		Domain:             d.Domain,
+		Verified:           !d.VerifiedAt.IsZero(),
+		VerificationRecord: ingress.VerificationPrefix + d.Domain,
+		VerificationValue:  d.VerificationToken,
+	}
+	if pb.Verified {
+		pb.VerifiedAt = timestamppb.New(d.VerifiedAt)
+	}
+	return pb
+}
@@ -2,75 +2,360 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
 	acctconnect "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1/acctmanagementv1connect"
 	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/accountservice"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/audit"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/auth"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/billing"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/events"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/gateway"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/gitops"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/health"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/identity"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/ingress"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/jobtopics"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/schedulerservice"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/templates"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/usage"
 )
 
 // accountHandler adapts pkg/accountservice.Service to the generated Connect handler interface.
 type accountHandler struct {
-	svc *accountservice.Service
+	svc      *accountservice.Service
+	reporter *usage.Reporter
+	audit    *audit.Logger
 }
 
 func (h *accountHandler) CreateAccount(ctx context.Context, req *connect.Request[acctv1.CreateAccountRequest]) (*connect.Response[acctv1.CreateAccountResponse], error) {
 	r := req.Msg
 
-	result, err := h.svc.ProvisionAccount(ctx, r.GetOrganizationId(), r.GetOrganizationType(), r.GetPlanTier(), r.GetS3Bucket())
+	result, err := h.svc.ProvisionAccount(ctx, accountservice.ProvisionOptions{
+		OrgID:            r.GetOrganizationId(),
+		OrganizationType: r.GetOrganizationType(),
+		PlanTier:         r.GetPlanTier(),
+		S3Bucket:         r.GetS3Bucket(),
+		Region:           r.GetRegion(),
+		Registry:         r.GetContainerRegistry(),
+		JobTopic:         r.GetDedicatedJobTopic(),
+		Ingress:          r.GetIngress(),
+		Template:         r.GetTemplate(),
+	})
 	if err != nil {
+		h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err)
+		if errors.Is(err, accountservice.ErrRegistryDisabled) || errors.Is(err, accountservice.ErrJobTopicsDisabled) ||
+			errors.Is(err, accountservice.ErrIngressDisabled) || errors.Is(err, accountservice.ErrTemplatesDisabled) ||
+			errors.Is(err, accountservice.ErrGitOpsUnsupported) {
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		}
+		if errors.Is(err, templates.ErrUnknownTemplate) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, nil, result.Namespace, result.IAMRoleARN)
 
 	resp := &acctv1.CreateAccountResponse{
-		OrganizationId:   result.OrganizationID,
-		Namespace:        result.Namespace,
-		OrganizationType: r.GetOrganizationType(),
-		PlanTier:         r.GetPlanTier(),
-		IamRoleArn:       result.IAMRoleARN,
-		S3Bucket:         result.S3Bucket,
-		S3Prefix:         result.S3Prefix,
-		ResourceQuota:    result.ResourceQuota,
-		Status:           "ACTIVE",
+		OrganizationId:    result.OrganizationID,
+		Namespace:         result.Namespace,
+		OrganizationType:  r.GetOrganizationType(),
+		PlanTier:          r.GetPlanTier(),
+		IamRoleArn:        result.IAMRoleARN,
+		S3Bucket:          result.S3Bucket,
+		S3Prefix:          result.S3Prefix,
+		ResourceQuota:     result.ResourceQuota,
+		Status:            result.Status,
+		CreatedAt:         timestamppb.New(result.CreatedAt),
+		Cluster:           result.Cluster,
+		ContainerRegistry: result.Registry,
+		JobTopic:          result.JobTopic,
+		IngressHostname:   result.IngressHostname,
+		Template:          appliedTemplateToProto(result.Template),
+		GitopsRevision:    result.GitOpsRevision,
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+func (h *accountHandler) GetAccount(ctx context.Context, req *connect.Request[acctv1.GetAccountRequest]) (*connect.Response[acctv1.GetAccountResponse], error) {
+	record, err := h.svc.GetAccount(ctx, req.Msg.GetOrganizationId())
+	if err != nil {
+		return nil, accountError(err)
+	}
+	return connect.NewResponse(accountToProto(record)), nil
+}
+
+func (h *accountHandler) UpdateAccount(ctx context.Context, req *connect.Request[acctv1.UpdateAccountRequest]) (*connect.Response[acctv1.UpdateAccountResponse], error) {
+	r := req.Msg
+
+	current, err := h.svc.GetAccount(ctx, r.GetOrganizationId())
+	if err != nil {
+		return nil, accountError(err)
+	}
+	if t := r.GetOrganizationType(); t != acctv1.OrganizationType_ORGANIZATION_TYPE_UNSPECIFIED && t.String() != current.OrganizationType {
+		return nil, connect.NewError(connect.CodeUnimplemented, fmt.Errorf("changing organization type is not supported"))
 	}
 
+	record, quota, err := h.svc.UpdateAccount(ctx, r.GetOrganizationId(), r.GetPlanTier())
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err)
+	if err != nil {
+		return nil, accountError(err)
+	}
+
+	resp := &acctv1.UpdateAccountResponse{
+		OrganizationId: record.OrganizationID,
+		PlanTier:       r.GetPlanTier(),
+		ResourceQuota:  quota,
+		UpdatedAt:      timestamppb.New(record.UpdatedAt),
+	}
 	return connect.NewResponse(resp), nil
 }
 
-// The rest of the RPCs can be wired later; for now return Unimplemented.
-func (h *accountHandler) GetAccount(context.Context, *connect.Request[acctv1.GetAccountRequest]) (*connect.Response[acctv1.GetAccountResponse], error) {
-	return nil, connect.NewError(connect.CodeUnimplemented, nil)
+func (h *accountHandler) SuspendAccount(ctx context.Context, req *connect.Request[acctv1.SuspendAccountRequest]) (*connect.Response[acctv1.SuspendAccountResponse], error) {
+	record, err := h.svc.SuspendAccount(ctx, req.Msg.GetOrganizationId(), req.Msg.GetReason())
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, req.Msg.GetOrganizationId(), req.Msg, err)
+	if err != nil {
+		return nil, accountError(err)
+	}
+
+	resp := &acctv1.SuspendAccountResponse{
+		OrganizationId: record.OrganizationID,
+		Status:         record.Status,
+		SuspendedAt:    timestamppb.New(record.UpdatedAt),
+	}
+	return connect.NewResponse(resp), nil
 }
 
-func (h *accountHandler) UpdateAccount(context.Context, *connect.Request[acctv1.UpdateAccountRequest]) (*connect.Response[acctv1.UpdateAccountResponse], error) {
-	return nil, connect.NewError(connect.CodeUnimplemented, nil)
+func (h *accountHandler) ResumeAccount(ctx context.Context, req *connect.Request[acctv1.ResumeAccountRequest]) (*connect.Response[acctv1.ResumeAccountResponse], error) {
+	record, err := h.svc.ResumeAccount(ctx, req.Msg.GetOrganizationId())
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, req.Msg.GetOrganizationId(), req.Msg, err)
+	if err != nil {
+		return nil, accountError(err)
+	}
+
+	resp := &acctv1.ResumeAccountResponse{
+		OrganizationId: record.OrganizationID,
+		Status:         record.Status,
+		ResumedAt:      timestamppb.New(record.UpdatedAt),
+	}
+	return connect.NewResponse(resp), nil
 }
 
 func (h *accountHandler) DeleteAccount(ctx context.Context, req *connect.Request[acctv1.DeleteAccountRequest]) (*connect.Response[acctv1.DeleteAccountResponse], error) {
-	if err := h.svc.DeleteAccount(ctx, req.Msg.GetOrganizationId()); err != nil {
+	err := h.svc.DeleteAccount(ctx, req.Msg.GetOrganizationId())
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, req.Msg.GetOrganizationId(), req.Msg, err)
+	if err != nil {
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 	resp := &acctv1.DeleteAccountResponse{
 		OrganizationId: req.Msg.GetOrganizationId(),
 		Status:         "DELETED",
+		DeletedAt:      timestamppb.Now(),
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *accountHandler) ListAccounts(ctx context.Context, req *connect.Request[acctv1.ListAccountsRequest]) (*connect.Response[acctv1.ListAccountsResponse], error) {
+	records, err := h.svc.ListAccounts(ctx, req.Msg.GetStatusFilter())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	pageSize := int(req.Msg.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	// Records are ordered by organization ID; the page token is the last ID returned.
+	resp := &acctv1.ListAccountsResponse{TotalCount: int32(len(records))}
+	for _, r := range records {
+		if r.OrganizationID <= req.Msg.GetPageToken() {
+			continue
+		}
+		if len(resp.Accounts) == pageSize {
+			resp.NextPageToken = resp.Accounts[len(resp.Accounts)-1].OrganizationId
+			break
+		}
+		resp.Accounts = append(resp.Accounts, accountToProto(&r))
 	}
+
 	return connect.NewResponse(resp), nil
 }
 
-func (h *accountHandler) ListAccounts(context.Context, *connect.Request[acctv1.ListAccountsRequest]) (*connect.Response[acctv1.ListAccountsResponse], error) {
-	return nil, connect.NewError(connect.CodeUnimplemented, nil)
+// accountToProto converts a registry record to the API representation.
+func accountToProto(r *storage.AccountRecord) *acctv1.GetAccountResponse {
+	tier := acctv1.PlanTier(acctv1.PlanTier_value[r.PlanTier])
+	quota, _ := accountservice.QuotaForTier(tier)
+
+	resp := &acctv1.GetAccountResponse{
+		OrganizationId:    r.OrganizationID,
+		Namespace:         r.Namespace,
+		OrganizationType:  acctv1.OrganizationType(acctv1.OrganizationType_value[r.OrganizationType]),
+		PlanTier:          tier,
+		IamRoleArn:        r.IAMRoleARN,
+		S3Bucket:          r.S3Bucket,
+		S3Prefix:          r.S3Prefix,
+		ResourceQuota:     quota,
+		Status:            r.Status,
+		CreatedAt:         timestamppb.New(r.CreatedAt),
+		UpdatedAt:         timestamppb.New(r.UpdatedAt),
+		Cluster:           r.Cluster,
+		SuspendReason:     r.SuspendReason,
+		ContainerRegistry: r.Registry,
+		JobTopic:          r.JobTopic,
+		IngressHostname:   r.IngressHostname,
+		Template:          appliedTemplateToProto(r.Template),
+		GitopsRevision:    r.GitOpsRevision,
+	}
+	for _, d := range r.CustomDomains {
+		resp.CustomDomains = append(resp.CustomDomains, customDomainToProto(d))
+	}
+	if !r.TrialEndsAt.IsZero() {
+		resp.TrialEndsAt = timestamppb.New(r.TrialEndsAt)
+	}
+	if !r.PlanExpiresAt.IsZero() {
+		resp.PlanExpiresAt = timestamppb.New(r.PlanExpiresAt)
+	}
+	return resp
+}
+
+// accountError maps registry lookups that miss to NotFound.
+func accountError(err error) error {
+	if errors.Is(err, storage.ErrNotFound) {
+		return connect.NewError(connect.CodeNotFound, err)
+	}
+	return connect.NewError(connect.CodeInternal, err)
 }
 
 func main() {
+	backend, err := storage.NewBackendFromEnv()
+	if err != nil {
+		log.Fatalf("failed to create storage backend: %v", err)
+	}
+	usageStore := storage.NewUsageStore(backend)
+	// The audit chain is keyed so that write access to storage alone cannot rewrite it
+	auditLog, err := audit.NewLogger(storage.NewAuditStore(backend), []byte(os.Getenv("AUDIT_HMAC_KEY")))
+	if err != nil {
+		log.Fatalf("failed to create audit logger (AUDIT_HMAC_KEY): %v", err)
+	}
+	accounts := storage.NewAccountStore(backend)
+
 	// Wire the domain service from environment.
 	cfg := accountservice.Config{
-		KubeConfigPath: os.Getenv("KUBECONFIG"),
-		AWSRegion:      os.Getenv("AWS_REGION"),
-		ClusterARN:     os.Getenv("CLUSTER_ARN"),
+		KubeConfigPath:        os.Getenv("KUBECONFIG"),
+		ClusterRegistryPath:   os.Getenv("CLUSTER_REGISTRY_PATH"),
+		AWSRegion:             os.Getenv("AWS_REGION"),
+		ClusterARN:            os.Getenv("CLUSTER_ARN"),
+		AuditLog:              auditLog,
+		Accounts:              accounts,
+		APIKeys:               storage.NewAPIKeyStore(backend),
+		Replication:           storage.NewReplicationStore(backend),
+		Members:               storage.NewMemberStore(backend),
+		Invitations:           storage.NewInvitationStore(backend),
+		InvitationTTL:         time.Duration(envIntOrDefault("INVITATION_TTL_HOURS", 168)) * time.Hour,
+		EnterprisePodSecurity: os.Getenv("ENTERPRISE_POD_SECURITY"),
+		RegistryPullRoleARN:   os.Getenv("REGISTRY_PULL_ROLE_ARN"),
+		Expiry: accountservice.ExpiryPolicy{
+			TrialPeriod: envDaysOrDefault("FREE_TRIAL_DAYS", 30),
+			WarnBefore:  envDaysOrDefault("EXPIRY_WARNING_DAYS", 7),
+			PlanAction:  os.Getenv("PLAN_EXPIRY_ACTION"),
+		},
+	}
+
+	// Invitation tokens are signed; without a key members cannot be invited.
+	if key := os.Getenv("INVITATION_SIGNING_KEY"); key != "" {
+		signer, err := auth.NewInvitationSigner([]byte(key))
+		if err != nil {
+			log.Fatalf("failed to create invitation signer: %v", err)
+		}
+		cfg.InvitationSigner = signer
+	} else {
+		log.Printf("INVITATION_SIGNING_KEY not set; member invitations are disabled")
+	}
+	// Invoice tenants through the billing provider when configured.
+	billingPrices := billing.PricesFromEnv()
+	if key := os.Getenv("BILLING_API_KEY"); key != "" {
+		client, err := billing.NewStripeClient(os.Getenv("BILLING_API_URL"), key, billingPrices)
+		if err != nil {
+			log.Fatalf("failed to create billing client: %v", err)
+		}
+		cfg.Billing = client
+		cfg.BillingAccounts = storage.NewBillingStore(backend)
+	} else {
+		log.Printf("BILLING_API_KEY not set; billing is disabled")
+	}
+	// Tenants may ask for job topics of their own on the events cluster.
+	if brokers := splitBrokers(os.Getenv("KAFKA_BROKERS")); len(brokers) > 0 {
+		admin, err := jobtopics.NewAdmin(brokers,
+			envIntOrDefault("JOB_TOPIC_REPLICATION_FACTOR", 3),
+			envOrDefault("TENANT_KAFKA_PRINCIPAL_PREFIX", "User:tenant-"))
+		if err != nil {
+			log.Fatalf("failed to create job topic admin: %v", err)
+		}
+		cfg.JobTopics = admin
+	}
+	// Serve tenant subdomains from a shared Gateway when a domain is configured.
+	if domain := os.Getenv("INGRESS_DOMAIN"); domain != "" {
+		gatewayNamespace, gatewayName, ok := strings.Cut(envOrDefault("INGRESS_GATEWAY", "gateway-system/tenant-gateway"), "/")
+		if !ok {
+			log.Fatalf("INGRESS_GATEWAY must be <namespace>/<name>")
+		}
+		manager, err := ingress.NewManager(ingress.Config{
+			Domain:             domain,
+			GatewayNamespace:   gatewayNamespace,
+			GatewayName:        gatewayName,
+			Issuer:             envOrDefault("INGRESS_CLUSTER_ISSUER", "letsencrypt-dns"),
+			CustomDomainIssuer: os.Getenv("INGRESS_DOMAIN_ISSUER"),
+		})
+		if err != nil {
+			log.Fatalf("failed to configure ingress: %v", err)
+		}
+		cfg.Ingress = manager
+	}
+	// Seed new tenants from the environment templates in TEMPLATES_DIR.
+	if dir := os.Getenv("TEMPLATES_DIR"); dir != "" {
+		catalog, err := templates.LoadCatalog(dir, os.Getenv("HELM_BINARY"))
+		if err != nil {
+			log.Fatalf("failed to load environment templates: %v", err)
+		}
+		cfg.Templates = catalog
+	}
+	// Commit tenants to a GitOps repository instead of creating them when configured.
+	if repo := os.Getenv("GITOPS_REPO_PATH"); repo != "" {
+		exporter, err := gitops.NewExporter(gitops.Config{
+			RepoPath:    repo,
+			Dir:         os.Getenv("GITOPS_DIR"),
+			Format:      gitops.Format(os.Getenv("GITOPS_FORMAT")),
+			AuthorName:  os.Getenv("GITOPS_AUTHOR_NAME"),
+			AuthorEmail: os.Getenv("GITOPS_AUTHOR_EMAIL"),
+			Push:        os.Getenv("GITOPS_PUSH") == "true",
+			Sync:        os.Getenv("GITOPS_SYNC"),
+		})
+		if err != nil {
+			log.Fatalf("failed to open gitops repository: %v", err)
+		}
+		cfg.GitOps = exporter
+	}
+	// Mirror membership into IdP groups over SCIM when configured.
+	if scimURL := os.Getenv("IDP_SCIM_URL"); scimURL != "" {
+		groups, err := identity.NewSCIMClient(scimURL, os.Getenv("IDP_SCIM_TOKEN"))
+		if err != nil {
+			log.Fatalf("failed to create SCIM client: %v", err)
+		}
+		cfg.Groups = groups
 	}
 
 	svc, err := accountservice.New(cfg)
@@ -78,15 +363,162 @@ func main() {
 		log.Fatalf("failed to create account service: %v", err)
 	}
 
-	h := &accountHandler{svc: svc}
+	prices, err := usage.LoadPriceSheet(os.Getenv("PRICE_SHEET_PATH"))
+	if err != nil {
+		log.Fatalf("failed to load price sheet: %v", err)
+	}
+
+	// Sample tenant usage on every cluster in the background for cost reports.
+	sampleInterval := time.Duration(envIntOrDefault("USAGE_SAMPLE_INTERVAL_SECONDS", 60)) * time.Second
+	for _, cluster := range svc.Clusters().All() {
+		collector, err := usage.NewCollector(cluster.Client, usageStore, sampleInterval)
+		if err != nil {
+			log.Fatalf("failed to create usage collector for cluster %s: %v", cluster.Name, err)
+		}
+		go collector.Run(context.Background())
+	}
+	if horizon := envDaysOrDefault("USAGE_RETENTION_DAYS", 400); horizon > 0 {
+		go usage.RunRetention(context.Background(), usageStore, horizon)
+	}
+
+	// Bill completed jobs on every cluster.
+	if cfg.Billing != nil {
+		meterInterval := time.Duration(envIntOrDefault("BILLING_METER_INTERVAL_SECONDS", 60)) * time.Second
+		for _, cluster := range svc.Clusters().All() {
+			meter, err := billing.NewMeter(cluster.Client, cfg.BillingAccounts, cfg.Billing, meterInterval)
+			if err != nil {
+				log.Fatalf("failed to create job meter for cluster %s: %v", cluster.Name, err)
+			}
+			go meter.Run(context.Background())
+		}
+	}
+
+	// Re-resolve hostname allowlists on clusters without FQDN-aware policies.
+	egressInterval := time.Duration(envIntOrDefault("EGRESS_RESOLVE_INTERVAL_SECONDS", 300)) * time.Second
+	go svc.RunEgressRefresh(context.Background(), egressInterval)
+
+	// Mirror tenants to the standby cluster when the registry has one.
+	if standby, ok := svc.Clusters().Standby(); ok {
+		replicationInterval := time.Duration(envIntOrDefault("DR_REPLICATION_INTERVAL_SECONDS", 60)) * time.Second
+		log.Printf("replicating tenants to standby cluster %s every %s", standby.Name, replicationInterval)
+		go svc.RunReplication(context.Background(), replicationInterval)
+	}
+
+	// Warn about, then downgrade or suspend, accounts past their trial or plan.
+	expiryInterval := time.Duration(envIntOrDefault("EXPIRY_CHECK_INTERVAL_SECONDS", 3600)) * time.Second
+	go svc.RunExpiry(context.Background(), expiryInterval)
+
+	// Renew registry pull secrets inside the hour their credentials last.
+	if svc.RegistryEnabled() {
+		registryInterval := time.Duration(envIntOrDefault("REGISTRY_REFRESH_INTERVAL_SECONDS", 1800)) * time.Second
+		go svc.RunRegistryRefresh(context.Background(), registryInterval)
+	}
+
+	// Activate GitOps tenants once the sync controller has applied their commit.
+	if svc.GitOpsEnabled() {
+		gitopsInterval := time.Duration(envIntOrDefault("GITOPS_SYNC_INTERVAL_SECONDS", 30)) * time.Second
+		go svc.RunGitOpsSync(context.Background(), gitopsInterval)
+	}
+
+	// Repair drift between recorded members and RoleBindings/IdP groups.
+	memberSyncInterval := time.Duration(envIntOrDefault("MEMBER_SYNC_INTERVAL_SECONDS", 600)) * time.Second
+	go svc.RunMemberSync(context.Background(), memberSyncInterval)
+
+	// Publish account lifecycle events committed to the outbox.
+	if brokers := splitBrokers(os.Getenv("KAFKA_BROKERS")); len(brokers) > 0 {
+		queue, err := schedulerservice.NewKafkaQueue(brokers, envOrDefault("ACCOUNT_EVENTS_TOPIC", "account-events"))
+		if err != nil {
+			log.Fatalf("failed to create event publisher: %v", err)
+		}
+		relay, err := events.NewRelay(storage.NewOutboxStore(backend), queue, time.Second)
+		if err != nil {
+			log.Fatalf("failed to create event relay: %v", err)
+		}
+		go relay.Run(context.Background())
+	} else {
+		log.Printf("KAFKA_BROKERS not set; account events stay in the outbox")
+	}
+
+	h := &accountHandler{
+		svc:      svc,
+		reporter: usage.NewReporter(usageStore, prices),
+		audit:    auditLog,
+	}
+
+	// Every account-management RPC is restricted to platform admins, so API keys
+	// (which are always tenant-scoped) are not accepted here.
+	verifier, err := auth.NewVerifier(context.Background(), auth.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("failed to create token verifier: %v", err)
+	}
+	// Tenant admins manage their own organization's members; invitees accept
+	// with the signed invitation token, before they belong to any organization.
+	policy := auth.Policy{
+		AdminPrefixes: []string{"/" + acctconnect.AccountProvisioningServiceName + "/"},
+		TenantAdmin: []string{
+			acctconnect.AccountProvisioningServiceInviteMemberProcedure,
+			acctconnect.AccountProvisioningServiceListMembersProcedure,
+			acctconnect.AccountProvisioningServiceRemoveMemberProcedure,
+			acctconnect.AccountProvisioningServiceAddCustomDomainProcedure,
+		},
+		Public: []string{acctconnect.AccountProvisioningServiceAcceptInvitationProcedure},
+	}
 
 	mux := http.NewServeMux()
-	path, handler := acctconnect.NewAccountProvisioningServiceHandler(h)
+	path, handler := acctconnect.NewAccountProvisioningServiceHandler(h,
+		connect.WithInterceptors(auth.NewInterceptor(auth.Tokens(verifier, nil), policy), audit.NewCallerInterceptor()),
+	)
 	mux.Handle(path, handler)
 
+	// Subscription webhooks authenticate with their signature, not a token.
+	if cfg.Billing != nil {
+		webhook, err := billing.NewWebhookHandler(os.Getenv("BILLING_WEBHOOK_SECRET"), billingPrices, svc)
+		if err != nil {
+			log.Fatalf("failed to create billing webhook: %v", err)
+		}
+		mux.Handle("POST /webhooks/billing", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := audit.WithCaller(r.Context(), audit.Caller{Subject: "billing-webhook", Address: r.RemoteAddr})
+			webhook.ServeHTTP(w, r.WithContext(ctx))
+		}))
+	}
+
+	rest, err := gateway.New("Account Provisioning API", "v1", handler, restRoutes)
+	if err != nil {
+		log.Fatalf("failed to create REST gateway: %v", err)
+	}
+	rest.Register(mux)
+
+	// Readiness covers every cluster, IAM, Redis and, since events wait in the
+	// outbox while it is down, optionally Kafka.
+	probes := []health.Probe{health.IAMProbe(svc.AWSConfig())}
+	for _, cluster := range svc.Clusters().All() {
+		probe := health.KubernetesProbe("kubernetes/"+cluster.Name, cluster.Client)
+		probe.Optional = cluster.Standby
+		probes = append(probes, probe)
+	}
+	if redisBackend, ok := backend.(*storage.RedisBackend); ok {
+		probes = append(probes, health.RedisProbe(redisBackend.Client()))
+	}
+	if brokers := splitBrokers(os.Getenv("KAFKA_BROKERS")); len(brokers) > 0 {
+		probe := health.KafkaProbe(brokers, envOrDefault("ACCOUNT_EVENTS_TOPIC", "account-events"))
+		probe.Optional = true
+		probes = append(probes, probe)
+	}
+	checker, err := health.NewChecker(3*time.Second, probes...)
+	if err != nil {
+		log.Fatalf("failed to create health checker: %v", err)
+	}
+	checker.Register(mux, acctconnect.AccountProvisioningServiceName)
+
 	addr := ":" + envOrDefault("ACCOUNT_SERVER_PORT", "8080")
 	log.Printf("AccountProvisioningService listening on %s", addr)
-	if err := http.ListenAndServe(addr, mux); err != nil {
+	// HTTP/2 without TLS too, which gRPC clients such as grpc_health_probe
+	// and kubelet grpc probes require
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	server := &http.Server{Addr: addr, Handler: mux, Protocols: protocols}
+	if err := server.ListenAndServe(); err != nil {
 		log.Fatalf("server error: %v", err)
 	}
 }
@@ -97,3 +529,33 @@ func envOrDefault(key, def string) string {
 	}
 	return def
 }
+
+func envIntOrDefault(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return def
+}
+
+// envDaysOrDefault reads a number of days; unlike envIntOrDefault, 0 is allowed and disables the feature.
+func envDaysOrDefault(key string, def int) time.Duration {
+	days := def
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			days = n
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func splitBrokers(v string) []string {
+	var brokers []string
+	for _, b := range strings.Split(v, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			brokers = append(brokers, b)
+		}
+	}
+	return brokers
+}
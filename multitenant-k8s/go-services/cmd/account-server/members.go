@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/accountservice"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/auth"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+func (h *accountHandler) InviteMember(ctx context.Context, req *connect.Request[acctv1.InviteMemberRequest]) (*connect.Response[acctv1.InviteMemberResponse], error) {
+	r := req.Msg
+	var invitedBy string
+	if p, ok := auth.PrincipalFromContext(ctx); ok {
+		invitedBy = p.Subject
+	}
+
+	record, token, err := h.svc.InviteMember(ctx, r.GetOrganizationId(), r.GetEmail(), r.GetPersona(), invitedBy)
+	if err != nil {
+		h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err)
+		return nil, memberError(err)
+	}
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, nil, "invitation/"+record.ID)
+
+	return connect.NewResponse(&acctv1.InviteMemberResponse{
+		Invitation: invitationToProto(record),
+		Token:      token,
+	}), nil
+}
+
+func (h *accountHandler) AcceptInvitation(ctx context.Context, req *connect.Request[acctv1.AcceptInvitationRequest]) (*connect.Response[acctv1.AcceptInvitationResponse], error) {
+	// The token is a credential; keep it out of the audit log
+	redacted := &acctv1.AcceptInvitationRequest{}
+	var subject string
+	if p, ok := auth.PrincipalFromContext(ctx); ok {
+		subject = p.Subject
+	}
+
+	member, err := h.svc.AcceptInvitation(ctx, req.Msg.GetToken(), subject)
+	if err != nil {
+		h.audit.RecordRPC(ctx, req.Spec().Procedure, "", redacted, err)
+		return nil, memberError(err)
+	}
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, member.OrganizationID, redacted, nil, "member/"+member.Email)
+
+	return connect.NewResponse(&acctv1.AcceptInvitationResponse{Member: memberToProto(member)}), nil
+}
+
+func (h *accountHandler) ListMembers(ctx context.Context, req *connect.Request[acctv1.ListMembersRequest]) (*connect.Response[acctv1.ListMembersResponse], error) {
+	members, invitations, err := h.svc.ListMembers(ctx, req.Msg.GetOrganizationId())
+	if err != nil {
+		return nil, memberError(err)
+	}
+
+	resp := &acctv1.ListMembersResponse{}
+	for i := range members {
+		resp.Members = append(resp.Members, memberToProto(&members[i]))
+	}
+	for i := range invitations {
+		resp.PendingInvitations = append(resp.PendingInvitations, invitationToProto(&invitations[i]))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *accountHandler) RemoveMember(ctx context.Context, req *connect.Request[acctv1.RemoveMemberRequest]) (*connect.Response[acctv1.RemoveMemberResponse], error) {
+	r := req.Msg
+
+	err := h.svc.RemoveMember(ctx, r.GetOrganizationId(), r.GetEmail())
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err, "member/"+r.GetEmail())
+	if err != nil {
+		return nil, memberError(err)
+	}
+
+	return connect.NewResponse(&acctv1.RemoveMemberResponse{
+		OrganizationId: r.GetOrganizationId(),
+		Email:          r.GetEmail(),
+		RemovedAt:      timestamppb.New(time.Now()),
+	}), nil
+}
+
+func memberToProto(r *storage.MemberRecord) *acctv1.Member {
+	return &acctv1.Member{
+		OrganizationId: r.OrganizationID,
+		Email:          r.Email,
+		Persona:        r.Persona,
+		Subject:        r.Subject,
+		InvitedBy:      r.InvitedBy,
+		AddedAt:        timestamppb.New(r.AddedAt),
+	}
+}
+
+func invitationToProto(r *storage.InvitationRecord) *acctv1.Invitation {
+	return &acctv1.Invitation{
+		InvitationId:   r.ID,
+		OrganizationId: r.OrganizationID,
+		Email:          r.Email,
+		Persona:        r.Persona,
+		InvitedBy:      r.InvitedBy,
+		CreatedAt:      timestamppb.New(r.CreatedAt),
+		ExpiresAt:      timestamppb.New(r.ExpiresAt),
+	}
+}
+
+// memberError maps invitation and membership failures to Connect codes.
+func memberError(err error) error {
+	switch {
+	case errors.Is(err, accountservice.ErrInvalidMember):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, accountservice.ErrMemberExists):
+		return connect.NewError(connect.CodeAlreadyExists, err)
+	case errors.Is(err, accountservice.ErrInvalidInvitation):
+		return connect.NewError(connect.CodePermissionDenied, err)
+	case errors.Is(err, accountservice.ErrInvitationsDisabled):
+		return connect.NewError(connect.CodeFailedPrecondition, err)
+	}
+	return accountError(err)
+}
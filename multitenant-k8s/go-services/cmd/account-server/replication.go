@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/accountservice"
+)
+
+func (h *accountHandler) GetReplicationStatus(ctx context.Context, req *connect.Request[acctv1.GetReplicationStatusRequest]) (*connect.Response[acctv1.GetReplicationStatusResponse], error) {
+	statuses, err := h.svc.ReplicationStatuses(ctx, req.Msg.GetOrganizationId())
+	if err != nil {
+		return nil, replicationError(err)
+	}
+
+	resp := &acctv1.GetReplicationStatusResponse{}
+	for _, st := range statuses {
+		tenant := &acctv1.TenantReplication{
+			OrganizationId: st.OrganizationID,
+			StandbyCluster: st.StandbyCluster,
+			LagSeconds:     int64(st.Lag.Seconds()),
+			Error:          st.LastError,
+		}
+		if !st.LastSyncedAt.IsZero() {
+			tenant.LastSyncedAt = timestamppb.New(st.LastSyncedAt)
+		}
+		if !st.FailedOverAt.IsZero() {
+			tenant.FailedOverAt = timestamppb.New(st.FailedOverAt)
+		}
+		resp.Tenants = append(resp.Tenants, tenant)
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *accountHandler) FailoverAccount(ctx context.Context, req *connect.Request[acctv1.FailoverAccountRequest]) (*connect.Response[acctv1.FailoverAccountResponse], error) {
+	r := req.Msg
+	record, err := h.svc.FailoverAccount(ctx, r.GetOrganizationId())
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err)
+	if err != nil {
+		return nil, replicationError(err)
+	}
+
+	resp := &acctv1.FailoverAccountResponse{
+		OrganizationId: record.OrganizationID,
+		Cluster:        record.Cluster,
+		IamRoleArn:     record.IAMRoleARN,
+		FailedOverAt:   timestamppb.New(record.UpdatedAt),
+	}
+	return connect.NewResponse(resp), nil
+}
+
+// replicationError maps a missing standby or replica to FailedPrecondition.
+func replicationError(err error) error {
+	if errors.Is(err, accountservice.ErrReplicationDisabled) || errors.Is(err, accountservice.ErrNotReplicated) ||
+		errors.Is(err, accountservice.ErrGitOpsUnsupported) {
+		return connect.NewError(connect.CodeFailedPrecondition, err)
+	}
+	return accountError(err)
+}
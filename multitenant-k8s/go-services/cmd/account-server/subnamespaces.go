@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/accountservice"
+)
+
+func (h *accountHandler) CreateSubNamespace(ctx context.Context, req *connect.Request[acctv1.CreateSubNamespaceRequest]) (*connect.Response[acctv1.CreateSubNamespaceResponse], error) {
+	r := req.Msg
+	sub, err := h.svc.CreateSubNamespace(ctx, r.GetOrganizationId(), r.GetName())
+	if err != nil {
+		h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err)
+		switch {
+		case errors.Is(err, accountservice.ErrInvalidSubNamespace):
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		case errors.Is(err, accountservice.ErrSubNamespaceExists):
+			return nil, connect.NewError(connect.CodeAlreadyExists, err)
+		case errors.Is(err, accountservice.ErrGitOpsUnsupported):
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		}
+		return nil, accountError(err)
+	}
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, nil, "namespace/"+sub.Namespace)
+
+	return connect.NewResponse(&acctv1.CreateSubNamespaceResponse{SubNamespace: subNamespaceToProto(*sub)}), nil
+}
+
+func (h *accountHandler) ListSubNamespaces(ctx context.Context, req *connect.Request[acctv1.ListSubNamespacesRequest]) (*connect.Response[acctv1.ListSubNamespacesResponse], error) {
+	subs, err := h.svc.ListSubNamespaces(ctx, req.Msg.GetOrganizationId())
+	if err != nil {
+		return nil, accountError(err)
+	}
+
+	resp := &acctv1.ListSubNamespacesResponse{}
+	for _, sub := range subs {
+		resp.SubNamespaces = append(resp.SubNamespaces, subNamespaceToProto(sub))
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func subNamespaceToProto(sub accountservice.SubNamespace) *acctv1.SubNamespace {
+	return &acctv1.SubNamespace{
+		Name:          sub.Name,
+		Namespace:     sub.Namespace,
+		ResourceQuota: sub.Quota,
+		CreatedAt:     timestamppb.New(sub.CreatedAt),
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/accountservice"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/templates"
+)
+
+func (h *accountHandler) ListTemplates(ctx context.Context, req *connect.Request[acctv1.ListTemplatesRequest]) (*connect.Response[acctv1.ListTemplatesResponse], error) {
+	list, err := h.svc.ListTemplates()
+	if err != nil {
+		return nil, templateError(err)
+	}
+
+	resp := &acctv1.ListTemplatesResponse{}
+	for _, t := range list {
+		resp.Templates = append(resp.Templates, &acctv1.EnvironmentTemplate{
+			Name:        t.Name,
+			Version:     t.Version,
+			Description: t.Description,
+			Chart:       t.Chart,
+		})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *accountHandler) UpgradeTemplate(ctx context.Context, req *connect.Request[acctv1.UpgradeTemplateRequest]) (*connect.Response[acctv1.UpgradeTemplateResponse], error) {
+	r := req.Msg
+
+	record, previous, err := h.svc.UpgradeTemplate(ctx, r.GetOrganizationId(), r.GetTemplate())
+	h.audit.RecordRPC(ctx, req.Spec().Procedure, r.GetOrganizationId(), r, err)
+	if err != nil {
+		return nil, templateError(err)
+	}
+
+	return connect.NewResponse(&acctv1.UpgradeTemplateResponse{
+		OrganizationId:  record.OrganizationID,
+		Template:        appliedTemplateToProto(record.Template),
+		PreviousVersion: previous,
+	}), nil
+}
+
+// templateError maps template errors to their codes and everything else as accountError does.
+func templateError(err error) error {
+	switch {
+	case errors.Is(err, templates.ErrUnknownTemplate):
+		return connect.NewError(connect.CodeInvalidArgument, err)
+	case errors.Is(err, accountservice.ErrTemplatesDisabled), errors.Is(err, accountservice.ErrNoTemplate):
+		return connect.NewError(connect.CodeFailedPrecondition, err)
+	}
+	return accountError(err)
+}
+
+func appliedTemplateToProto(t *storage.AppliedTemplate) *acctv1.AppliedTemplate {
+	if t == nil {
+		return nil
+	}
+	return &acctv1.AppliedTemplate{
+		Name:      t.Name,
+		Version:   t.Version,
+		AppliedAt: timestamppb.New(t.AppliedAt),
+	}
+}
@@ -0,0 +1,36 @@
+// Command fake-billing serves an in-memory imitation of the billing API for
+// local development. Point the account server at it with
+//
+//	BILLING_API_URL=http://localhost:12111 BILLING_API_KEY=sk_test_fake
+//
+// and use the same BILLING_WEBHOOK_SECRET on both sides. Subscription changes
+// are posted to FAKE_BILLING_WEBHOOK_URL; simulate a failed payment with
+//
+//	curl -d status=past_due localhost:12111/fake/subscriptions/<id>
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/billing"
+)
+
+func main() {
+	webhookURL := envOrDefault("FAKE_BILLING_WEBHOOK_URL", "http://localhost:8080/webhooks/billing")
+	server := billing.NewFakeServer(webhookURL, os.Getenv("BILLING_WEBHOOK_SECRET"))
+
+	addr := ":" + envOrDefault("FAKE_BILLING_PORT", "12111")
+	log.Printf("fake billing API listening on %s, sending webhooks to %s", addr, webhookURL)
+	if err := http.ListenAndServe(addr, server); err != nil {
+		log.Fatalf("server error: %v", err)
+	}
+}
+
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
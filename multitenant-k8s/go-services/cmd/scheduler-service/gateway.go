@@ -0,0 +1,24 @@
+package main
+
+import (
+	"net/http"
+
+	schedv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/mcp-scheduler/v1"
+	schedconnect "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/mcp-scheduler/v1/mcpschedulerv1connect"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/gateway"
+)
+
+// restRoutes maps the REST/JSON API (see api/example-calls.js) onto the
+// MCPJobService procedures. Job statuses use short names ("running").
+var restRoutes = []gateway.Route{
+	{Method: http.MethodPost, Path: "/api/mcp/jobs", Procedure: schedconnect.MCPJobServiceCreateJobProcedure,
+		Request: &schedv1.CreateJobRequest{}, Response: &schedv1.CreateJobResponse{}, Summary: "Submit an MCP job"},
+	{Method: http.MethodGet, Path: "/api/mcp/jobs", Procedure: schedconnect.MCPJobServiceListJobsProcedure,
+		Request: &schedv1.ListJobsRequest{}, Response: &schedv1.ListJobsResponse{}, Summary: "List an organization's jobs"},
+	{Method: http.MethodGet, Path: "/api/mcp/jobs/{job_id}", Procedure: schedconnect.MCPJobServiceGetJobProcedure,
+		Request: &schedv1.GetJobRequest{}, Response: &schedv1.GetJobResponse{}, Summary: "Get a job"},
+	{Method: http.MethodPost, Path: "/api/mcp/jobs/{job_id}/cancel", Procedure: schedconnect.MCPJobServiceCancelJobProcedure,
+		Request: &schedv1.CancelJobRequest{}, Response: &schedv1.CancelJobResponse{}, Summary: "Cancel a job"},
+	{Method: http.MethodGet, Path: "/api/mcp/jobs/{job_id}/logs", Procedure: schedconnect.MCPJobServiceGetJobLogsProcedure,
+		Request: &schedv1.GetJobLogsRequest{}, Response: &schedv1.GetJobLogsResponse{}, Summary: "Get a job's logs"},
+}
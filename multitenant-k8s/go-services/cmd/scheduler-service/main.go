@@ -5,12 +5,18 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	connect "connectrpc.com/connect"
 
 	schedv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/mcp-scheduler/v1"
 	schedconnect "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/mcp-scheduler/v1/mcpschedulerv1connect"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/auth"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/gateway"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/health"
 	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/schedulerservice"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
 )
 
 // mcpJobHandler adapts schedulerservice.Service to the generated MCPJobService.
@@ -50,22 +56,103 @@ func main() {
 		log.Fatalf("failed to create scheduler service: %v", err)
 	}
 
+	backend, err := storage.NewBackendFromEnv()
+	if err != nil {
+		log.Fatalf("failed to open storage backend: %v", err)
+	}
+
+	// Jobs run in the tenant's namespace at its plan tier's priority.
+	tenants, err := schedulerservice.NewAccountDirectory(storage.NewAccountStore(backend))
+	if err != nil {
+		log.Fatalf("failed to create tenant directory: %v", err)
+	}
+	svc.SetTenantDirectory(tenants)
+
 	handler := &mcpJobHandler{svc: svc}
 
+	// Tenants may only schedule jobs for their own organization. Users sign in
+	// with JWTs; tenant backends use API keys issued by the account service.
+	authn, err := newAuthenticator(backend)
+	if err != nil {
+		log.Fatalf("failed to configure authentication: %v", err)
+	}
+	policy := auth.Policy{Scopes: map[string]string{
+		schedconnect.MCPJobServiceCreateJobProcedure:  auth.ScopeJobsWrite,
+		schedconnect.MCPJobServiceCancelJobProcedure:  auth.ScopeJobsWrite,
+		schedconnect.MCPJobServiceGetJobProcedure:     auth.ScopeJobsRead,
+		schedconnect.MCPJobServiceListJobsProcedure:   auth.ScopeJobsRead,
+		schedconnect.MCPJobServiceGetJobLogsProcedure: auth.ScopeJobsRead,
+	}}
+
 	mux := http.NewServeMux()
-	path, hnd := schedconnect.NewMCPJobServiceHandler(handler)
+	path, hnd := schedconnect.NewMCPJobServiceHandler(handler,
+		connect.WithInterceptors(auth.NewInterceptor(authn, policy)),
+	)
 	mux.Handle(path, hnd)
 
+	rest, err := gateway.New("MCP Job API", "v1", hnd, restRoutes)
+	if err != nil {
+		log.Fatalf("failed to create REST gateway: %v", err)
+	}
+	rest.Register(mux)
+
+	// Jobs cannot be scheduled without Redis (locks, throttling, API keys) and Kafka.
+	probes := []health.Probe{health.KafkaProbe(splitBrokers(os.Getenv("KAFKA_BROKERS")), os.Getenv("KAFKA_TOPIC"))}
+	if redisBackend, ok := backend.(*storage.RedisBackend); ok {
+		probes = append(probes, health.RedisProbe(redisBackend.Client()))
+	}
+	checker, err := health.NewChecker(3*time.Second, probes...)
+	if err != nil {
+		log.Fatalf("failed to create health checker: %v", err)
+	}
+	checker.Register(mux, schedconnect.MCPJobServiceName)
+
 	addr := ":" + envOrDefault("SCHEDULER_SERVER_PORT", "8081")
 	log.Printf("MCPJobService scheduler listening on %s", addr)
-	if err := http.ListenAndServe(addr, mux); err != nil {
+	// HTTP/2 without TLS too, which gRPC clients such as grpc_health_probe
+	// and kubelet grpc probes require
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	server := &http.Server{Addr: addr, Handler: mux, Protocols: protocols}
+	if err := server.ListenAndServe(); err != nil {
 		log.Fatalf("server error: %v", err)
 	}
 }
 
+// newAuthenticator accepts API keys from the shared account storage and, when
+// AUTH_ISSUER, AUTH_JWKS_URL or AUTH_STATIC_SECRET is set, user JWTs.
+func newAuthenticator(backend storage.Backend) (auth.Authenticator, error) {
+	apiKeys, err := auth.NewAPIKeyAuthenticator(storage.NewAPIKeyStore(backend))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := auth.ConfigFromEnv()
+	if cfg.Issuer == "" && cfg.JWKSURL == "" && cfg.StaticSecret == "" {
+		log.Printf("no JWT issuer configured; accepting API keys only")
+		return auth.Tokens(nil, apiKeys), nil
+	}
+	verifier, err := auth.NewVerifier(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+	return auth.Tokens(verifier, apiKeys), nil
+}
+
 func envOrDefault(key, def string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
 	}
 	return def
 }
+
+func splitBrokers(v string) []string {
+	var brokers []string
+	for _, b := range strings.Split(v, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			brokers = append(brokers, b)
+		}
+	}
+	return brokers
+}
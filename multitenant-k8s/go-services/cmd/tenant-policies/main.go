@@ -0,0 +1,31 @@
+// Command tenant-policies prints the admission policies that back tenant
+// isolation, for the engine installed in the cluster:
+//
+//	go run ./cmd/tenant-policies -engine kyverno | kubectl apply -f -
+//	go run ./cmd/tenant-policies -engine vap | kubectl apply -f -
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/policy"
+)
+
+func main() {
+	engine := flag.String("engine", policy.EngineVAP, "admission engine: kyverno or vap")
+	flag.Parse()
+
+	objects, err := policy.Generate(*engine)
+	if err != nil {
+		log.Fatal(err)
+	}
+	out, err := policy.YAML(objects)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := os.Stdout.Write(out); err != nil {
+		log.Fatal(err)
+	}
+}
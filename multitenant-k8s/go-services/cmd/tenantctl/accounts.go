@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+)
+
+var accountCommands = map[string]command{
+	"create": {"provision a tenant account", accountCreate},
+	"get":    {"show one account", accountGet},
+	"list":   {"list accounts", accountList},
+	"update": {"change an account's plan tier or organization type", accountUpdate},
+	"delete": {"deprovision an account and its resources", accountDelete},
+	"sync":   {"create, update and delete accounts to match a directory of tenant specs", accountSync},
+
+	"templates":        {"list environment templates", accountTemplates},
+	"upgrade-template": {"re-apply an account's environment template at its current version", accountUpgradeTemplate},
+}
+
+var accountHeaders = []string{"ORGANIZATION", "NAMESPACE", "CLUSTER", "TYPE", "TIER", "STATUS", "CREATED"}
+
+func accountCreate(ctx context.Context, c *cli, args []string) error {
+	fs := c.outputFlagSet("account create", "")
+	org := fs.String("org", "", "organization id")
+	tier := fs.String("tier", "free", "plan tier: free, starter, pro or enterprise")
+	orgType := fs.String("type", "namespace", "organization type: namespace, node or cluster")
+	bucket := fs.String("bucket", "", "S3 bucket (server default if empty)")
+	region := fs.String("region", "", "restrict placement to clusters in this region")
+	registry := fs.Bool("registry", false, "give the tenant a private container registry")
+	jobTopic := fs.Bool("job-topic", false, "queue the tenant's jobs on a dedicated Kafka topic")
+	withIngress := fs.Bool("ingress", false, "serve the tenant's <org>.apps subdomain")
+	template := fs.String("template", "", "seed the namespace from this environment template")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	if *org == "" {
+		return errors.New("-org is required")
+	}
+	planTier, err := parsePlanTier(*tier)
+	if err != nil {
+		return err
+	}
+	organizationType, err := parseOrganizationType(*orgType)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.accounts.CreateAccount(ctx, connect.NewRequest(&acctv1.CreateAccountRequest{
+		OrganizationId:    *org,
+		OrganizationType:  organizationType,
+		PlanTier:          planTier,
+		S3Bucket:          *bucket,
+		Region:            *region,
+		ContainerRegistry: *registry,
+		DedicatedJobTopic: *jobTopic,
+		Ingress:           *withIngress,
+		Template:          *template,
+	}))
+	if err != nil {
+		return err
+	}
+	m := resp.Msg
+	return c.print(m, table{
+		headers: accountHeaders,
+		rows: [][]string{{
+			m.GetOrganizationId(), m.GetNamespace(), orDash(m.GetCluster()),
+			enumName(m.GetOrganizationType(), "ORGANIZATION_TYPE_"), enumName(m.GetPlanTier(), "PLAN_TIER_"),
+			m.GetStatus(), formatTime(m.GetCreatedAt()),
+		}},
+	})
+}
+
+func accountGet(ctx context.Context, c *cli, args []string) error {
+	pos, err := parseArgs(c.outputFlagSet("account get", "<organization-id>"), args, 1)
+	if err != nil {
+		return err
+	}
+	resp, err := c.accounts.GetAccount(ctx, connect.NewRequest(&acctv1.GetAccountRequest{OrganizationId: pos[0]}))
+	if err != nil {
+		return err
+	}
+	return c.print(resp.Msg, table{headers: accountHeaders, rows: [][]string{accountRow(resp.Msg)}})
+}
+
+func accountList(ctx context.Context, c *cli, args []string) error {
+	fs := c.outputFlagSet("account list", "")
+	status := fs.String("status", "", "only accounts with this status, e.g. ACTIVE or SUSPENDED")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	resp, err := c.accounts.ListAccounts(ctx, connect.NewRequest(&acctv1.ListAccountsRequest{StatusFilter: *status}))
+	if err != nil {
+		return err
+	}
+	t := table{headers: accountHeaders}
+	for _, a := range resp.Msg.GetAccounts() {
+		t.rows = append(t.rows, accountRow(a))
+	}
+	return c.print(resp.Msg, t)
+}
+
+func accountUpdate(ctx context.Context, c *cli, args []string) error {
+	fs := c.outputFlagSet("account update", "<organization-id>")
+	tier := fs.String("tier", "", "new plan tier")
+	orgType := fs.String("type", "", "new organization type")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *tier == "" && *orgType == "" {
+		return errors.New("nothing to update: set -tier and/or -type")
+	}
+	req := &acctv1.UpdateAccountRequest{OrganizationId: pos[0]}
+	if *tier != "" {
+		if req.PlanTier, err = parsePlanTier(*tier); err != nil {
+			return err
+		}
+	}
+	if *orgType != "" {
+		if req.OrganizationType, err = parseOrganizationType(*orgType); err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.accounts.UpdateAccount(ctx, connect.NewRequest(req))
+	if err != nil {
+		return err
+	}
+	m := resp.Msg
+	return c.print(m, table{
+		headers: []string{"ORGANIZATION", "TIER", "UPDATED"},
+		rows:    [][]string{{m.GetOrganizationId(), enumName(m.GetPlanTier(), "PLAN_TIER_"), formatTime(m.GetUpdatedAt())}},
+	})
+}
+
+func accountDelete(ctx context.Context, c *cli, args []string) error {
+	fs := c.outputFlagSet("account delete", "<organization-id>")
+	yes := fs.Bool("yes", false, "confirm deletion")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if !*yes {
+		return fmt.Errorf("deleting %s removes its namespace, IAM role and data; re-run with -yes to confirm", pos[0])
+	}
+	resp, err := c.accounts.DeleteAccount(ctx, connect.NewRequest(&acctv1.DeleteAccountRequest{OrganizationId: pos[0]}))
+	if err != nil {
+		return err
+	}
+	m := resp.Msg
+	return c.print(m, table{
+		headers: []string{"ORGANIZATION", "STATUS", "DELETED"},
+		rows:    [][]string{{m.GetOrganizationId(), m.GetStatus(), formatTime(m.GetDeletedAt())}},
+	})
+}
+
+func accountTemplates(ctx context.Context, c *cli, args []string) error {
+	if _, err := parseArgs(c.outputFlagSet("account templates", ""), args, 0); err != nil {
+		return err
+	}
+	resp, err := c.accounts.ListTemplates(ctx, connect.NewRequest(&acctv1.ListTemplatesRequest{}))
+	if err != nil {
+		return err
+	}
+	t := table{headers: []string{"NAME", "VERSION", "CHART", "DESCRIPTION"}}
+	for _, tmpl := range resp.Msg.GetTemplates() {
+		t.rows = append(t.rows, []string{tmpl.GetName(), tmpl.GetVersion(), orDash(tmpl.GetChart()), orDash(tmpl.GetDescription())})
+	}
+	return c.print(resp.Msg, t)
+}
+
+func accountUpgradeTemplate(ctx context.Context, c *cli, args []string) error {
+	fs := c.outputFlagSet("account upgrade-template", "<organization-id>")
+	template := fs.String("template", "", "switch to this template instead of upgrading the installed one")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	resp, err := c.accounts.UpgradeTemplate(ctx, connect.NewRequest(&acctv1.UpgradeTemplateRequest{
+		OrganizationId: pos[0],
+		Template:       *template,
+	}))
+	if err != nil {
+		return err
+	}
+	m := resp.Msg
+	return c.print(m, table{
+		headers: []string{"ORGANIZATION", "TEMPLATE", "PREVIOUS", "VERSION", "APPLIED"},
+		rows: [][]string{{
+			m.GetOrganizationId(), m.GetTemplate().GetName(), orDash(m.GetPreviousVersion()),
+			m.GetTemplate().GetVersion(), formatTime(m.GetTemplate().GetAppliedAt()),
+		}},
+	})
+}
+
+func accountRow(a *acctv1.GetAccountResponse) []string {
+	return []string{
+		a.GetOrganizationId(), a.GetNamespace(), orDash(a.GetCluster()),
+		enumName(a.GetOrganizationType(), "ORGANIZATION_TYPE_"), enumName(a.GetPlanTier(), "PLAN_TIER_"),
+		a.GetStatus(), formatTime(a.GetCreatedAt()),
+	}
+}
+
+func parsePlanTier(s string) (acctv1.PlanTier, error) {
+	v, err := parseEnum(acctv1.PlanTier_value, "PLAN_TIER_", s)
+	if err != nil {
+		return 0, fmt.Errorf("plan tier: %w", err)
+	}
+	return acctv1.PlanTier(v), nil
+}
+
+func parseOrganizationType(s string) (acctv1.OrganizationType, error) {
+	v, err := parseEnum(acctv1.OrganizationType_value, "ORGANIZATION_TYPE_", s)
+	if err != nil {
+		return 0, fmt.Errorf("organization type: %w", err)
+	}
+	return acctv1.OrganizationType(v), nil
+}
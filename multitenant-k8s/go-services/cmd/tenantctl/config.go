@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"connectrpc.com/connect"
+	"sigs.k8s.io/yaml"
+
+	acctconnect "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1/acctmanagementv1connect"
+	schedconnect "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/mcp-scheduler/v1/mcpschedulerv1connect"
+)
+
+const (
+	defaultAccountEndpoint   = "http://localhost:8080"
+	defaultSchedulerEndpoint = "http://localhost:8081"
+)
+
+// Config is the tenantctl config file
+type Config struct {
+	CurrentProfile string             `json:"current_profile,omitempty"`
+	Profiles       map[string]Profile `json:"profiles,omitempty"`
+}
+
+// Profile holds the endpoints and credentials for one environment
+type Profile struct {
+	AccountEndpoint   string `json:"account_endpoint,omitempty"`
+	SchedulerEndpoint string `json:"scheduler_endpoint,omitempty"`
+	Token             string `json:"token,omitempty"`           // JWT or mcpk_ API key
+	TokenEnv          string `json:"token_env,omitempty"`       // Read the token from this variable instead
+	OrganizationID    string `json:"organization_id,omitempty"` // Default organization for job commands
+}
+
+// cli is the state shared by all subcommands
+type cli struct {
+	configPath  string
+	config      *Config
+	profileName string
+	profile     Profile
+	format      string
+
+	accounts acctconnect.AccountProvisioningServiceClient
+	jobs     schedconnect.MCPJobServiceClient
+}
+
+func newCLI(configPath, profileName, format string) (*cli, error) {
+	if err := checkFormat(format); err != nil {
+		return nil, err
+	}
+	if configPath == "" {
+		configPath = os.Getenv("TENANTCTL_CONFIG")
+	}
+	if configPath == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate config dir: %w", err)
+		}
+		configPath = filepath.Join(dir, "tenantctl", "config.yaml")
+	}
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if profileName == "" {
+		profileName = os.Getenv("TENANTCTL_PROFILE")
+	}
+	if profileName == "" {
+		profileName = config.CurrentProfile
+	}
+	var profile Profile
+	if profileName != "" {
+		p, ok := config.Profiles[profileName]
+		if !ok {
+			return nil, fmt.Errorf("profile %q not found in %s", profileName, configPath)
+		}
+		profile = p
+	}
+
+	c := &cli{
+		configPath:  configPath,
+		config:      config,
+		profileName: profileName,
+		profile:     profile,
+		format:      format,
+	}
+	c.accounts = acctconnect.NewAccountProvisioningServiceClient(http.DefaultClient,
+		c.endpoint(profile.AccountEndpoint, "TENANTCTL_ACCOUNT_ENDPOINT", defaultAccountEndpoint),
+		connect.WithInterceptors(bearerInterceptor(c.token())))
+	c.jobs = schedconnect.NewMCPJobServiceClient(http.DefaultClient,
+		c.endpoint(profile.SchedulerEndpoint, "TENANTCTL_SCHEDULER_ENDPOINT", defaultSchedulerEndpoint),
+		connect.WithInterceptors(bearerInterceptor(c.token())))
+	return c, nil
+}
+
+// endpoint resolves an endpoint from the environment, the profile or the default
+func (c *cli) endpoint(fromProfile, env, def string) string {
+	if v := os.Getenv(env); v != "" {
+		return v
+	}
+	if fromProfile != "" {
+		return fromProfile
+	}
+	return def
+}
+
+// token resolves the bearer token; TENANTCTL_TOKEN wins over the profile
+func (c *cli) token() string {
+	if v := os.Getenv("TENANTCTL_TOKEN"); v != "" {
+		return v
+	}
+	if c.profile.TokenEnv != "" {
+		return os.Getenv(c.profile.TokenEnv)
+	}
+	return c.profile.Token
+}
+
+// organization returns the flag value or the profile's default organization
+func (c *cli) organization(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if c.profile.OrganizationID != "" {
+		return c.profile.OrganizationID, nil
+	}
+	return "", errors.New("-org is required (or set organization_id on the profile)")
+}
+
+// bearerInterceptor sends the token as an Authorization header on every call
+func bearerInterceptor(token string) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if token != "" {
+				req.Header().Set("Authorization", "Bearer "+token)
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+func loadConfig(path string) (*Config, error) {
+	config := &Config{Profiles: map[string]Profile{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if config.Profiles == nil {
+		config.Profiles = map[string]Profile{}
+	}
+	return config, nil
+}
+
+// saveConfig writes the config owner-only, since profiles may hold tokens
+func (c *cli) saveConfig() error {
+	data, err := yaml.Marshal(c.config)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.configPath), 0o700); err != nil {
+		return fmt.Errorf("failed to create config dir: %w", err)
+	}
+	if err := os.WriteFile(c.configPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
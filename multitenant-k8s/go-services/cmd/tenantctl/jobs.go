@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"connectrpc.com/connect"
+
+	schedv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/mcp-scheduler/v1"
+)
+
+var jobCommands = map[string]command{
+	"create": {"submit an MCP job", jobCreate},
+	"get":    {"show one job", jobGet},
+	"list":   {"list an organization's jobs", jobList},
+	"cancel": {"cancel a pending or running job", jobCancel},
+	"logs":   {"print a job's logs", jobLogs},
+}
+
+var jobHeaders = []string{"JOB", "ORGANIZATION", "TYPE", "STATUS", "POD", "CREATED"}
+
+// params collects repeated -param key=value flags
+type params map[string]string
+
+func (p params) String() string { return fmt.Sprint(map[string]string(p)) }
+
+func (p params) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+	p[k] = v
+	return nil
+}
+
+func jobCreate(ctx context.Context, c *cli, args []string) error {
+	fs := c.outputFlagSet("job create", "")
+	org := fs.String("org", "", "organization id (default the profile's)")
+	jobType := fs.String("type", "", "job type")
+	prompt := fs.String("prompt", "", "prompt for the MCP automation")
+	payload := fs.String("payload", "", "JSON payload, or @file to read it from a file")
+	timeout := fs.Int("timeout", 0, "timeout in seconds (server default if 0)")
+	callback := fs.String("callback", "", "URL notified when the job finishes")
+	parameters := params{}
+	fs.Var(parameters, "param", "job parameter key=value (repeatable)")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	orgID, err := c.organization(*org)
+	if err != nil {
+		return err
+	}
+	if *jobType == "" {
+		return errors.New("-type is required")
+	}
+	body := *payload
+	if path, ok := strings.CutPrefix(body, "@"); ok {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read payload: %w", err)
+		}
+		body = string(data)
+	}
+
+	resp, err := c.jobs.CreateJob(ctx, connect.NewRequest(&schedv1.CreateJobRequest{
+		OrganizationId: orgID,
+		JobType:        *jobType,
+		Prompt:         *prompt,
+		Parameters:     parameters,
+		Payload:        body,
+		TimeoutSeconds: int32(*timeout),
+		CallbackUrl:    *callback,
+	}))
+	if err != nil {
+		return err
+	}
+	m := resp.Msg
+	return c.print(m, table{
+		headers: []string{"JOB", "ORGANIZATION", "STATUS", "NAMESPACE", "CREATED"},
+		rows: [][]string{{
+			m.GetJobId(), m.GetOrganizationId(), enumName(m.GetStatus(), "JOB_STATUS_"),
+			orDash(m.GetNamespace()), formatTime(m.GetCreatedAt()),
+		}},
+	})
+}
+
+func jobGet(ctx context.Context, c *cli, args []string) error {
+	pos, err := parseArgs(c.outputFlagSet("job get", "<job-id>"), args, 1)
+	if err != nil {
+		return err
+	}
+	resp, err := c.jobs.GetJob(ctx, connect.NewRequest(&schedv1.GetJobRequest{JobId: pos[0]}))
+	if err != nil {
+		return err
+	}
+	return c.print(resp.Msg, table{headers: jobHeaders, rows: [][]string{jobRow(resp.Msg)}})
+}
+
+func jobList(ctx context.Context, c *cli, args []string) error {
+	fs := c.outputFlagSet("job list", "")
+	org := fs.String("org", "", "organization id (default the profile's)")
+	status := fs.String("status", "", "only jobs with this status, e.g. running or failed")
+	limit := fs.Int("limit", 0, "maximum number of jobs (server default if 0)")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	orgID, err := c.organization(*org)
+	if err != nil {
+		return err
+	}
+	req := &schedv1.ListJobsRequest{OrganizationId: orgID, PageSize: int32(*limit)}
+	if *status != "" {
+		v, err := parseEnum(schedv1.JobStatus_value, "JOB_STATUS_", *status)
+		if err != nil {
+			return fmt.Errorf("job status: %w", err)
+		}
+		req.StatusFilter = schedv1.JobStatus(v)
+	}
+
+	resp, err := c.jobs.ListJobs(ctx, connect.NewRequest(req))
+	if err != nil {
+		return err
+	}
+	t := table{headers: jobHeaders}
+	for _, j := range resp.Msg.GetJobs() {
+		t.rows = append(t.rows, jobRow(j))
+	}
+	return c.print(resp.Msg, t)
+}
+
+func jobCancel(ctx context.Context, c *cli, args []string) error {
+	pos, err := parseArgs(c.outputFlagSet("job cancel", "<job-id>"), args, 1)
+	if err != nil {
+		return err
+	}
+	resp, err := c.jobs.CancelJob(ctx, connect.NewRequest(&schedv1.CancelJobRequest{JobId: pos[0]}))
+	if err != nil {
+		return err
+	}
+	m := resp.Msg
+	return c.print(m, table{
+		headers: []string{"JOB", "STATUS", "CANCELLED"},
+		rows:    [][]string{{m.GetJobId(), enumName(m.GetStatus(), "JOB_STATUS_"), formatTime(m.GetCancelledAt())}},
+	})
+}
+
+func jobLogs(ctx context.Context, c *cli, args []string) error {
+	fs := c.outputFlagSet("job logs", "<job-id>")
+	tail := fs.Int("tail", 0, "only the last N lines (all if 0)")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	resp, err := c.jobs.GetJobLogs(ctx, connect.NewRequest(&schedv1.GetJobLogsRequest{JobId: pos[0], TailLines: int32(*tail)}))
+	if err != nil {
+		return err
+	}
+	// Logs are printed raw in table mode so they can be piped to grep and friends
+	if c.format == formatTable {
+		_, err := fmt.Fprint(os.Stdout, resp.Msg.GetLogs())
+		return err
+	}
+	return c.print(resp.Msg, table{})
+}
+
+func jobRow(j *schedv1.GetJobResponse) []string {
+	return []string{
+		j.GetJobId(), j.GetOrganizationId(), j.GetJobType(), enumName(j.GetStatus(), "JOB_STATUS_"),
+		orDash(j.GetPodName()), formatTime(j.GetCreatedAt()),
+	}
+}
@@ -0,0 +1,135 @@
+// Command tenantctl is the operator CLI for the account provisioning and MCP
+// job services. It talks to both servers through the generated Connect clients:
+//
+//	tenantctl profile set prod -account-endpoint https://accounts.example.com -token-env PROD_TOKEN
+//	tenantctl account create -org acme -tier pro
+//	tenantctl account get acme -o yaml
+//	tenantctl job create -org acme -type scrape -prompt "..."
+//	tenantctl job logs <job-id> -tail 100
+//	tenantctl account sync -plan tenants/
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+)
+
+// command is a leaf subcommand such as "account get"
+type command struct {
+	usage string
+	run   func(ctx context.Context, c *cli, args []string) error
+}
+
+var groups = map[string]map[string]command{
+	"account": accountCommands,
+	"job":     jobCommands,
+	"profile": profileCommands,
+}
+
+func main() {
+	flag.Usage = usage
+	configPath := flag.String("config", "", "config file (default $TENANTCTL_CONFIG or <user config dir>/tenantctl/config.yaml)")
+	profile := flag.String("profile", "", "profile to use (default the config's current profile)")
+	output := flag.String("o", formatTable, "output format: table, json or yaml")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := groups[args[0]][args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "tenantctl: unknown command %q\n\n", strings.Join(args[:2], " "))
+		usage()
+		os.Exit(2)
+	}
+
+	c, err := newCLI(*configPath, *profile, *output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tenantctl: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := cmd.run(ctx, c, args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "tenantctl: %v\n", err)
+		if errors.Is(err, errDrift) {
+			os.Exit(exitDrift)
+		}
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: tenantctl [global flags] <group> <command> [flags] [args]\n\nCommands:\n")
+	for _, group := range sortedKeys(groups) {
+		for _, name := range sortedKeys(groups[group]) {
+			fmt.Fprintf(os.Stderr, "  %-16s %s\n", group+" "+name, groups[group][name].usage)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\nGlobal flags:\n")
+	flag.PrintDefaults()
+}
+
+// newFlagSet returns a flag set for a subcommand that prints its own usage
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: tenantctl %s [flags] %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// outputFlagSet is newFlagSet for a subcommand that prints a response. It
+// repeats the global -o flag so that the format can follow the command.
+func (c *cli) outputFlagSet(name, args string) *flag.FlagSet {
+	fs := newFlagSet(name, args)
+	fs.Func("o", "output format: table, json or yaml (overrides the global -o)", func(format string) error {
+		if err := checkFormat(format); err != nil {
+			return err
+		}
+		c.format = format
+		return nil
+	})
+	return fs
+}
+
+// parseArgs parses flags for a subcommand and requires exactly n positional arguments
+func parseArgs(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	// Accept flags after positional arguments too ("account get acme -o json" style)
+	var positional []string
+	rest := fs.Args()
+	for len(rest) > 0 {
+		positional = append(positional, rest[0])
+		if err := fs.Parse(rest[1:]); err != nil {
+			return nil, err
+		}
+		rest = fs.Args()
+	}
+	if len(positional) != n {
+		fs.Usage()
+		return nil, fmt.Errorf("%s: expected %d argument(s), got %d", fs.Name(), n, len(positional))
+	}
+	return positional, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
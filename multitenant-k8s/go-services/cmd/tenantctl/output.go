@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+// checkFormat rejects output formats print does not know
+func checkFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatYAML:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// table is the tabular rendering of a response
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+// print writes msg in the selected format; table output uses t
+func (c *cli) print(msg proto.Message, t table) error {
+	switch c.format {
+	case formatJSON, formatYAML:
+		data, err := protojson.MarshalOptions{Multiline: true, Indent: "  ", UseProtoNames: true}.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("failed to encode response: %w", err)
+		}
+		if c.format == formatYAML {
+			if data, err = yaml.JSONToYAML(data); err != nil {
+				return fmt.Errorf("failed to encode response: %w", err)
+			}
+		} else {
+			data = append(data, '\n')
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(t.headers, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+}
+
+// formatTime renders a timestamp for tables; unset timestamps print as "-"
+func formatTime(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return "-"
+	}
+	return ts.AsTime().Local().Format(time.RFC3339)
+}
+
+// orDash renders empty strings as "-" so table columns stay aligned
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// enumName strips the enum prefix, e.g. PLAN_TIER_PRO -> PRO
+func enumName(value fmt.Stringer, prefix string) string {
+	return strings.TrimPrefix(value.String(), prefix)
+}
+
+// parseEnum accepts "pro", "PRO" or "PLAN_TIER_PRO" for an enum with the given prefix
+func parseEnum(values map[string]int32, prefix, s string) (int32, error) {
+	name := strings.ToUpper(s)
+	if !strings.HasPrefix(name, prefix) {
+		name = prefix + name
+	}
+	v, ok := values[name]
+	if !ok || v == 0 {
+		var valid []string
+		for n, v := range values {
+			if v != 0 {
+				valid = append(valid, strings.ToLower(strings.TrimPrefix(n, prefix)))
+			}
+		}
+		return 0, fmt.Errorf("invalid value %q (one of %s)", s, strings.Join(sortStrings(valid), ", "))
+	}
+	return v, nil
+}
+
+func sortStrings(s []string) []string {
+	m := make(map[string]struct{}, len(s))
+	for _, v := range s {
+		m[v] = struct{}{}
+	}
+	return sortedKeys(m)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+var profileCommands = map[string]command{
+	"list":   {"list configured profiles", profileList},
+	"set":    {"create or update a profile", profileSet},
+	"use":    {"make a profile the default", profileUse},
+	"delete": {"remove a profile", profileDelete},
+}
+
+func profileList(_ context.Context, c *cli, args []string) error {
+	if _, err := parseArgs(newFlagSet("profile list", ""), args, 0); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CURRENT\tNAME\tACCOUNT ENDPOINT\tSCHEDULER ENDPOINT\tORGANIZATION\tCREDENTIALS")
+	for _, name := range sortedKeys(c.config.Profiles) {
+		p := c.config.Profiles[name]
+		current := ""
+		if name == c.config.CurrentProfile {
+			current = "*"
+		}
+		// Never print the token itself
+		creds := "-"
+		switch {
+		case p.TokenEnv != "":
+			creds = "$" + p.TokenEnv
+		case p.Token != "":
+			creds = "token"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", current, name,
+			orDash(p.AccountEndpoint), orDash(p.SchedulerEndpoint), orDash(p.OrganizationID), creds)
+	}
+	return w.Flush()
+}
+
+func profileSet(_ context.Context, c *cli, args []string) error {
+	fs := newFlagSet("profile set", "<name>")
+	accountEndpoint := fs.String("account-endpoint", "", "account server URL")
+	schedulerEndpoint := fs.String("scheduler-endpoint", "", "scheduler server URL")
+	token := fs.String("token", "", "JWT or API key stored in the config file")
+	tokenEnv := fs.String("token-env", "", "environment variable holding the token")
+	org := fs.String("org", "", "default organization for job commands")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	name := pos[0]
+
+	// Only the flags given on the command line change the profile
+	p := c.config.Profiles[name]
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "account-endpoint":
+			p.AccountEndpoint = *accountEndpoint
+		case "scheduler-endpoint":
+			p.SchedulerEndpoint = *schedulerEndpoint
+		case "token":
+			p.Token, p.TokenEnv = *token, ""
+		case "token-env":
+			p.TokenEnv, p.Token = *tokenEnv, ""
+		case "org":
+			p.OrganizationID = *org
+		}
+	})
+	c.config.Profiles[name] = p
+	if c.config.CurrentProfile == "" {
+		c.config.CurrentProfile = name
+	}
+	if err := c.saveConfig(); err != nil {
+		return err
+	}
+	fmt.Printf("Profile %q saved to %s\n", name, c.configPath)
+	return nil
+}
+
+func profileUse(_ context.Context, c *cli, args []string) error {
+	pos, err := parseArgs(newFlagSet("profile use", "<name>"), args, 1)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.config.Profiles[pos[0]]; !ok {
+		return fmt.Errorf("profile %q not found", pos[0])
+	}
+	c.config.CurrentProfile = pos[0]
+	if err := c.saveConfig(); err != nil {
+		return err
+	}
+	fmt.Printf("Switched to profile %q\n", pos[0])
+	return nil
+}
+
+func profileDelete(_ context.Context, c *cli, args []string) error {
+	pos, err := parseArgs(newFlagSet("profile delete", "<name>"), args, 1)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.config.Profiles[pos[0]]; !ok {
+		return fmt.Errorf("profile %q not found", pos[0])
+	}
+	delete(c.config.Profiles, pos[0])
+	if c.config.CurrentProfile == pos[0] {
+		c.config.CurrentProfile = ""
+	}
+	return c.saveConfig()
+}
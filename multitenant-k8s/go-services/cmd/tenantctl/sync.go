@@ -0,0 +1,479 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"connectrpc.com/connect"
+	"sigs.k8s.io/yaml"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+)
+
+// exitDrift is the exit status of a sync that leaves accounts differing from
+// their specs: a plan with changes, or changes that were skipped
+const exitDrift = 3
+
+// errDrift makes tenantctl exit with exitDrift
+var errDrift = errors.New("accounts differ from their specs")
+
+// tenantSpec is one tenant file of an `account sync` directory:
+//
+//	organization_id: acme
+//	tier: pro
+//	type: namespace
+//	bucket: acme-data
+//	members:
+//	  - email: alice@acme.com
+//	    persona: admin
+type tenantSpec struct {
+	OrganizationID   string       `json:"organization_id"`
+	Tier             string       `json:"tier"`
+	OrganizationType string       `json:"type,omitempty"`    // Default namespace
+	Bucket           string       `json:"bucket,omitempty"`  // Server default if empty
+	Members          []memberSpec `json:"members,omitempty"` // Omitted leaves membership unmanaged; [] removes every member
+
+	tier     acctv1.PlanTier
+	orgType  acctv1.OrganizationType
+	personas map[string]string // email -> persona
+}
+
+type memberSpec struct {
+	Email   string `json:"email"`
+	Persona string `json:"persona"`
+}
+
+// change is one step of a sync plan
+type change struct {
+	action string // create, update, delete, invite, remove-member, revoke-invitation or unsupported
+	org    string
+	detail string
+	skip   string                          // Why the change is not applied; empty to apply it
+	apply  func(ctx context.Context) error // nil for changes that cannot be applied
+}
+
+func accountSync(ctx context.Context, c *cli, args []string) error {
+	fs := newFlagSet("account sync", "<spec-dir>")
+	planOnly := fs.Bool("plan", false, "print the plan and exit without changing anything")
+	prune := fs.Bool("prune", false, "delete accounts that have no spec")
+	invitations := fs.String("invitations", "", "file to write the invitation tokens of invited members to (required to invite)")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	specs, err := loadTenantSpecs(pos[0])
+	if err != nil {
+		return err
+	}
+	tokens := &invitationTokens{}
+	plan, err := planSync(ctx, c, specs, *prune, tokens)
+	if err != nil {
+		return err
+	}
+
+	if len(plan) == 0 {
+		fmt.Println("No changes: every account matches its spec.")
+		return nil
+	}
+	if err := printPlan(plan, nil); err != nil {
+		return err
+	}
+	if *planOnly {
+		return errDrift
+	}
+
+	for _, ch := range plan {
+		if ch.action == "invite" && ch.skip == "" && *invitations == "" {
+			return errors.New("the plan invites members; set -invitations <file> to receive their tokens")
+		}
+	}
+
+	// Apply in plan order; a failed change skips the rest of its organization
+	fmt.Println()
+	results := make([]string, len(plan))
+	failed := map[string]bool{}
+	var errs, skipped int
+	for i, ch := range plan {
+		switch {
+		case ch.apply == nil || ch.skip != "":
+			results[i] = "skipped: " + orDash(ch.skip)
+			skipped++
+		case failed[ch.org]:
+			results[i] = "skipped: an earlier change failed"
+			skipped++
+		default:
+			if err := ch.apply(ctx); err != nil {
+				results[i] = "failed: " + err.Error()
+				failed[ch.org] = true
+				errs++
+			} else {
+				results[i] = "done"
+			}
+		}
+	}
+	printErr := printPlan(plan, results)
+
+	if len(tokens.entries) > 0 {
+		if err := tokens.write(*invitations); err != nil {
+			return err
+		}
+		fmt.Printf("\nWrote %d invitation token(s) to %s\n", len(tokens.entries), *invitations)
+	}
+	switch {
+	case printErr != nil:
+		return printErr
+	case errs > 0:
+		return fmt.Errorf("%d of %d changes failed", errs, len(plan))
+	case skipped > 0:
+		return fmt.Errorf("%d of %d changes skipped: %w", skipped, len(plan), errDrift)
+	}
+	return nil
+}
+
+// loadTenantSpecs reads every .yaml and .yml file under dir, one tenant per file
+func loadTenantSpecs(dir string) ([]*tenantSpec, error) {
+	var specs []*tenantSpec
+	files := map[string]string{} // organization -> file
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ext := filepath.Ext(path); d.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			return nil
+		}
+		spec, err := loadTenantSpec(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if other, dup := files[spec.OrganizationID]; dup {
+			return fmt.Errorf("%s: organization %s is also defined in %s", path, spec.OrganizationID, other)
+		}
+		files[spec.OrganizationID] = path
+		specs = append(specs, spec)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].OrganizationID < specs[j].OrganizationID })
+	return specs, nil
+}
+
+func loadTenantSpec(path string) (*tenantSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec := &tenantSpec{}
+	if err := yaml.UnmarshalStrict(data, spec); err != nil {
+		return nil, err
+	}
+	if spec.OrganizationID == "" {
+		return nil, errors.New("organization_id is required")
+	}
+	if spec.Tier == "" {
+		return nil, errors.New("tier is required")
+	}
+	if spec.tier, err = parsePlanTier(spec.Tier); err != nil {
+		return nil, err
+	}
+	if spec.OrganizationType == "" {
+		spec.OrganizationType = "namespace"
+	}
+	if spec.orgType, err = parseOrganizationType(spec.OrganizationType); err != nil {
+		return nil, err
+	}
+
+	if spec.Members == nil {
+		return spec, nil
+	}
+	spec.personas = map[string]string{}
+	for _, m := range spec.Members {
+		email := strings.ToLower(strings.TrimSpace(m.Email))
+		if email == "" {
+			return nil, errors.New("member email is required")
+		}
+		switch m.Persona {
+		case "admin", "user", "viewer":
+		default:
+			return nil, fmt.Errorf("member %s: persona must be admin, user or viewer", email)
+		}
+		if _, dup := spec.personas[email]; dup {
+			return nil, fmt.Errorf("member %s is listed twice", email)
+		}
+		spec.personas[email] = m.Persona
+	}
+	return spec, nil
+}
+
+// planSync compares the specs with the account registry. Accounts without a
+// spec are deleted only with prune; invitation tokens are collected in tokens.
+func planSync(ctx context.Context, c *cli, specs []*tenantSpec, prune bool, tokens *invitationTokens) ([]change, error) {
+	// Every page: an account missing from the listing would be created again
+	existing := map[string]*acctv1.GetAccountResponse{}
+	for token := ""; ; {
+		resp, err := c.accounts.ListAccounts(ctx, connect.NewRequest(&acctv1.ListAccountsRequest{PageToken: token}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+		for _, a := range resp.Msg.GetAccounts() {
+			existing[a.GetOrganizationId()] = a
+		}
+		if token = resp.Msg.GetNextPageToken(); token == "" {
+			break
+		}
+	}
+
+	var plan []change
+	for _, spec := range specs {
+		org := spec.OrganizationID
+		current, ok := existing[org]
+		if !ok {
+			plan = append(plan, change{
+				action: "create",
+				org:    org,
+				detail: fmt.Sprintf("tier %s, type %s%s", enumName(spec.tier, "PLAN_TIER_"),
+					enumName(spec.orgType, "ORGANIZATION_TYPE_"), bucketDetail(spec.Bucket)),
+				apply: func(ctx context.Context) error {
+					_, err := c.accounts.CreateAccount(ctx, connect.NewRequest(&acctv1.CreateAccountRequest{
+						OrganizationId:   org,
+						OrganizationType: spec.orgType,
+						PlanTier:         spec.tier,
+						S3Bucket:         spec.Bucket,
+					}))
+					return err
+				},
+			})
+			plan = append(plan, memberChanges(c, spec, nil, tokens)...)
+			continue
+		}
+
+		if current.GetOrganizationType() != spec.orgType {
+			plan = append(plan, change{
+				action: "unsupported",
+				org:    org,
+				detail: fmt.Sprintf("type %s -> %s", enumName(current.GetOrganizationType(), "ORGANIZATION_TYPE_"), enumName(spec.orgType, "ORGANIZATION_TYPE_")),
+				skip:   "the organization type of an account cannot be changed",
+			})
+		}
+		if spec.Bucket != "" && current.GetS3Bucket() != spec.Bucket {
+			plan = append(plan, change{
+				action: "unsupported",
+				org:    org,
+				detail: fmt.Sprintf("bucket %s -> %s", orDash(current.GetS3Bucket()), spec.Bucket),
+				skip:   "the bucket of an account cannot be changed",
+			})
+		}
+		if current.GetPlanTier() != spec.tier {
+			plan = append(plan, change{
+				action: "update",
+				org:    org,
+				detail: fmt.Sprintf("tier %s -> %s", enumName(current.GetPlanTier(), "PLAN_TIER_"), enumName(spec.tier, "PLAN_TIER_")),
+				apply: func(ctx context.Context) error {
+					_, err := c.accounts.UpdateAccount(ctx, connect.NewRequest(&acctv1.UpdateAccountRequest{
+						OrganizationId: org,
+						PlanTier:       spec.tier,
+					}))
+					return err
+				},
+			})
+		}
+
+		if spec.personas != nil {
+			members, err := c.accounts.ListMembers(ctx, connect.NewRequest(&acctv1.ListMembersRequest{OrganizationId: org}))
+			if err != nil {
+				return nil, fmt.Errorf("failed to list members of %s: %w", org, err)
+			}
+			plan = append(plan, memberChanges(c, spec, members.Msg, tokens)...)
+		}
+	}
+
+	var orphans []string
+	for org := range existing {
+		if !containsSpec(specs, org) {
+			orphans = append(orphans, org)
+		}
+	}
+	sort.Strings(orphans)
+	for _, org := range orphans {
+		ch := change{
+			action: "delete",
+			org:    org,
+			detail: "no spec",
+			apply: func(ctx context.Context) error {
+				_, err := c.accounts.DeleteAccount(ctx, connect.NewRequest(&acctv1.DeleteAccountRequest{OrganizationId: org}))
+				return err
+			},
+		}
+		if !prune {
+			ch.skip = "needs -prune"
+		}
+		plan = append(plan, ch)
+	}
+	return plan, nil
+}
+
+// memberChanges invites the spec's members that are neither members nor
+// invited, and removes members and revokes invitations the spec does not
+// list. Personas cannot be changed in place. current is nil for an account
+// that does not exist yet.
+func memberChanges(c *cli, spec *tenantSpec, current *acctv1.ListMembersResponse, tokens *invitationTokens) []change {
+	if spec.personas == nil {
+		return nil
+	}
+	org := spec.OrganizationID
+	have := map[string]string{}    // member email -> persona
+	pending := map[string]string{} // invited email -> persona
+	for _, m := range current.GetMembers() {
+		have[m.GetEmail()] = m.GetPersona()
+	}
+	for _, inv := range current.GetPendingInvitations() {
+		pending[inv.GetEmail()] = inv.GetPersona()
+	}
+
+	var plan []change
+	for _, email := range sortedKeys(spec.personas) {
+		persona := spec.personas[email]
+		got, known := have[email]
+		if !known {
+			got, known = pending[email]
+		}
+		switch {
+		case !known:
+			plan = append(plan, change{
+				action: "invite",
+				org:    org,
+				detail: email + " as " + persona,
+				apply: func(ctx context.Context) error {
+					resp, err := c.accounts.InviteMember(ctx, connect.NewRequest(&acctv1.InviteMemberRequest{
+						OrganizationId: org,
+						Email:          email,
+						Persona:        persona,
+					}))
+					if err != nil {
+						return err
+					}
+					tokens.add(org, email, persona, resp.Msg.GetToken())
+					return nil
+				},
+			})
+		case got != persona:
+			plan = append(plan, change{
+				action: "unsupported",
+				org:    org,
+				detail: fmt.Sprintf("%s %s -> %s", email, got, persona),
+				skip:   "personas cannot be changed; remove the member and sync again",
+			})
+		}
+	}
+	for _, email := range sortedKeys(have) {
+		if _, keep := spec.personas[email]; keep {
+			continue
+		}
+		plan = append(plan, change{
+			action: "remove-member",
+			org:    org,
+			detail: email,
+			apply: func(ctx context.Context) error {
+				_, err := c.accounts.RemoveMember(ctx, connect.NewRequest(&acctv1.RemoveMemberRequest{
+					OrganizationId: org,
+					Email:          email,
+				}))
+				return err
+			},
+		})
+	}
+	// RemoveMember also revokes the invitations of someone who is not a member yet
+	for _, email := range sortedKeys(pending) {
+		if _, keep := spec.personas[email]; keep {
+			continue
+		}
+		if _, member := have[email]; member {
+			continue // Revoked with the membership
+		}
+		plan = append(plan, change{
+			action: "revoke-invitation",
+			org:    org,
+			detail: email,
+			apply: func(ctx context.Context) error {
+				_, err := c.accounts.RemoveMember(ctx, connect.NewRequest(&acctv1.RemoveMemberRequest{
+					OrganizationId: org,
+					Email:          email,
+				}))
+				return err
+			},
+		})
+	}
+	return plan
+}
+
+func containsSpec(specs []*tenantSpec, org string) bool {
+	for _, s := range specs {
+		if s.OrganizationID == org {
+			return true
+		}
+	}
+	return false
+}
+
+func bucketDetail(bucket string) string {
+	if bucket == "" {
+		return ""
+	}
+	return ", bucket " + bucket
+}
+
+// printPlan writes the plan as a table, with a RESULT column once applied
+func printPlan(plan []change, results []string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	if results == nil {
+		fmt.Fprintln(w, "ACTION\tORGANIZATION\tDETAIL\tNOTE")
+	} else {
+		fmt.Fprintln(w, "ACTION\tORGANIZATION\tDETAIL\tRESULT")
+	}
+	for i, ch := range plan {
+		note := orDash(ch.skip)
+		if results != nil {
+			note = results[i]
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", ch.action, ch.org, ch.detail, note)
+	}
+	return w.Flush()
+}
+
+// invitationTokens collects the tokens of members invited by a sync, which
+// are only returned once and must reach the invitees
+type invitationTokens struct {
+	entries []invitationToken
+}
+
+type invitationToken struct {
+	OrganizationID string `json:"organization_id"`
+	Email          string `json:"email"`
+	Persona        string `json:"persona"`
+	Token          string `json:"token"`
+}
+
+func (t *invitationTokens) add(org, email, persona, token string) {
+	t.entries = append(t.entries, invitationToken{OrganizationID: org, Email: email, Persona: persona, Token: token})
+}
+
+// write saves the tokens as YAML readable only by the current user
+func (t *invitationTokens) write(path string) error {
+	data, err := yaml.Marshal(t.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode invitation tokens: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write invitation tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"connectrpc.com/connect"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	acctconnect "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1/acctmanagementv1connect"
+)
+
+// fakeAccounts serves ListAccounts one account per page and ListMembers from
+// members; every other call panics through the nil embedded client
+type fakeAccounts struct {
+	acctconnect.AccountProvisioningServiceClient
+	accounts []*acctv1.GetAccountResponse
+	members  map[string]*acctv1.ListMembersResponse
+}
+
+func (f *fakeAccounts) ListAccounts(_ context.Context, req *connect.Request[acctv1.ListAccountsRequest]) (*connect.Response[acctv1.ListAccountsResponse], error) {
+	resp := &acctv1.ListAccountsResponse{}
+	page := 0
+	if token := req.Msg.GetPageToken(); token != "" {
+		page, _ = strconv.Atoi(token)
+	}
+	if page < len(f.accounts) {
+		resp.Accounts = f.accounts[page : page+1]
+		if page+1 < len(f.accounts) {
+			resp.NextPageToken = strconv.Itoa(page + 1)
+		}
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (f *fakeAccounts) ListMembers(_ context.Context, req *connect.Request[acctv1.ListMembersRequest]) (*connect.Response[acctv1.ListMembersResponse], error) {
+	resp, ok := f.members[req.Msg.GetOrganizationId()]
+	if !ok {
+		resp = &acctv1.ListMembersResponse{}
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func TestPlanSync(t *testing.T) {
+	pro, free := acctv1.PlanTier_PLAN_TIER_PRO, acctv1.PlanTier_PLAN_TIER_FREE
+	namespace, node := acctv1.OrganizationType_ORGANIZATION_TYPE_NAMESPACE, acctv1.OrganizationType_ORGANIZATION_TYPE_NODE
+	account := func(org string, tier acctv1.PlanTier, orgType acctv1.OrganizationType, bucket string) *acctv1.GetAccountResponse {
+		return &acctv1.GetAccountResponse{OrganizationId: org, PlanTier: tier, OrganizationType: orgType, S3Bucket: bucket}
+	}
+	spec := func(org string, tier acctv1.PlanTier, orgType acctv1.OrganizationType, bucket string, personas map[string]string) *tenantSpec {
+		return &tenantSpec{OrganizationID: org, Bucket: bucket, tier: tier, orgType: orgType, personas: personas}
+	}
+
+	tests := []struct {
+		name     string
+		accounts []*acctv1.GetAccountResponse
+		members  map[string]*acctv1.ListMembersResponse
+		specs    []*tenantSpec
+		prune    bool
+		want     []string
+	}{
+		{
+			name:     "in sync",
+			accounts: []*acctv1.GetAccountResponse{account("acme", pro, namespace, "acme-data")},
+			specs:    []*tenantSpec{spec("acme", pro, namespace, "", nil)},
+		},
+		{
+			name:  "create with members",
+			specs: []*tenantSpec{spec("acme", pro, namespace, "acme-data", map[string]string{"alice@acme.com": "admin"})},
+			want: []string{
+				"create acme: tier PRO, type NAMESPACE, bucket acme-data",
+				"invite acme: alice@acme.com as admin",
+			},
+		},
+		{
+			name:     "tier change",
+			accounts: []*acctv1.GetAccountResponse{account("acme", free, namespace, "")},
+			specs:    []*tenantSpec{spec("acme", pro, namespace, "", nil)},
+			want:     []string{"update acme: tier FREE -> PRO"},
+		},
+		{
+			name:     "type and bucket cannot change",
+			accounts: []*acctv1.GetAccountResponse{account("acme", pro, namespace, "old")},
+			specs:    []*tenantSpec{spec("acme", pro, node, "new", nil)},
+			want: []string{
+				"unsupported acme: type NAMESPACE -> NODE (skipped)",
+				"unsupported acme: bucket old -> new (skipped)",
+			},
+		},
+		{
+			name: "members",
+			accounts: []*acctv1.GetAccountResponse{
+				account("acme", pro, namespace, ""),
+			},
+			members: map[string]*acctv1.ListMembersResponse{
+				"acme": {
+					Members: []*acctv1.Member{
+						{Email: "alice@acme.com", Persona: "admin"},
+						{Email: "bob@acme.com", Persona: "user"},
+						{Email: "eve@acme.com", Persona: "viewer"},
+					},
+					PendingInvitations: []*acctv1.Invitation{{Email: "carol@acme.com", Persona: "user"}},
+				},
+			},
+			specs: []*tenantSpec{spec("acme", pro, namespace, "", map[string]string{
+				"alice@acme.com": "admin",
+				"bob@acme.com":   "admin",
+				"carol@acme.com": "user",
+				"dave@acme.com":  "viewer",
+			})},
+			want: []string{
+				"unsupported acme: bob@acme.com user -> admin (skipped)",
+				"invite acme: dave@acme.com as viewer",
+				"remove-member acme: eve@acme.com",
+			},
+		},
+		{
+			name: "pending invitations not in the spec",
+			accounts: []*acctv1.GetAccountResponse{
+				account("acme", pro, namespace, ""),
+				account("beta", pro, namespace, ""),
+			},
+			members: map[string]*acctv1.ListMembersResponse{
+				"acme": {
+					Members: []*acctv1.Member{{Email: "alice@acme.com", Persona: "admin"}},
+					PendingInvitations: []*acctv1.Invitation{
+						{Email: "alice@acme.com", Persona: "admin"},
+						{Email: "carol@acme.com", Persona: "user"},
+					},
+				},
+				"beta": {PendingInvitations: []*acctv1.Invitation{{Email: "dave@beta.com", Persona: "viewer"}}},
+			},
+			specs: []*tenantSpec{
+				spec("acme", pro, namespace, "", map[string]string{"alice@acme.com": "admin"}),
+				spec("beta", pro, namespace, "", map[string]string{}), // members: []
+			},
+			want: []string{
+				"revoke-invitation acme: carol@acme.com",
+				"revoke-invitation beta: dave@beta.com",
+			},
+		},
+		{
+			name: "orphans on later pages",
+			accounts: []*acctv1.GetAccountResponse{
+				account("acme", pro, namespace, ""),
+				account("zeta", pro, namespace, ""),
+				account("beta", pro, namespace, ""),
+			},
+			specs: []*tenantSpec{spec("acme", pro, namespace, "", nil)},
+			want: []string{
+				"delete beta: no spec (skipped)",
+				"delete zeta: no spec (skipped)",
+			},
+		},
+		{
+			name:     "prune",
+			accounts: []*acctv1.GetAccountResponse{account("acme", pro, namespace, ""), account("beta", pro, namespace, "")},
+			specs:    []*tenantSpec{spec("acme", pro, namespace, "", nil)},
+			prune:    true,
+			want:     []string{"delete beta: no spec"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &cli{accounts: &fakeAccounts{accounts: tt.accounts, members: tt.members}}
+			plan, err := planSync(context.Background(), c, tt.specs, tt.prune, &invitationTokens{})
+			if err != nil {
+				t.Fatalf("planSync: %v", err)
+			}
+
+			var got []string
+			for _, ch := range plan {
+				line := ch.action + " " + ch.org + ": " + ch.detail
+				if ch.skip != "" {
+					line += " (skipped)"
+				}
+				if ch.skip == "" && ch.apply == nil {
+					t.Errorf("%s has neither apply nor skip", line)
+				}
+				got = append(got, line)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planSync =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
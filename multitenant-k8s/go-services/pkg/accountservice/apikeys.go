@@ -0,0 +1,135 @@
+package accountservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/auth"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+// ErrInvalidScope is returned when a key is requested with an unknown scope
+var ErrInvalidScope = errors.New("unknown api key scope")
+
+// ============================================================================
+// API Keys
+// ============================================================================
+
+// CreateAPIKey mints a key for an organization. The returned token is the only
+// copy of the secret; only its hash is stored.
+func (s *Service) CreateAPIKey(ctx context.Context, orgID, name string, scopes []string, ttl time.Duration) (*storage.APIKeyRecord, string, error) {
+	if _, err := s.accounts.Get(ctx, orgID); err != nil {
+		return nil, "", err
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(auth.KnownScopes, scope) {
+			return nil, "", fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+
+	id, secret, token, err := auth.NewAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now().UTC()
+	record := &storage.APIKeyRecord{
+		ID:             id,
+		OrganizationID: orgID,
+		Name:           name,
+		SecretHash:     auth.HashAPIKeySecret(secret),
+		Scopes:         scopes,
+		CreatedAt:      now,
+	}
+	if ttl > 0 {
+		record.ExpiresAt = now.Add(ttl)
+	}
+
+	err = s.apiKeys.Create(ctx, record)
+	s.audit.RecordCall(ctx, "apikeys:Create", orgID, err, "apikey/"+id)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to save api key: %w", err)
+	}
+
+	return record, token, nil
+}
+
+// ListAPIKeys returns an organization's keys, including revoked ones
+func (s *Service) ListAPIKeys(ctx context.Context, orgID string) ([]storage.APIKeyRecord, error) {
+	return s.apiKeys.List(ctx, orgID)
+}
+
+// RotateAPIKey replaces a key's secret; the old secret stops working immediately
+func (s *Service) RotateAPIKey(ctx context.Context, orgID, keyID string) (*storage.APIKeyRecord, string, error) {
+	secret, err := auth.NewAPIKeySecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	record, err := s.apiKeys.Modify(ctx, keyID, func(rec *storage.APIKeyRecord) error {
+		if rec.OrganizationID != orgID {
+			return storage.ErrNotFound
+		}
+		if !rec.RevokedAt.IsZero() {
+			return fmt.Errorf("api key %s is revoked", keyID)
+		}
+		rec.SecretHash = auth.HashAPIKeySecret(secret)
+		rec.RotatedAt = time.Now().UTC()
+		return nil
+	})
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, "", err
+	}
+	s.audit.RecordCall(ctx, "apikeys:Rotate", orgID, err, "apikey/"+keyID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to rotate api key: %w", err)
+	}
+
+	return record, auth.FormatAPIKey(record.ID, secret), nil
+}
+
+// RevokeAPIKey permanently disables a key
+func (s *Service) RevokeAPIKey(ctx context.Context, orgID, keyID string) (*storage.APIKeyRecord, error) {
+	revoked := false
+	record, err := s.apiKeys.Modify(ctx, keyID, func(rec *storage.APIKeyRecord) error {
+		if rec.OrganizationID != orgID {
+			return storage.ErrNotFound
+		}
+		revoked = rec.RevokedAt.IsZero()
+		if revoked {
+			rec.RevokedAt = time.Now().UTC()
+		}
+		return nil
+	})
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil || revoked {
+		s.audit.RecordCall(ctx, "apikeys:Revoke", orgID, err, "apikey/"+keyID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return record, nil
+}
+
+// revokeAllAPIKeys disables every key of an organization that is being deleted
+func (s *Service) revokeAllAPIKeys(ctx context.Context, orgID string) error {
+	keys, err := s.apiKeys.List(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if _, err := s.RevokeAPIKey(ctx, orgID, k.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
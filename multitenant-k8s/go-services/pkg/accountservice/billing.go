@@ -0,0 +1,179 @@
+package accountservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/billing"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+// BillingSuspendReason prefixes the suspension reason of accounts suspended for
+// their subscription state. Only these suspensions are lifted by billing.
+const BillingSuspendReason = "billing: subscription "
+
+// ============================================================================
+// Billing
+// ============================================================================
+
+// startBilling creates the tenant's customer and subscription. It is a no-op
+// when no billing provider is configured
+func (s *Service) startBilling(ctx context.Context, orgID string, tier acctv1.PlanTier) error {
+	if s.billing == nil {
+		return nil
+	}
+
+	customerID, err := s.billing.CreateCustomer(ctx, orgID)
+	s.audit.RecordCall(ctx, "billing:CreateCustomer", orgID, err, "customer/"+customerID)
+	if err != nil {
+		return err
+	}
+
+	sub, err := s.billing.CreateSubscription(ctx, customerID, orgID, tier.String())
+	var subID string
+	if sub != nil {
+		subID = sub.ID
+	}
+	s.audit.RecordCall(ctx, "billing:CreateSubscription", orgID, err, "subscription/"+subID)
+	if err != nil {
+		return err
+	}
+
+	return s.billingAccounts.Save(ctx, &storage.BillingRecord{
+		OrganizationID: orgID,
+		CustomerID:     customerID,
+		SubscriptionID: sub.ID,
+		PlanItemID:     sub.PlanItemID,
+		MeteredItemID:  sub.MeteredItemID,
+		Plan:           tier.String(),
+		Status:         sub.Status,
+	})
+}
+
+// stopBilling cancels the tenant's subscription and forgets its billing record
+func (s *Service) stopBilling(ctx context.Context, orgID string) error {
+	if s.billing == nil {
+		return nil
+	}
+
+	record, err := s.billingAccounts.Get(ctx, orgID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if record.SubscriptionID != "" && record.Status != "canceled" {
+		err := s.billing.CancelSubscription(ctx, record.SubscriptionID)
+		s.audit.RecordCall(ctx, "billing:CancelSubscription", orgID, err, "subscription/"+record.SubscriptionID)
+		if err != nil {
+			return err
+		}
+	}
+	return s.billingAccounts.Delete(ctx, orgID)
+}
+
+// syncBillingPlan moves the subscription to the account's plan tier when a
+// plan change did not originate from billing
+func (s *Service) syncBillingPlan(ctx context.Context, orgID string, tier acctv1.PlanTier) error {
+	if s.billing == nil {
+		return nil
+	}
+
+	record, err := s.billingAccounts.Get(ctx, orgID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if record.Plan == tier.String() || record.SubscriptionID == "" {
+		return nil
+	}
+
+	sub := &billing.Subscription{ID: record.SubscriptionID, PlanItemID: record.PlanItemID}
+	err = s.billing.ChangePlan(ctx, sub, tier.String())
+	s.audit.RecordCall(ctx, "billing:ChangePlan", orgID, err, "subscription/"+record.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	record.Plan = tier.String()
+	return s.billingAccounts.Save(ctx, record)
+}
+
+// ApplySubscriptionChange implements billing.Accounts: the account follows the
+// subscription's plan, is suspended when payment lapses and resumed when the
+// subscription is back in good standing
+func (s *Service) ApplySubscriptionChange(ctx context.Context, change billing.SubscriptionChange) error {
+	record, err := s.billingAccounts.Get(ctx, change.OrganizationID)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Printf("billing: ignoring subscription %s for unknown organization %s", change.SubscriptionID, change.OrganizationID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Events for replaced subscriptions and deliveries out of order are stale
+	if record.SubscriptionID != change.SubscriptionID || change.OccurredAt.Before(record.LastEventAt) {
+		return nil
+	}
+
+	// Record the plan first so UpdateAccount does not push it back to billing
+	if change.Plan != "" {
+		record.Plan = change.Plan
+	}
+	record.Status = change.Status
+	record.LastEventAt = change.OccurredAt
+	if err := s.billingAccounts.Save(ctx, record); err != nil {
+		return fmt.Errorf("failed to save billing record: %w", err)
+	}
+
+	account, err := s.accounts.Get(ctx, change.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	if change.Plan != "" && change.Plan != account.PlanTier {
+		tier, ok := acctv1.PlanTier_value[change.Plan]
+		if !ok {
+			return fmt.Errorf("unknown plan tier %s", change.Plan)
+		}
+		if _, _, err := s.UpdateAccount(ctx, change.OrganizationID, acctv1.PlanTier(tier)); err != nil {
+			return fmt.Errorf("failed to apply plan %s: %w", change.Plan, err)
+		}
+	}
+
+	switch {
+	case subscriptionLapsed(change.Status) && account.Status != storage.AccountStatusSuspended:
+		if _, err := s.SuspendAccount(ctx, change.OrganizationID, BillingSuspendReason+change.Status); err != nil {
+			return fmt.Errorf("failed to suspend account: %w", err)
+		}
+	case subscriptionInGoodStanding(change.Status) && account.Status == storage.AccountStatusSuspended &&
+		strings.HasPrefix(account.SuspendReason, BillingSuspendReason):
+		if _, err := s.ResumeAccount(ctx, change.OrganizationID); err != nil {
+			return fmt.Errorf("failed to resume account: %w", err)
+		}
+	}
+	return nil
+}
+
+// subscriptionLapsed reports whether a subscription status means the tenant
+// is no longer paying
+func subscriptionLapsed(status string) bool {
+	switch status {
+	case "past_due", "unpaid", "canceled", "incomplete_expired", "paused":
+		return true
+	}
+	return false
+}
+
+// subscriptionInGoodStanding reports whether a subscription status allows the tenant to run
+func subscriptionInGoodStanding(status string) bool {
+	return status == "active" || status == "trialing"
+}
@@ -0,0 +1,129 @@
+package accountservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/egress"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/periodic"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+// ErrInvalidEgress is returned for malformed hostnames or CIDRs
+var ErrInvalidEgress = errors.New("invalid egress allowlist")
+
+// ============================================================================
+// Egress Allowlists
+// ============================================================================
+
+// EgressAllowlist returns an account's allowlist and how its cluster enforces it
+func (s *Service) EgressAllowlist(ctx context.Context, orgID string) (egress.Allowlist, egress.Mode, error) {
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return egress.Allowlist{}, "", err
+	}
+	cluster, err := s.clusterFor(record)
+	if err != nil {
+		return egress.Allowlist{}, "", err
+	}
+	return accountAllowlist(record), egress.DetectMode(cluster.Client), nil
+}
+
+// SetEgressAllowlist replaces an account's allowlist and renders it into the
+// tenant namespace. An empty allowlist removes the external destinations again.
+func (s *Service) SetEgressAllowlist(ctx context.Context, orgID string, list egress.Allowlist) (egress.Allowlist, egress.Mode, error) {
+	list, err := list.Normalize()
+	if err != nil {
+		return egress.Allowlist{}, "", fmt.Errorf("%w: %v", ErrInvalidEgress, err)
+	}
+
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return egress.Allowlist{}, "", err
+	}
+	cluster, err := s.clusterFor(record)
+	if err != nil {
+		return egress.Allowlist{}, "", err
+	}
+
+	// Sub-namespaces share the tenant's allowlist
+	namespaces, err := tenantNamespaces(ctx, cluster.Client, record)
+	if err != nil {
+		return egress.Allowlist{}, "", err
+	}
+	var mode egress.Mode
+	for _, ns := range namespaces {
+		mode, err = s.egress.Apply(ctx, cluster.Client, cluster.Dynamic, orgID, ns, list)
+		s.audit.RecordCall(ctx, "kubernetes:ApplyEgressPolicy", orgID, err, ns+"/egress/"+egress.PolicyName)
+		if err != nil {
+			return egress.Allowlist{}, "", fmt.Errorf("failed to apply egress policy: %w", err)
+		}
+	}
+
+	err = s.modifyAccount(ctx, record, func(rec *storage.AccountRecord) {
+		rec.EgressHostnames = list.Hostnames
+		rec.EgressCIDRs = list.CIDRs
+	}, nil)
+	if err != nil {
+		return egress.Allowlist{}, "", err
+	}
+
+	return list, mode, nil
+}
+
+// RunEgressRefresh re-resolves hostname allowlists until ctx is cancelled
+func (s *Service) RunEgressRefresh(ctx context.Context, interval time.Duration) {
+	periodic.Every(ctx, interval, "egress refresh", s.RefreshEgress)
+}
+
+// RefreshEgress re-renders hostname allowlists on clusters without FQDN policy
+// support, where the policy holds resolved IPs that go stale
+func (s *Service) RefreshEgress(ctx context.Context) error {
+	records, err := s.accounts.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	modes := map[string]egress.Mode{}
+	for i := range records {
+		record := &records[i]
+		if len(record.EgressHostnames) == 0 {
+			continue
+		}
+		cluster, err := s.clusterFor(record)
+		if err != nil {
+			log.Printf("egress refresh: %s: %v", record.OrganizationID, err)
+			continue
+		}
+		mode, ok := modes[cluster.Name]
+		if !ok {
+			mode = egress.DetectMode(cluster.Client)
+			modes[cluster.Name] = mode
+		}
+		if mode != egress.ModeResolved {
+			continue
+		}
+
+		namespaces, err := tenantNamespaces(ctx, cluster.Client, record)
+		if err != nil {
+			log.Printf("egress refresh: %s: %v", record.OrganizationID, err)
+			continue
+		}
+		// One tenant's DNS failure should not block the others.
+		for _, ns := range namespaces {
+			_, err := s.egress.Apply(ctx, cluster.Client, cluster.Dynamic, record.OrganizationID, ns, accountAllowlist(record))
+			s.audit.RecordCall(ctx, "kubernetes:ApplyEgressPolicy", record.OrganizationID, err, ns+"/egress/"+egress.PolicyName)
+			if err != nil {
+				log.Printf("egress refresh: %s: %v", ns, err)
+			}
+		}
+	}
+	return nil
+}
+
+func accountAllowlist(record *storage.AccountRecord) egress.Allowlist {
+	return egress.Allowlist{Hostnames: record.EgressHostnames, CIDRs: record.EgressCIDRs}
+}
@@ -0,0 +1,251 @@
+package accountservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/events"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/periodic"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+// What happens to an account whose paid plan expires
+const (
+	ExpiryActionDowngrade = "downgrade" // Move to PLAN_TIER_FREE, which starts a trial
+	ExpiryActionSuspend   = "suspend"
+)
+
+// ExpirySuspendReason prefixes the suspension reason of expired accounts. Only
+// these suspensions are lifted by RevertExpiry.
+const ExpirySuspendReason = "expired: "
+
+// Kinds of deadline an account can reach
+const (
+	expiryTrial = "trial"
+	expiryPlan  = "plan"
+)
+
+// ErrNotExpired is returned by RevertExpiry for accounts with no expiry to undo
+var ErrNotExpired = errors.New("account has no expiry to revert")
+
+// ExpiryPolicy decides when accounts expire and what happens then
+type ExpiryPolicy struct {
+	TrialPeriod time.Duration // Trial length for FREE accounts; 0 means FREE accounts never expire
+	WarnBefore  time.Duration // How long before a deadline account.expiry_warning is published
+	PlanAction  string        // ExpiryActionDowngrade (default) or ExpiryActionSuspend
+}
+
+// ValidateExpiryAction rejects unknown plan expiry actions
+func ValidateExpiryAction(action string) error {
+	switch action {
+	case ExpiryActionDowngrade, ExpiryActionSuspend:
+		return nil
+	}
+	return fmt.Errorf("invalid plan expiry action %q: must be %s or %s", action, ExpiryActionDowngrade, ExpiryActionSuspend)
+}
+
+// ============================================================================
+// Deadlines
+// ============================================================================
+
+// resetTrial starts the trial clock of FREE accounts that have none and clears
+// it on paid tiers. Accounts registered before trials existed keep no deadline
+// until they change plan.
+func (s *Service) resetTrial(record *storage.AccountRecord, now time.Time) {
+	if record.PlanTier != acctv1.PlanTier_PLAN_TIER_FREE.String() {
+		record.TrialEndsAt = time.Time{}
+		return
+	}
+	if record.TrialEndsAt.IsZero() && s.expiry.TrialPeriod > 0 {
+		record.TrialEndsAt = now.Add(s.expiry.TrialPeriod).UTC()
+	}
+}
+
+// deadlineOf returns the deadline that applies to the account's current plan
+func deadlineOf(record *storage.AccountRecord) (time.Time, string) {
+	if record.PlanTier == acctv1.PlanTier_PLAN_TIER_FREE.String() {
+		return record.TrialEndsAt, expiryTrial
+	}
+	return record.PlanExpiresAt, expiryPlan
+}
+
+// SetAccountExpiry replaces an account's trial end and plan expiry. Zero times
+// remove the deadline.
+func (s *Service) SetAccountExpiry(ctx context.Context, orgID string, trialEndsAt, planExpiresAt time.Time) (*storage.AccountRecord, error) {
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.modifyAccount(ctx, record, func(rec *storage.AccountRecord) {
+		rec.TrialEndsAt = trialEndsAt.UTC()
+		rec.PlanExpiresAt = planExpiresAt.UTC()
+		rec.ExpiryWarnedAt = time.Time{}
+	}, expiryEvent(events.TypeAccountExpiryChanged, "", ""))
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// expiryEvent builds an expiry event carrying the account's current deadline
+func expiryEvent(eventType, kind, previousTier string) func(*storage.AccountRecord) (storage.OutboxMessage, error) {
+	return func(record *storage.AccountRecord) (storage.OutboxMessage, error) {
+		data := events.AccountData{
+			OrganizationID:   record.OrganizationID,
+			Namespace:        record.Namespace,
+			PlanTier:         record.PlanTier,
+			PreviousPlanTier: previousTier,
+			Status:           record.Status,
+			Reason:           kind,
+		}
+		if at, _ := deadlineOf(record); !at.IsZero() {
+			data.ExpiresAt = at.Format(time.RFC3339)
+		}
+		return events.NewAccountEvent(eventType, data)
+	}
+}
+
+// ============================================================================
+// Expiry Loop
+// ============================================================================
+
+// RunExpiry checks every account's deadline on an interval until ctx is cancelled
+func (s *Service) RunExpiry(ctx context.Context, interval time.Duration) {
+	periodic.Every(ctx, interval, "expiry check", func(ctx context.Context) error {
+		return s.CheckExpiry(ctx, time.Now())
+	})
+}
+
+// CheckExpiry warns accounts approaching their deadline and expires those past it
+func (s *Service) CheckExpiry(ctx context.Context, now time.Time) error {
+	records, err := s.accounts.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range records {
+		if err := s.checkAccountExpiry(ctx, &records[i], now); err != nil {
+			log.Printf("expiry check failed for %s: %v", records[i].OrganizationID, err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) checkAccountExpiry(ctx context.Context, record *storage.AccountRecord, now time.Time) error {
+	at, kind := deadlineOf(record)
+	if at.IsZero() {
+		return nil
+	}
+
+	if !now.Before(at) {
+		// Suspended accounts stay suspended; resuming one past its deadline
+		// without RevertExpiry expires it again
+		if record.Status == storage.AccountStatusSuspended {
+			return nil
+		}
+		return s.expireAccount(ctx, record, kind)
+	}
+
+	warnFrom := at.Add(-s.expiry.WarnBefore)
+	if s.expiry.WarnBefore > 0 && !now.Before(warnFrom) && record.ExpiryWarnedAt.Before(warnFrom) {
+		err := s.modifyAccount(ctx, record, func(rec *storage.AccountRecord) {
+			rec.ExpiryWarnedAt = now.UTC()
+		}, expiryEvent(events.TypeAccountExpiryWarning, kind, ""))
+		s.audit.RecordCall(ctx, "expiry:Warn", record.OrganizationID, err, kind)
+		return err
+	}
+	return nil
+}
+
+// expireAccount downgrades or suspends an account that reached its deadline
+func (s *Service) expireAccount(ctx context.Context, record *storage.AccountRecord, kind string) error {
+	orgID := record.OrganizationID
+	previousTier := record.PlanTier
+
+	// FREE has no lower tier, so an ended trial always suspends
+	if kind == expiryPlan && s.expiry.PlanAction == ExpiryActionDowngrade {
+		_, _, err := s.UpdateAccount(ctx, orgID, acctv1.PlanTier_PLAN_TIER_FREE)
+		s.audit.RecordCall(ctx, "expiry:Downgrade", orgID, err, kind, previousTier)
+		if err != nil {
+			return fmt.Errorf("failed to downgrade: %w", err)
+		}
+	} else {
+		_, err := s.SuspendAccount(ctx, orgID, ExpirySuspendReason+kind+" ended")
+		s.audit.RecordCall(ctx, "expiry:Suspend", orgID, err, kind, previousTier)
+		if err != nil {
+			return fmt.Errorf("failed to suspend: %w", err)
+		}
+	}
+
+	// The transition saved the record; record what RevertExpiry needs on top
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	return s.modifyAccount(ctx, record, func(rec *storage.AccountRecord) {
+		rec.ExpiredFromPlan = previousTier
+		rec.ExpiryWarnedAt = time.Time{}
+		// A suspended account keeps its passed deadline so a plain ResumeAccount
+		// expires it again; a downgraded one must not carry it into a later upgrade
+		if rec.PlanTier != previousTier {
+			rec.PlanExpiresAt = time.Time{}
+		}
+	}, expiryEvent(events.TypeAccountExpired, kind, previousTier))
+}
+
+// RevertExpiry undoes the last expiry of an account: the previous plan is
+// restored, an expiry suspension is lifted and the deadline moves to extend
+// from now (none if extend is 0)
+func (s *Service) RevertExpiry(ctx context.Context, orgID string, extend time.Duration) (*storage.AccountRecord, error) {
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if record.ExpiredFromPlan == "" {
+		return nil, ErrNotExpired
+	}
+	restoreTier, ok := acctv1.PlanTier_value[record.ExpiredFromPlan]
+	if !ok {
+		return nil, fmt.Errorf("unknown plan tier %s", record.ExpiredFromPlan)
+	}
+
+	if record.Status == storage.AccountStatusSuspended && strings.HasPrefix(record.SuspendReason, ExpirySuspendReason) {
+		if _, err := s.ResumeAccount(ctx, orgID); err != nil {
+			return nil, fmt.Errorf("failed to resume account: %w", err)
+		}
+	}
+	previousTier := record.PlanTier
+	if record.PlanTier != record.ExpiredFromPlan {
+		if _, _, err := s.UpdateAccount(ctx, orgID, acctv1.PlanTier(restoreTier)); err != nil {
+			return nil, fmt.Errorf("failed to restore plan: %w", err)
+		}
+	}
+
+	record, err = s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	var deadline time.Time
+	if extend > 0 {
+		deadline = time.Now().Add(extend).UTC()
+	}
+	err = s.modifyAccount(ctx, record, func(rec *storage.AccountRecord) {
+		if rec.PlanTier == acctv1.PlanTier_PLAN_TIER_FREE.String() {
+			rec.TrialEndsAt = deadline
+		} else {
+			rec.PlanExpiresAt = deadline
+		}
+		rec.ExpiredFromPlan = ""
+		rec.ExpiryWarnedAt = time.Time{}
+	}, expiryEvent(events.TypeAccountExpiryReverted, "", previousTier))
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
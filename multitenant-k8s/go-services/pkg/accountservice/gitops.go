@@ -0,0 +1,324 @@
+package accountservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/clusters"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/events"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/gitops"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/manifests"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/periodic"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/templates"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Errors returned for tenants whose objects live in the GitOps repository
+var (
+	ErrGitOpsDisabled    = errors.New("gitops export is not enabled on this server")
+	ErrGitOpsUnsupported = errors.New("not supported for tenants managed through gitops")
+)
+
+// ============================================================================
+// GitOps Export
+// ============================================================================
+
+// GitOpsEnabled reports whether new tenants are committed to the GitOps
+// repository instead of being created on their cluster
+func (s *Service) GitOpsEnabled() bool {
+	return s.gitops != nil
+}
+
+// provisionGitOps is ProvisionAccount for a GitOps server. The tenant's
+// Kubernetes objects are committed to the repository for the sync controller
+// to apply; IAM, S3, the job topic and billing are set up directly as usual.
+// The account is registered as PROVISIONING and becomes ACTIVE, with
+// account.created staged, once CheckGitOpsSync sees the commit synced.
+func (s *Service) provisionGitOps(ctx context.Context, opts ProvisionOptions) (*AccountProvisioningResult, error) {
+	// Pull secrets are refreshed hourly and ingress edits the shared Gateway,
+	// neither of which belongs in the repository
+	if opts.Registry {
+		return nil, fmt.Errorf("container registry: %w", ErrGitOpsUnsupported)
+	}
+	if opts.Ingress {
+		return nil, fmt.Errorf("ingress: %w", ErrGitOpsUnsupported)
+	}
+
+	orgID, tier := opts.OrgID, opts.PlanTier
+	result := &AccountProvisioningResult{
+		OrganizationID: orgID,
+	}
+
+	// 0. Choose the cluster; its sync controller applies the tenant directory
+	cluster, err := s.placeTenant(ctx, tier, opts.Region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place tenant: %w", err)
+	}
+	result.Cluster = cluster.Name
+	result.Namespace = fmt.Sprintf("tenant-%s", orgID)
+	created := &provisioned{cluster: cluster, orgID: orgID}
+
+	quota, err := QuotaForTier(tier)
+	if err != nil {
+		return nil, err
+	}
+	result.ResourceQuota = quota
+
+	// 1. PriorityClasses are shared by every tenant on the cluster and stay outside the repository
+	if err := s.ensurePriorityClasses(ctx, cluster.Client); err != nil {
+		return nil, fmt.Errorf("failed to ensure priority classes: %w", err)
+	}
+
+	// 2. Create the IAM role and attach the S3 policy
+	if err := s.provisionIAM(ctx, created, opts.S3Bucket, result); err != nil {
+		return nil, err
+	}
+
+	// 3-4. Give the tenant a job topic of its own and open billing
+	if err := s.provisionExternal(ctx, created, opts.JobTopic, tier, result); err != nil {
+		return nil, err
+	}
+
+	// 5. Commit the tenant's objects
+	record := provisionedRecord(opts, result)
+	record.Status = storage.AccountStatusProvisioning
+	record.CreatedAt = time.Now().UTC()
+	if opts.Template != "" {
+		record.Template = &storage.AppliedTemplate{Name: opts.Template}
+	}
+	if err := s.exportTenant(ctx, cluster, record); err != nil {
+		s.rollback(ctx, created)
+		return nil, err
+	}
+	created.exported = true
+
+	// 6. Register the account; account.created waits for the sync
+	s.resetTrial(record, time.Now())
+	if err := s.accounts.Save(ctx, record); err != nil {
+		s.rollback(ctx, created)
+		return nil, fmt.Errorf("failed to register account: %w", err)
+	}
+
+	result.Template = record.Template
+	result.GitOpsRevision = record.GitOpsRevision
+	result.Status = record.Status
+	result.CreatedAt = record.CreatedAt
+	return result, nil
+}
+
+// exportTenant commits the tenant's objects as recorded and stores the commit
+// in record.GitOpsRevision. A recorded template is rendered at the catalog's
+// current version.
+func (s *Service) exportTenant(ctx context.Context, cluster *clusters.Cluster, record *storage.AccountRecord) error {
+	if s.gitops == nil {
+		return ErrGitOpsDisabled
+	}
+	objs, err := s.tenantObjects(ctx, cluster, record)
+	if err != nil {
+		return err
+	}
+
+	revision, err := s.gitops.Export(ctx, gitops.Tenant{
+		OrgID:     record.OrganizationID,
+		Namespace: record.Namespace,
+		Cluster:   cluster.Name,
+		PlanTier:  record.PlanTier,
+	}, objs)
+	s.audit.RecordCall(ctx, "git:ExportTenant", record.OrganizationID, err, s.gitops.Path(cluster.Name, record.OrganizationID)+"@"+revision)
+	if err != nil {
+		return fmt.Errorf("failed to export tenant: %w", err)
+	}
+	record.GitOpsRevision = revision
+	return nil
+}
+
+// removeExport commits the removal of the tenant's directory
+func (s *Service) removeExport(ctx context.Context, cluster *clusters.Cluster, orgID string) error {
+	if s.gitops == nil {
+		return ErrGitOpsDisabled
+	}
+	revision, err := s.gitops.Remove(ctx, cluster.Name, orgID)
+	s.audit.RecordCall(ctx, "git:RemoveTenant", orgID, err, s.gitops.Path(cluster.Name, orgID)+"@"+revision)
+	return err
+}
+
+// tenantObjects builds what direct provisioning creates in the tenant
+// namespace: the namespace, quotas, service account, network policy, roles
+// and the objects of record.Template, which is updated to the rendered version
+func (s *Service) tenantObjects(ctx context.Context, cluster *clusters.Cluster, record *storage.AccountRecord) ([]runtime.Object, error) {
+	tier := acctv1.PlanTier(acctv1.PlanTier_value[record.PlanTier])
+	orgType := acctv1.OrganizationType(acctv1.OrganizationType_value[record.OrganizationType])
+
+	quotaSpec, err := QuotaForTier(tier)
+	if err != nil {
+		return nil, err
+	}
+	quota, err := resourceQuotaObject(record.Namespace, tier, quotaSpec)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := manifests.NetworkPolicy(manifests.Vars{TenantName: record.Namespace, OrgID: record.OrganizationID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render network policy: %w", err)
+	}
+	roles, bindings, err := tenantRBAC(record.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := []runtime.Object{
+		s.namespaceObject(record.OrganizationID, orgType, tier, record.CreatedAt),
+		quota,
+		priorityGuardObject(record.Namespace, tier),
+		serviceAccountObject(record.Namespace, record.OrganizationID, record.IAMRoleARN),
+		policy,
+	}
+	for _, role := range roles {
+		objs = append(objs, role)
+	}
+	for _, binding := range bindings {
+		objs = append(objs, binding)
+	}
+
+	if record.Template == nil {
+		return objs, nil
+	}
+	if s.templates == nil {
+		return nil, ErrTemplatesDisabled
+	}
+	t, err := s.templates.Get(record.Template.Name)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := s.templates.Render(ctx, t, templates.Values{
+		OrgID:     record.OrganizationID,
+		Namespace: record.Namespace,
+		Tier:      record.PlanTier,
+		Cluster:   cluster.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", t.Name, err)
+	}
+	prepared, err := templates.NewApplier(cluster.Client, cluster.Dynamic).Prepare(t, record.Namespace, rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.Template.Version != t.Version {
+		record.Template = &storage.AppliedTemplate{Name: t.Name, Version: t.Version, AppliedAt: time.Now().UTC()}
+	}
+	record.Template.Objects = nil
+	for _, o := range prepared {
+		record.Template.Objects = append(record.Template.Objects, storage.TemplateObject(o))
+	}
+	for _, obj := range rendered {
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// updateGitOpsTier is UpdateAccount for a GitOps tenant: the new tier's
+// objects are committed rather than applied
+func (s *Service) updateGitOpsTier(ctx context.Context, record *storage.AccountRecord, tier acctv1.PlanTier, quotaSpec *acctv1.ResourceQuota) (*storage.AccountRecord, *acctv1.ResourceQuota, error) {
+	cluster, err := s.clusterFor(record)
+	if err != nil {
+		return nil, nil, err
+	}
+	previousTier := record.PlanTier
+
+	record.PlanTier = tier.String()
+	if err := s.exportTenant(ctx, cluster, record); err != nil {
+		return nil, nil, err
+	}
+
+	// Give a dedicated job topic the new tier's partitions
+	if err := s.resizeJobTopic(ctx, record, tier.String()); err != nil {
+		return nil, nil, fmt.Errorf("failed to resize job topic: %w", err)
+	}
+
+	revision, template := record.GitOpsRevision, record.Template
+	now := time.Now()
+	err = s.modifyAccount(ctx, record, func(rec *storage.AccountRecord) {
+		rec.PlanTier = tier.String()
+		rec.GitOpsRevision = revision
+		rec.Template = template
+		rec.ExpiryWarnedAt = time.Time{}
+		s.resetTrial(rec, now)
+	}, lifecycleEvent(events.TypeAccountPlanChanged, previousTier))
+	if err != nil {
+		return nil, nil, err
+	}
+	return record, quotaSpec, nil
+}
+
+// ============================================================================
+// Sync Tracking
+// ============================================================================
+
+// errSyncSuperseded aborts activating a tenant that changed since it was
+// checked; a newer commit is checked on the next pass
+var errSyncSuperseded = errors.New("account changed since its sync was checked")
+
+// RunGitOpsSync activates provisioning tenants once their commit is synced,
+// on an interval until ctx is cancelled
+func (s *Service) RunGitOpsSync(ctx context.Context, interval time.Duration) {
+	periodic.Every(ctx, interval, "gitops sync check", s.CheckGitOpsSync)
+}
+
+// CheckGitOpsSync marks every PROVISIONING tenant whose commit the sync
+// controller has applied as ACTIVE and stages its account.created
+func (s *Service) CheckGitOpsSync(ctx context.Context) error {
+	if s.gitops == nil {
+		return nil
+	}
+	records, err := s.accounts.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range records {
+		record := &records[i]
+		if record.Status != storage.AccountStatusProvisioning || record.GitOpsRevision == "" {
+			continue
+		}
+		if err := s.checkGitOpsSync(ctx, record); err != nil {
+			log.Printf("gitops sync check failed for %s: %v", record.OrganizationID, err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) checkGitOpsSync(ctx context.Context, record *storage.AccountRecord) error {
+	cluster, err := s.clusterFor(record)
+	if err != nil {
+		return err
+	}
+	synced, err := s.gitops.Synced(ctx, cluster.Dynamic, cluster.Name, record.GitOpsRevision)
+	if err != nil || !synced {
+		return err
+	}
+
+	activated := *record
+	activated.Status = storage.AccountStatusActive
+	event, err := lifecycleEvent(events.TypeAccountCreated, "")(&activated)
+	if err != nil {
+		return err
+	}
+	_, err = s.accounts.Modify(ctx, record.OrganizationID, func(rec *storage.AccountRecord) error {
+		if rec.Status != storage.AccountStatusProvisioning || rec.GitOpsRevision != record.GitOpsRevision {
+			return errSyncSuperseded
+		}
+		rec.Status = storage.AccountStatusActive
+		return nil
+	}, event)
+	if errors.Is(err, errSyncSuperseded) {
+		return nil
+	}
+	return err
+}
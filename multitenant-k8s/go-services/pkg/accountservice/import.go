@@ -0,0 +1,292 @@
+package accountservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/events"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	// ErrAccountExists is returned when importing an organization that is already registered
+	ErrAccountExists = errors.New("account already registered")
+	// ErrInvalidImport is returned when the namespace cannot be adopted as requested
+	ErrInvalidImport = errors.New("invalid import")
+)
+
+// ImportRequest describes a hand-made namespace to adopt as a tenant
+type ImportRequest struct {
+	OrganizationID   string
+	Namespace        string // Existing namespace; kept under its current name
+	OrganizationType acctv1.OrganizationType
+	PlanTier         acctv1.PlanTier
+	S3Bucket         string
+	Cluster          string // Registry cluster holding the namespace; empty for the default
+	IAMRoleARN       string // Existing role to link; its trust policy must admit tenant-sa. Empty creates tenant-<org>-role
+}
+
+// ImportResult is the outcome of one entry of a bulk import
+type ImportResult struct {
+	OrganizationID string
+	Result         *AccountProvisioningResult
+	Err            error
+}
+
+// ============================================================================
+// Import
+// ============================================================================
+
+// ImportAccount adopts an existing namespace: it labels it, reconciles quota,
+// priority guard, RBAC, service account and network policy to the tier, links or
+// creates the IAM role and registers the account. Nothing that already existed
+// is deleted on failure, and a failed import can simply be retried.
+func (s *Service) ImportAccount(ctx context.Context, req ImportRequest) (*AccountProvisioningResult, error) {
+	orgID, namespace, tier := req.OrganizationID, req.Namespace, req.PlanTier
+	if orgID == "" || namespace == "" {
+		return nil, fmt.Errorf("%w: organization id and namespace are required", ErrInvalidImport)
+	}
+	if _, err := QuotaForTier(tier); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	if _, err := s.accounts.Get(ctx, orgID); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountExists, orgID)
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to look up account: %w", err)
+	}
+
+	cluster, err := s.clusters.Get(req.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	if cluster.Standby {
+		return nil, fmt.Errorf("%w: cluster %s is a standby", ErrInvalidImport, cluster.Name)
+	}
+	kc := cluster.Client
+
+	// 1. Label the namespace as a managed tenant
+	ns, err := kc.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: namespace %s not found on cluster %s", ErrInvalidImport, namespace, cluster.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace: %w", err)
+	}
+	if owner, ok := ns.Labels["tenant-id"]; ok && owner != orgID {
+		return nil, fmt.Errorf("%w: namespace %s belongs to tenant %s", ErrInvalidImport, namespace, owner)
+	}
+	if ns.Labels == nil {
+		ns.Labels = map[string]string{}
+	}
+	if ns.Annotations == nil {
+		ns.Annotations = map[string]string{}
+	}
+	ns.Labels["tenant"] = namespace
+	ns.Labels["tenant-id"] = orgID
+	ns.Labels["plan-tier"] = tier.String()
+	ns.Labels["organization-type"] = req.OrganizationType.String()
+	ns.Labels["managed-by"] = "account-provisioning-service"
+	if _, ok := ns.Labels["created-at"]; !ok {
+		ns.Labels["created-at"] = ns.CreationTimestamp.Format(time.RFC3339)
+	}
+	ns.Annotations["organization-id"] = orgID
+	applyPodSecurityLabels(ns.Labels, s.podSecurityFor(req.OrganizationType, tier))
+	_, err = kc.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+	s.audit.RecordCall(ctx, "kubernetes:UpdateNamespace", orgID, err, "namespace/"+namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to label namespace: %w", err)
+	}
+
+	record := &storage.AccountRecord{
+		OrganizationID:   orgID,
+		Namespace:        namespace,
+		Cluster:          cluster.Name,
+		OrganizationType: req.OrganizationType.String(),
+		PlanTier:         tier.String(),
+		Status:           storage.AccountStatusActive,
+	}
+	result := &AccountProvisioningResult{
+		OrganizationID: orgID,
+		Namespace:      namespace,
+		Cluster:        cluster.Name,
+	}
+
+	// 2. Reconcile quotas to the tier
+	quota, err := s.applyQuotaShares(ctx, kc, record, tier)
+	if err != nil {
+		return nil, err
+	}
+	result.ResourceQuota = quota
+	if err := s.ensurePriorityClasses(ctx, kc); err != nil {
+		return nil, err
+	}
+	if err := s.applyPriorityGuard(ctx, kc, orgID, namespace, tier); err != nil {
+		return nil, err
+	}
+
+	// 3. Link the given IAM role or create the standard one. A linked role is
+	// not ours, so DeleteAccount only takes back the policies put on it
+	roleName := fmt.Sprintf("tenant-%s-role", orgID)
+	if req.IAMRoleARN != "" {
+		roleName = roleNameFromARN(req.IAMRoleARN)
+		out, err := s.iamClient.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(roleName)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to link IAM role %s: %w", roleName, err)
+		}
+		ok, err := trustsServiceAccount(aws.ToString(out.Role.AssumeRolePolicyDocument), namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read trust policy of IAM role %s: %w", roleName, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: IAM role %s does not trust system:serviceaccount:%s:tenant-sa", ErrInvalidImport, roleName, namespace)
+		}
+		record.IAMRoleARN = *out.Role.Arn
+		record.IAMRoleLinked = true
+	} else {
+		record.IAMRoleARN, _, err = s.ensureIAMRole(ctx, orgID, roleName, namespace, cluster.ClusterARN)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result.IAMRoleARN = record.IAMRoleARN
+
+	// 4. Scope the role to the tenant's S3 prefix
+	if req.S3Bucket != "" {
+		if err := s.attachS3Policy(ctx, roleName, req.S3Bucket, orgID); err != nil {
+			return nil, err
+		}
+		record.S3Bucket = req.S3Bucket
+		record.S3Prefix = fmt.Sprintf("orgs/%s", orgID)
+		result.S3Bucket, result.S3Prefix = record.S3Bucket, record.S3Prefix
+	}
+
+	// 5. Service account, RBAC and network policy
+	if err := s.ensureServiceAccount(ctx, kc, orgID, namespace, record.IAMRoleARN); err != nil {
+		return nil, err
+	}
+	if err := s.applyRBAC(ctx, kc, namespace, orgID); err != nil {
+		return nil, err
+	}
+	if err := s.applyNetworkPolicy(ctx, kc, orgID, namespace); err != nil {
+		fmt.Printf("Warning: failed to apply network policy: %v\n", err)
+	}
+
+	// 6. Register the account; consumers see it as created
+	event, err := events.NewAccountEvent(events.TypeAccountCreated, events.AccountData{
+		OrganizationID:   orgID,
+		Namespace:        namespace,
+		OrganizationType: record.OrganizationType,
+		PlanTier:         record.PlanTier,
+		Status:           record.Status,
+		Reason:           "imported from existing namespace",
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.resetTrial(record, time.Now())
+	if err := s.accounts.Save(ctx, record, event); err != nil {
+		return nil, fmt.Errorf("failed to register account: %w", err)
+	}
+	result.CreatedAt = record.CreatedAt
+
+	return result, nil
+}
+
+// ImportAccounts imports each request in order; one failure does not stop the rest
+func (s *Service) ImportAccounts(ctx context.Context, reqs []ImportRequest) []ImportResult {
+	results := make([]ImportResult, 0, len(reqs))
+	for _, req := range reqs {
+		result, err := s.ImportAccount(ctx, req)
+		results = append(results, ImportResult{OrganizationID: req.OrganizationID, Result: result, Err: err})
+	}
+	return results
+}
+
+// trustsServiceAccount reports whether an IAM trust policy lets the tenant's
+// service account assume the role through web identity. The document is the
+// URL-encoded JSON that GetRole returns.
+func trustsServiceAccount(document, namespace string) (bool, error) {
+	decoded, err := url.QueryUnescape(document)
+	if err != nil {
+		return false, err
+	}
+	var policy struct {
+		Statement json.RawMessage
+	}
+	if err := json.Unmarshal([]byte(decoded), &policy); err != nil {
+		return false, err
+	}
+	var statements []struct {
+		Effect    string
+		Action    json.RawMessage
+		Condition map[string]map[string]json.RawMessage
+	}
+	if err := unmarshalOneOrMany(policy.Statement, &statements); err != nil {
+		return false, err
+	}
+
+	subject := fmt.Sprintf("system:serviceaccount:%s:tenant-sa", namespace)
+	for _, st := range statements {
+		var actions []string
+		if st.Effect != "Allow" || unmarshalOneOrMany(st.Action, &actions) != nil {
+			continue
+		}
+		webIdentity := false
+		for _, action := range actions {
+			if action == "sts:AssumeRoleWithWebIdentity" || action == "sts:*" || action == "*" {
+				webIdentity = true
+			}
+		}
+		if !webIdentity {
+			continue
+		}
+		for operator, conditions := range st.Condition {
+			for key, raw := range conditions {
+				var values []string
+				if !strings.HasSuffix(key, ":sub") || unmarshalOneOrMany(raw, &values) != nil {
+					continue
+				}
+				for _, value := range values {
+					switch operator {
+					case "StringEquals":
+						if value == subject {
+							return true, nil
+						}
+					case "StringLike":
+						if ok, _ := path.Match(value, subject); ok {
+							return true, nil
+						}
+					}
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// unmarshalOneOrMany decodes a policy element that may be a single value or a list
+func unmarshalOneOrMany[T any](raw json.RawMessage, out *[]T) error {
+	if len(raw) > 0 && raw[0] != '[' {
+		var one T
+		if err := json.Unmarshal(raw, &one); err != nil {
+			return err
+		}
+		*out = []T{one}
+		return nil
+	}
+	return json.Unmarshal(raw, out)
+}
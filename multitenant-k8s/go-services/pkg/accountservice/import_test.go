@@ -0,0 +1,61 @@
+package accountservice
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestTrustsServiceAccount(t *testing.T) {
+	const sub = "oidc.eks.us-east-1.amazonaws.com/id/ABC:sub"
+
+	tests := []struct {
+		name     string
+		document string
+		want     bool
+	}{
+		{
+			name:     "exact subject",
+			document: `{"Statement": [{"Effect": "Allow", "Action": "sts:AssumeRoleWithWebIdentity", "Condition": {"StringEquals": {"` + sub + `": "system:serviceaccount:tenant-acme:tenant-sa"}}}]}`,
+			want:     true,
+		},
+		{
+			name:     "single statement and subject list",
+			document: `{"Statement": {"Effect": "Allow", "Action": ["sts:AssumeRoleWithWebIdentity"], "Condition": {"StringEquals": {"` + sub + `": ["system:serviceaccount:other:app", "system:serviceaccount:tenant-acme:tenant-sa"]}}}}`,
+			want:     true,
+		},
+		{
+			name:     "wildcard subject",
+			document: `{"Statement": [{"Effect": "Allow", "Action": "sts:AssumeRoleWithWebIdentity", "Condition": {"StringLike": {"` + sub + `": "system:serviceaccount:tenant-acme:*"}}}]}`,
+			want:     true,
+		},
+		{
+			name:     "other namespace",
+			document: `{"Statement": [{"Effect": "Allow", "Action": "sts:AssumeRoleWithWebIdentity", "Condition": {"StringEquals": {"` + sub + `": "system:serviceaccount:tenant-other:tenant-sa"}}}]}`,
+		},
+		{
+			name:     "audience only",
+			document: `{"Statement": [{"Effect": "Allow", "Action": "sts:AssumeRoleWithWebIdentity", "Condition": {"StringEquals": {"oidc.eks.us-east-1.amazonaws.com/id/ABC:aud": "system:serviceaccount:tenant-acme:tenant-sa"}}}]}`,
+		},
+		{
+			name:     "denied",
+			document: `{"Statement": [{"Effect": "Deny", "Action": "sts:AssumeRoleWithWebIdentity", "Condition": {"StringEquals": {"` + sub + `": "system:serviceaccount:tenant-acme:tenant-sa"}}}]}`,
+		},
+		{
+			name:     "not web identity",
+			document: `{"Statement": [{"Effect": "Allow", "Principal": {"Service": "ec2.amazonaws.com"}, "Action": "sts:AssumeRole"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// GetRole returns the document URL-encoded
+			got, err := trustsServiceAccount(url.QueryEscape(tt.document), "tenant-acme")
+			if err != nil {
+				t.Fatalf("trustsServiceAccount: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("trustsServiceAccount = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
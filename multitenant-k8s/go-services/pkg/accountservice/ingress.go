@@ -0,0 +1,228 @@
+package accountservice
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/clusters"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/ingress"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Errors returned by AddCustomDomain
+var (
+	ErrIngressDisabled = errors.New("tenant ingress is not enabled")
+	ErrInvalidDomain   = errors.New("invalid custom domain")
+	ErrDomainTaken     = errors.New("custom domain belongs to another organization")
+)
+
+// ============================================================================
+// Tenant Ingress
+// ============================================================================
+
+// IngressEnabled reports whether tenants can be given a subdomain
+func (s *Service) IngressEnabled() bool {
+	return s.ingress != nil
+}
+
+// enableIngress serves the tenant's subdomain from the shared Gateway and
+// limits how many HTTPRoutes it may create. It returns the subdomain.
+func (s *Service) enableIngress(ctx context.Context, cluster *clusters.Cluster, orgID, namespace, tier string) (string, error) {
+	if s.ingress == nil {
+		return "", ErrIngressDisabled
+	}
+	if err := s.applyRouteQuota(ctx, cluster.Client, orgID, namespace, tier); err != nil {
+		return "", err
+	}
+
+	record := &storage.AccountRecord{OrganizationID: orgID, Namespace: namespace}
+	if err := s.applySites(ctx, cluster, record); err != nil {
+		return "", err
+	}
+	return s.ingress.Hostname(orgID), nil
+}
+
+// applySites renders the tenant's subdomain and verified custom domains
+func (s *Service) applySites(ctx context.Context, cluster *clusters.Cluster, record *storage.AccountRecord) error {
+	sites := []ingress.Site{s.ingress.TenantSite(record.OrganizationID)}
+	for _, d := range record.CustomDomains {
+		if !d.VerifiedAt.IsZero() {
+			sites = append(sites, s.ingress.DomainSite(record.OrganizationID, d.Domain))
+		}
+	}
+
+	err := s.ingress.Apply(ctx, cluster.Dynamic, record.OrganizationID, record.Namespace, sites)
+	s.audit.RecordCall(ctx, "kubernetes:ApplyIngress", record.OrganizationID, err, record.Namespace+"/ingress")
+	if err != nil {
+		return fmt.Errorf("failed to apply ingress: %w", err)
+	}
+	return nil
+}
+
+// removeIngress takes the tenant's listeners off the shared Gateway
+func (s *Service) removeIngress(ctx context.Context, cluster *clusters.Cluster, orgID string) error {
+	if s.ingress == nil {
+		return nil
+	}
+	err := s.ingress.Remove(ctx, cluster.Dynamic, orgID)
+	s.audit.RecordCall(ctx, "kubernetes:RemoveIngress", orgID, err, "gateway/"+orgID)
+	return err
+}
+
+// applyRouteQuota caps the HTTPRoutes in the tenant namespace at the tier's limit
+func (s *Service) applyRouteQuota(ctx context.Context, kc kubernetes.Interface, orgID, namespace, tier string) error {
+	n, ok := ingress.RoutesForTier(tier)
+	if !ok {
+		return fmt.Errorf("no route quota for plan tier %s", tier)
+	}
+
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ingress.RouteQuotaName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"tenant-id": orgID,
+				"plan-tier": tier,
+			},
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{
+				ingress.RouteQuotaResource: *resource.NewQuantity(n, resource.DecimalSI),
+			},
+		},
+	}
+
+	quotas := kc.CoreV1().ResourceQuotas(namespace)
+	existing, err := quotas.Get(ctx, quota.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = quotas.Create(ctx, quota, metav1.CreateOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:CreateResourceQuota", orgID, err, namespace+"/resourcequota/"+quota.Name)
+	} else if err == nil {
+		quota.ResourceVersion = existing.ResourceVersion
+		_, err = quotas.Update(ctx, quota, metav1.UpdateOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:UpdateResourceQuota", orgID, err, namespace+"/resourcequota/"+quota.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply route quota: %w", err)
+	}
+	return nil
+}
+
+// resizeRouteQuota moves the route quota of a tenant with ingress to a new tier
+func (s *Service) resizeRouteQuota(ctx context.Context, kc kubernetes.Interface, record *storage.AccountRecord, tier string) error {
+	if record.IngressHostname == "" {
+		return nil
+	}
+	return s.applyRouteQuota(ctx, kc, record.OrganizationID, record.Namespace, tier)
+}
+
+// ============================================================================
+// Custom Domains
+// ============================================================================
+
+// AddCustomDomain registers a domain for the tenant, or checks a registered
+// one again. The domain is served once a TXT record at
+// _tenant-verification.<domain> holds its verification token; until then the
+// returned domain is unverified.
+func (s *Service) AddCustomDomain(ctx context.Context, orgID, domain string) (*storage.CustomDomain, error) {
+	if s.ingress == nil {
+		return nil, ErrIngressDisabled
+	}
+	domain, err := s.ingress.NormalizeDomain(domain)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDomain, err)
+	}
+
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if record.IngressHostname == "" {
+		return nil, fmt.Errorf("%w for organization %s", ErrIngressDisabled, orgID)
+	}
+
+	// A domain is served for one tenant only
+	records, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		if records[i].OrganizationID == orgID {
+			continue
+		}
+		for _, d := range records[i].CustomDomains {
+			if d.Domain == domain && !d.VerifiedAt.IsZero() {
+				return nil, ErrDomainTaken
+			}
+		}
+	}
+
+	var entry *storage.CustomDomain
+	for i := range record.CustomDomains {
+		if record.CustomDomains[i].Domain == domain {
+			entry = &record.CustomDomains[i]
+		}
+	}
+	if entry == nil {
+		token, err := verificationToken()
+		if err != nil {
+			return nil, err
+		}
+		record.CustomDomains = append(record.CustomDomains, storage.CustomDomain{
+			Domain:            domain,
+			VerificationToken: token,
+			AddedAt:           time.Now().UTC(),
+		})
+		entry = &record.CustomDomains[len(record.CustomDomains)-1]
+	}
+
+	if entry.VerifiedAt.IsZero() {
+		verified, err := s.ingress.Verify(ctx, domain, entry.VerificationToken)
+		if err != nil {
+			return nil, err
+		}
+		if verified {
+			entry.VerifiedAt = time.Now().UTC()
+			cluster, err := s.clusterFor(record)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.applySites(ctx, cluster, record); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := *entry
+	err = s.modifyAccount(ctx, record, func(rec *storage.AccountRecord) {
+		for i := range rec.CustomDomains {
+			if rec.CustomDomains[i].Domain == domain {
+				rec.CustomDomains[i] = result
+				return
+			}
+		}
+		rec.CustomDomains = append(rec.CustomDomains, result)
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func verificationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate verification token: %w", err)
+	}
+	return "tenant-verification=" + hex.EncodeToString(b), nil
+}
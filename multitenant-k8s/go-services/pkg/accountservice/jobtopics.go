@@ -0,0 +1,83 @@
+package accountservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/jobtopics"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+// ErrJobTopicsDisabled is returned when a dedicated job topic is requested but
+// no Kafka cluster is configured
+var ErrJobTopicsDisabled = errors.New("dedicated job topics are not enabled on this server")
+
+// ============================================================================
+// Dedicated Job Topics
+// ============================================================================
+
+// JobTopicsEnabled reports whether tenants can be given a job topic of their own
+func (s *Service) JobTopicsEnabled() bool {
+	return s.jobTopics != nil
+}
+
+// createJobTopic creates the tenant's topic sized for its tier and lets the
+// tenant's principal consume it
+func (s *Service) createJobTopic(ctx context.Context, orgID, tier string) (string, error) {
+	if s.jobTopics == nil {
+		return "", ErrJobTopicsDisabled
+	}
+	n, ok := jobtopics.PartitionsForTier(tier)
+	if !ok {
+		return "", fmt.Errorf("no partition count for plan tier %s", tier)
+	}
+
+	topic := jobtopics.TopicName(orgID)
+	err := s.jobTopics.CreateTopic(ctx, topic, n)
+	s.audit.RecordCall(ctx, "kafka:CreateTopic", orgID, err, "topic/"+topic)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.jobTopics.GrantTopic(ctx, orgID, topic)
+	s.audit.RecordCall(ctx, "kafka:CreateACLs", orgID, err, "topic/"+topic, s.jobTopics.Principal(orgID))
+	if err != nil {
+		s.deleteJobTopic(ctx, orgID, topic)
+		return "", err
+	}
+	return topic, nil
+}
+
+// resizeJobTopic grows the tenant's topic to its new tier's partition count.
+// Downgrades keep the partitions they have.
+func (s *Service) resizeJobTopic(ctx context.Context, record *storage.AccountRecord, tier string) error {
+	if record.JobTopic == "" || s.jobTopics == nil {
+		return nil
+	}
+	n, ok := jobtopics.PartitionsForTier(tier)
+	if !ok {
+		return fmt.Errorf("no partition count for plan tier %s", tier)
+	}
+
+	err := s.jobTopics.GrowTopic(ctx, record.JobTopic, n)
+	s.audit.RecordCall(ctx, "kafka:CreatePartitions", record.OrganizationID, err, "topic/"+record.JobTopic)
+	return err
+}
+
+// deleteJobTopic revokes the tenant's ACLs and deletes its topic
+func (s *Service) deleteJobTopic(ctx context.Context, orgID, topic string) error {
+	if topic == "" || s.jobTopics == nil {
+		return nil
+	}
+
+	err := s.jobTopics.RevokeTopic(ctx, orgID, topic)
+	s.audit.RecordCall(ctx, "kafka:DeleteACLs", orgID, err, "topic/"+topic, s.jobTopics.Principal(orgID))
+	if err != nil {
+		return err
+	}
+
+	err = s.jobTopics.DeleteTopic(ctx, topic)
+	s.audit.RecordCall(ctx, "kafka:DeleteTopic", orgID, err, "topic/"+topic)
+	return err
+}
@@ -0,0 +1,393 @@
+package accountservice
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/clusters"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/events"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// replicasBeforeSuspendAnnotation remembers workload scale so ResumeAccount can restore it
+const replicasBeforeSuspendAnnotation = "account-provisioning/replicas-before-suspend"
+
+// ============================================================================
+// Account Registry
+// ============================================================================
+
+// GetAccount returns the registry entry for an organization
+func (s *Service) GetAccount(ctx context.Context, orgID string) (*storage.AccountRecord, error) {
+	return s.accounts.Get(ctx, orgID)
+}
+
+// ListAccounts returns every registered account, optionally filtered by status
+func (s *Service) ListAccounts(ctx context.Context, status string) ([]storage.AccountRecord, error) {
+	records, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		return records, nil
+	}
+
+	filtered := records[:0]
+	for _, r := range records {
+		if r.Status == status {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered, nil
+}
+
+// clusterFor returns the cluster an account was placed on. Accounts registered
+// before multi-cluster placement have no cluster and live on the default one.
+func (s *Service) clusterFor(record *storage.AccountRecord) (*clusters.Cluster, error) {
+	return s.clusters.Get(record.Cluster)
+}
+
+// saveAccount writes a new record and stages a lifecycle event in one transaction
+func (s *Service) saveAccount(ctx context.Context, record *storage.AccountRecord, eventType, previousTier string) error {
+	event, err := lifecycleEvent(eventType, previousTier)(record)
+	if err != nil {
+		return err
+	}
+	return s.accounts.Save(ctx, record, event)
+}
+
+// modifyAccount applies change to the stored account and stages the event
+// built by event (nil for none) in one transaction. change runs against the
+// latest stored record, again on conflict, so concurrent writers keep each
+// other's fields. The event is built from record with change applied, and
+// record is replaced with what was saved.
+func (s *Service) modifyAccount(ctx context.Context, record *storage.AccountRecord, change func(*storage.AccountRecord), event func(*storage.AccountRecord) (storage.OutboxMessage, error)) error {
+	var msgs []storage.OutboxMessage
+	if event != nil {
+		preview := *record
+		change(&preview)
+		msg, err := event(&preview)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, msg)
+	}
+
+	saved, err := s.accounts.Modify(ctx, record.OrganizationID, func(rec *storage.AccountRecord) error {
+		change(rec)
+		return nil
+	}, msgs...)
+	if err != nil {
+		return fmt.Errorf("failed to save account: %w", err)
+	}
+	*record = *saved
+	return nil
+}
+
+// lifecycleEvent builds the event of a lifecycle change from the account it left
+func lifecycleEvent(eventType, previousTier string) func(*storage.AccountRecord) (storage.OutboxMessage, error) {
+	return func(record *storage.AccountRecord) (storage.OutboxMessage, error) {
+		return events.NewAccountEvent(eventType, events.AccountData{
+			OrganizationID:   record.OrganizationID,
+			Namespace:        record.Namespace,
+			OrganizationType: record.OrganizationType,
+			PlanTier:         record.PlanTier,
+			PreviousPlanTier: previousTier,
+			Status:           record.Status,
+		})
+	}
+}
+
+// ============================================================================
+// Plan Changes
+// ============================================================================
+
+// UpdateAccount moves an organization to a new plan tier and resizes its quota
+func (s *Service) UpdateAccount(ctx context.Context, orgID string, tier acctv1.PlanTier) (*storage.AccountRecord, *acctv1.ResourceQuota, error) {
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	quotaSpec, err := QuotaForTier(tier)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	previousTier := record.PlanTier
+	if previousTier == tier.String() {
+		return record, quotaSpec, nil
+	}
+
+	// Bill the new plan first; changes that came from billing are already recorded
+	if err := s.syncBillingPlan(ctx, orgID, tier); err != nil {
+		return nil, nil, fmt.Errorf("failed to change billing plan: %w", err)
+	}
+
+	// A GitOps tenant is resized by commit
+	if record.GitOpsRevision != "" {
+		return s.updateGitOpsTier(ctx, record, tier, quotaSpec)
+	}
+
+	cluster, err := s.clusterFor(record)
+	if err != nil {
+		return nil, nil, err
+	}
+	kc := cluster.Client
+
+	// Resize the tenant quota, split across any sub-namespaces
+	if _, err := s.applyQuotaShares(ctx, kc, record, tier); err != nil {
+		return nil, nil, err
+	}
+
+	// Move the tenant onto the new tier's PriorityClass
+	if err := s.ensurePriorityClasses(ctx, kc); err != nil {
+		return nil, nil, err
+	}
+	namespaces, err := tenantNamespaces(ctx, kc, record)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ns := range namespaces {
+		if err := s.applyPriorityGuard(ctx, kc, orgID, ns, tier); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Move the HTTPRoute quota to the new tier
+	if err := s.resizeRouteQuota(ctx, kc, record, tier.String()); err != nil {
+		return nil, nil, err
+	}
+
+	// Give a dedicated job topic the new tier's partitions
+	if err := s.resizeJobTopic(ctx, record, tier.String()); err != nil {
+		return nil, nil, fmt.Errorf("failed to resize job topic: %w", err)
+	}
+
+	// Keep the namespace labels in sync for selectors, the usage collector and PSA
+	orgType := acctv1.OrganizationType(acctv1.OrganizationType_value[record.OrganizationType])
+	for _, name := range namespaces {
+		ns, err := kc.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get namespace: %w", err)
+		}
+		ns.Labels["plan-tier"] = tier.String()
+		applyPodSecurityLabels(ns.Labels, s.podSecurityFor(orgType, tier))
+		_, err = kc.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:UpdateNamespace", orgID, err, "namespace/"+name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to update namespace: %w", err)
+		}
+	}
+
+	now := time.Now()
+	err = s.modifyAccount(ctx, record, func(rec *storage.AccountRecord) {
+		rec.PlanTier = tier.String()
+		rec.ExpiryWarnedAt = time.Time{}
+		s.resetTrial(rec, now)
+	}, lifecycleEvent(events.TypeAccountPlanChanged, previousTier))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return record, quotaSpec, nil
+}
+
+// ============================================================================
+// Suspension
+// ============================================================================
+
+// SuspendAccount stops a tenant's workloads without deleting anything: new pods are
+// blocked by a zero-pod quota and deployments/statefulsets are scaled to zero
+func (s *Service) SuspendAccount(ctx context.Context, orgID, reason string) (*storage.AccountRecord, error) {
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if record.Status == storage.AccountStatusSuspended {
+		return record, nil
+	}
+
+	cluster, err := s.clusterFor(record)
+	if err != nil {
+		return nil, err
+	}
+	kc := cluster.Client
+
+	// Sub-namespaces are suspended with the tenant
+	namespaces, err := tenantNamespaces(ctx, kc, record)
+	if err != nil {
+		return nil, err
+	}
+	for _, namespace := range namespaces {
+		if err := s.suspendNamespace(ctx, kc, orgID, namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.modifyAccount(ctx, record, func(rec *storage.AccountRecord) {
+		rec.Status = storage.AccountStatusSuspended
+		rec.SuspendReason = reason
+	}, func(rec *storage.AccountRecord) (storage.OutboxMessage, error) {
+		return events.NewAccountEvent(events.TypeAccountSuspended, events.AccountData{
+			OrganizationID: orgID,
+			Namespace:      rec.Namespace,
+			PlanTier:       rec.PlanTier,
+			Status:         rec.Status,
+			Reason:         reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// ResumeAccount lifts a suspension and restores workloads to their previous scale
+func (s *Service) ResumeAccount(ctx context.Context, orgID string) (*storage.AccountRecord, error) {
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if record.Status != storage.AccountStatusSuspended {
+		return record, nil
+	}
+
+	cluster, err := s.clusterFor(record)
+	if err != nil {
+		return nil, err
+	}
+	kc := cluster.Client
+
+	namespaces, err := tenantNamespaces(ctx, kc, record)
+	if err != nil {
+		return nil, err
+	}
+	for _, namespace := range namespaces {
+		err = kc.CoreV1().ResourceQuotas(namespace).Delete(ctx, "tenant-suspended", metav1.DeleteOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:DeleteResourceQuota", orgID, err, namespace+"/resourcequota/tenant-suspended")
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to delete suspension quota: %w", err)
+		}
+
+		if err := s.scaleWorkloads(ctx, kc, orgID, namespace, false); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.modifyAccount(ctx, record, func(rec *storage.AccountRecord) {
+		rec.Status = storage.AccountStatusActive
+		rec.SuspendReason = ""
+	}, lifecycleEvent(events.TypeAccountResumed, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// suspendNamespace blocks new pods in namespace with a zero-pod quota and
+// scales its workloads to zero
+func (s *Service) suspendNamespace(ctx context.Context, kc kubernetes.Interface, orgID, namespace string) error {
+	blockPods := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "tenant-suspended",
+			Namespace: namespace,
+			Labels: map[string]string{
+				"tenant-id": orgID,
+			},
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{
+				"pods": resource.MustParse("0"),
+			},
+		},
+	}
+	_, err := kc.CoreV1().ResourceQuotas(namespace).Create(ctx, blockPods, metav1.CreateOptions{})
+	s.audit.RecordCall(ctx, "kubernetes:CreateResourceQuota", orgID, err, namespace+"/resourcequota/tenant-suspended")
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create suspension quota: %w", err)
+	}
+
+	return s.scaleWorkloads(ctx, kc, orgID, namespace, true)
+}
+
+// scaleWorkloads scales deployments and statefulsets to zero (suspend) or back to
+// the replica count recorded in their annotation (resume)
+func (s *Service) scaleWorkloads(ctx context.Context, kc kubernetes.Interface, orgID, namespace string, suspend bool) error {
+	deployments, err := kc.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if !rescale(&d.ObjectMeta, &d.Spec.Replicas, suspend) {
+			continue
+		}
+		_, err := kc.AppsV1().Deployments(namespace).Update(ctx, d, metav1.UpdateOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:UpdateDeployment", orgID, err, namespace+"/deployment/"+d.Name)
+		if err != nil {
+			return fmt.Errorf("failed to scale deployment %s: %w", d.Name, err)
+		}
+	}
+
+	statefulSets, err := kc.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for i := range statefulSets.Items {
+		st := &statefulSets.Items[i]
+		if !rescale(&st.ObjectMeta, &st.Spec.Replicas, suspend) {
+			continue
+		}
+		_, err := kc.AppsV1().StatefulSets(namespace).Update(ctx, st, metav1.UpdateOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:UpdateStatefulSet", orgID, err, namespace+"/statefulset/"+st.Name)
+		if err != nil {
+			return fmt.Errorf("failed to scale statefulset %s: %w", st.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// rescale updates replicas and the bookkeeping annotation; it reports whether anything changed
+func rescale(meta *metav1.ObjectMeta, replicas **int32, suspend bool) bool {
+	if suspend {
+		current := int32(1)
+		if *replicas != nil {
+			current = **replicas
+		}
+		if current == 0 {
+			return false
+		}
+		if meta.Annotations == nil {
+			meta.Annotations = map[string]string{}
+		}
+		meta.Annotations[replicasBeforeSuspendAnnotation] = strconv.Itoa(int(current))
+		zero := int32(0)
+		*replicas = &zero
+		return true
+	}
+
+	previous, ok := meta.Annotations[replicasBeforeSuspendAnnotation]
+	if !ok {
+		return false
+	}
+	n, err := strconv.Atoi(previous)
+	if err != nil {
+		return false
+	}
+	restored := int32(n)
+	*replicas = &restored
+	delete(meta.Annotations, replicasBeforeSuspendAnnotation)
+	return true
+}
@@ -0,0 +1,396 @@
+package accountservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/auth"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/identity"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/periodic"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Personas a member can hold; each maps to the tenant-<persona> Role and to
+// the token role of the same name
+var Personas = []string{auth.RoleTenantAdmin, auth.RoleTenantUser, auth.RoleTenantViewer}
+
+// DefaultInvitationTTL is how long an invitation can be accepted
+const DefaultInvitationTTL = 7 * 24 * time.Hour
+
+var (
+	// ErrInvalidMember is returned for malformed emails and unknown personas
+	ErrInvalidMember = errors.New("invalid member")
+	// ErrMemberExists is returned when inviting someone who is already a member
+	ErrMemberExists = errors.New("already a member")
+	// ErrInvitationsDisabled is returned when no invitation signing key is configured
+	ErrInvitationsDisabled = errors.New("invitations are not configured")
+	// ErrInvalidInvitation is returned for forged, expired, used or revoked invitations
+	ErrInvalidInvitation = errors.New("invalid invitation")
+)
+
+// MemberGroup is the IdP group (and Kubernetes group) holding an
+// organization's members with one persona, e.g. "tenant-acme-admin"
+func MemberGroup(orgID, persona string) string {
+	return fmt.Sprintf("tenant-%s-%s", orgID, persona)
+}
+
+// memberBindingName is the RoleBinding granting a persona's Role to its members
+func memberBindingName(persona string) string {
+	return "tenant-" + persona + "-members"
+}
+
+// ============================================================================
+// Invitations
+// ============================================================================
+
+// InviteMember records an invitation and returns it with the signed token to
+// send to the invitee. The token is not stored.
+func (s *Service) InviteMember(ctx context.Context, orgID, email, persona, invitedBy string) (*storage.InvitationRecord, string, error) {
+	if s.invitationSigner == nil {
+		return nil, "", ErrInvitationsDisabled
+	}
+	email, err := normalizeMember(email, persona)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := s.accounts.Get(ctx, orgID); err != nil {
+		return nil, "", err
+	}
+	if _, err := s.members.Get(ctx, orgID, email); err == nil {
+		return nil, "", fmt.Errorf("%w: %s", ErrMemberExists, email)
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return nil, "", fmt.Errorf("failed to look up member: %w", err)
+	}
+
+	id, err := auth.NewInvitationID()
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now().UTC()
+	record := &storage.InvitationRecord{
+		ID:             id,
+		OrganizationID: orgID,
+		Email:          email,
+		Persona:        persona,
+		InvitedBy:      invitedBy,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(s.invitationTTL),
+	}
+	err = s.invitations.Save(ctx, record)
+	s.audit.RecordCall(ctx, "members:Invite", orgID, err, "invitation/"+id, "member/"+email)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to save invitation: %w", err)
+	}
+
+	return record, s.invitationSigner.Sign(id, record.ExpiresAt), nil
+}
+
+// AcceptInvitation verifies an invitation token and adds the invitee as a
+// member. subject is the token subject of the caller, if authenticated.
+func (s *Service) AcceptInvitation(ctx context.Context, token, subject string) (*storage.MemberRecord, error) {
+	if s.invitationSigner == nil {
+		return nil, ErrInvitationsDisabled
+	}
+	now := time.Now().UTC()
+	id, err := s.invitationSigner.Verify(token, now)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInvitation, err)
+	}
+	invitation, err := s.invitations.Get(ctx, id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("%w: unknown invitation", ErrInvalidInvitation)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up invitation: %w", err)
+	}
+	if !invitation.Pending(now) {
+		return nil, fmt.Errorf("%w: already used or revoked", ErrInvalidInvitation)
+	}
+	orgID := invitation.OrganizationID
+	if _, err := s.accounts.Get(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	member := &storage.MemberRecord{
+		OrganizationID: orgID,
+		Email:          invitation.Email,
+		Persona:        invitation.Persona,
+		Subject:        subject,
+		InvitationID:   invitation.ID,
+		InvitedBy:      invitation.InvitedBy,
+		AddedAt:        now,
+	}
+	err = s.members.Save(ctx, member)
+	s.audit.RecordCall(ctx, "members:Add", orgID, err, "member/"+member.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save member: %w", err)
+	}
+	invitation.AcceptedAt = now
+	if err := s.invitations.Save(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("failed to mark invitation accepted: %w", err)
+	}
+
+	// Membership is recorded; the periodic sync retries the rollout if this fails
+	if err := s.ReconcileMembers(ctx, orgID); err != nil {
+		log.Printf("Warning: failed to reconcile members of %s: %v", orgID, err)
+	}
+	return member, nil
+}
+
+// ============================================================================
+// Members
+// ============================================================================
+
+// ListMembers returns an organization's members and its pending invitations
+func (s *Service) ListMembers(ctx context.Context, orgID string) ([]storage.MemberRecord, []storage.InvitationRecord, error) {
+	if _, err := s.accounts.Get(ctx, orgID); err != nil {
+		return nil, nil, err
+	}
+	members, err := s.members.List(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	invitations, err := s.invitations.List(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	var pending []storage.InvitationRecord
+	for _, inv := range invitations {
+		if inv.Pending(now) {
+			pending = append(pending, inv)
+		}
+	}
+	return members, pending, nil
+}
+
+// RemoveMember revokes a user's membership and their pending invitations, and
+// removes them from the tenant's RoleBindings and IdP groups. A user who was
+// only invited has their invitations revoked. Returns ErrNotFound when the user
+// is neither a member nor invited.
+func (s *Service) RemoveMember(ctx context.Context, orgID, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	_, err := s.members.Get(ctx, orgID, email)
+	member := err == nil
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return err
+	}
+	if member {
+		err := s.members.Delete(ctx, orgID, email)
+		s.audit.RecordCall(ctx, "members:Remove", orgID, err, "member/"+email)
+		if err != nil {
+			return fmt.Errorf("failed to remove member: %w", err)
+		}
+	}
+
+	invitations, err := s.invitations.List(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	revoked := 0
+	for i := range invitations {
+		if inv := &invitations[i]; inv.Email == email && inv.Pending(now) {
+			inv.RevokedAt = now
+			err := s.invitations.Save(ctx, inv)
+			s.audit.RecordCall(ctx, "invitations:Revoke", orgID, err, "invitation/"+inv.ID)
+			if err != nil {
+				return fmt.Errorf("failed to revoke invitation %s: %w", inv.ID, err)
+			}
+			revoked++
+		}
+	}
+
+	if !member {
+		if revoked == 0 {
+			return storage.ErrNotFound
+		}
+		return nil
+	}
+	return s.ReconcileMembers(ctx, orgID)
+}
+
+// deleteMembers removes every membership and invitation of a deleted tenant and
+// empties its IdP groups
+func (s *Service) deleteMembers(ctx context.Context, orgID string) error {
+	members, err := s.members.List(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if err := s.members.Delete(ctx, orgID, m.Email); err != nil {
+			return fmt.Errorf("failed to remove member %s: %w", m.Email, err)
+		}
+	}
+	invitations, err := s.invitations.List(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	for _, inv := range invitations {
+		if err := s.invitations.Delete(ctx, inv.ID); err != nil {
+			return fmt.Errorf("failed to delete invitation %s: %w", inv.ID, err)
+		}
+	}
+	if len(members) > 0 {
+		s.audit.RecordCall(ctx, "members:RemoveAll", orgID, nil)
+	}
+	return s.syncGroups(ctx, orgID, nil)
+}
+
+// ============================================================================
+// Reconciliation
+// ============================================================================
+
+// RunMemberSync reconciles every tenant's membership on an interval until ctx
+// is cancelled, repairing drift and retrying failed rollouts
+func (s *Service) RunMemberSync(ctx context.Context, interval time.Duration) {
+	periodic.Every(ctx, interval, "member sync", s.reconcileAllMembers)
+}
+
+// reconcileAllMembers reconciles the membership of every account, logging
+// per-account failures
+func (s *Service) reconcileAllMembers(ctx context.Context) error {
+	records, err := s.accounts.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := s.ReconcileMembers(ctx, record.OrganizationID); err != nil {
+			log.Printf("member sync failed for %s: %v", record.OrganizationID, err)
+		}
+	}
+	return nil
+}
+
+// ReconcileMembers writes an organization's membership to one RoleBinding per
+// persona in every tenant namespace and to the persona groups in the IdP
+func (s *Service) ReconcileMembers(ctx context.Context, orgID string) error {
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	members, err := s.members.List(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	byPersona := map[string][]string{}
+	for _, m := range members {
+		byPersona[m.Persona] = append(byPersona[m.Persona], m.Email)
+	}
+
+	cluster, err := s.clusters.Get(record.Cluster)
+	if err != nil {
+		return err
+	}
+	kc := cluster.Client
+	namespaces, err := tenantNamespaces(ctx, kc, record)
+	if err != nil {
+		return err
+	}
+	for _, ns := range namespaces {
+		// The persona Roles must exist before they are bound
+		if err := s.applyRBAC(ctx, kc, ns, orgID); err != nil {
+			return err
+		}
+		for _, persona := range Personas {
+			if err := s.applyMemberBinding(ctx, kc, orgID, ns, persona, byPersona[persona]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.syncGroups(ctx, orgID, byPersona)
+}
+
+// applyMemberBinding creates or updates the RoleBinding of one persona. Members
+// are bound by user name (their email) and through their IdP group, so access
+// works with either OIDC claim mapping.
+func (s *Service) applyMemberBinding(ctx context.Context, kc kubernetes.Interface, orgID, namespace, persona string, emails []string) error {
+	subjects := []rbacv1.Subject{{
+		Kind:     rbacv1.GroupKind,
+		APIGroup: rbacv1.GroupName,
+		Name:     MemberGroup(orgID, persona),
+	}}
+	for _, email := range emails {
+		subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: email})
+	}
+	desired := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      memberBindingName(persona),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"tenant-id":  orgID,
+				"persona":    persona,
+				"managed-by": "account-provisioning-service",
+			},
+		},
+		Subjects: subjects,
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     "tenant-" + persona,
+		},
+	}
+
+	name := desired.Name
+	existing, err := kc.RbacV1().RoleBindings(namespace).Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = kc.RbacV1().RoleBindings(namespace).Create(ctx, desired, metav1.CreateOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:CreateRoleBinding", orgID, err, namespace+"/rolebinding/"+name)
+	case err == nil && !equality.Semantic.DeepEqual(existing.Subjects, desired.Subjects):
+		existing.Subjects = desired.Subjects
+		_, err = kc.RbacV1().RoleBindings(namespace).Update(ctx, existing, metav1.UpdateOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:UpdateRoleBinding", orgID, err, namespace+"/rolebinding/"+name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply role binding %s: %w", name, err)
+	}
+	return nil
+}
+
+// syncGroups pushes membership to the IdP persona groups, if an IdP is configured
+func (s *Service) syncGroups(ctx context.Context, orgID string, byPersona map[string][]string) error {
+	if s.groups == nil {
+		return nil
+	}
+	for _, persona := range Personas {
+		group := MemberGroup(orgID, persona)
+		err := s.groups.SyncGroup(ctx, group, byPersona[persona])
+		s.audit.RecordCall(ctx, "idp:SyncGroup", orgID, err, "group/"+group)
+		if errors.Is(err, identity.ErrUnknownUsers) {
+			// Invitees who have not signed in to the IdP yet are added on a later sync
+			log.Printf("Warning: %v", err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to sync group %s: %w", group, err)
+		}
+	}
+	return nil
+}
+
+// normalizeMember validates an invitee and returns the lower-cased email
+func normalizeMember(email, persona string) (string, error) {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != strings.TrimSpace(email) {
+		return "", fmt.Errorf("%w: %q is not an email address", ErrInvalidMember, email)
+	}
+	if !slices.Contains(Personas, persona) {
+		return "", fmt.Errorf("%w: persona must be one of %s", ErrInvalidMember, strings.Join(Personas, ", "))
+	}
+	return strings.ToLower(addr.Address), nil
+}
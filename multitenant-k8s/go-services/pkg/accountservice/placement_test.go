@@ -0,0 +1,67 @@
+package accountservice
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/clusters"
+)
+
+func TestPlaceTenant(t *testing.T) {
+	cluster := func(name string, standby bool, tiers ...string) *clusters.Cluster {
+		return &clusters.Cluster{
+			Spec:   clusters.Spec{Name: name, Standby: standby, Tiers: tiers},
+			Client: fake.NewSimpleClientset(),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		clusters []*clusters.Cluster
+		tier     acctv1.PlanTier
+		want     string // Empty when placement must fail
+	}{
+		{
+			name:     "single cluster is used without capacity",
+			clusters: []*clusters.Cluster{cluster("main", false)},
+			tier:     acctv1.PlanTier_PLAN_TIER_PRO,
+			want:     "main",
+		},
+		{
+			name:     "single cluster of the tier",
+			clusters: []*clusters.Cluster{cluster("main", false, "PLAN_TIER_PRO"), cluster("dr", true)},
+			tier:     acctv1.PlanTier_PLAN_TIER_PRO,
+			want:     "main",
+		},
+		{
+			name:     "single cluster of another tier",
+			clusters: []*clusters.Cluster{cluster("free", false, "PLAN_TIER_FREE")},
+			tier:     acctv1.PlanTier_PLAN_TIER_PRO,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, err := clusters.NewRegistry(tt.clusters...)
+			if err != nil {
+				t.Fatalf("NewRegistry: %v", err)
+			}
+			s := &Service{clusters: registry}
+
+			got, err := s.placeTenant(context.Background(), tt.tier, "")
+			switch {
+			case tt.want == "":
+				if err == nil {
+					t.Fatalf("placeTenant = %s, want error", got.Name)
+				}
+			case err != nil:
+				t.Fatalf("placeTenant: %v", err)
+			case got.Name != tt.want:
+				t.Errorf("placeTenant = %s, want %s", got.Name, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package accountservice
+
+import (
+	"fmt"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+)
+
+// Pod Security Admission levels
+const (
+	PodSecurityPrivileged = "privileged"
+	PodSecurityBaseline   = "baseline"
+	PodSecurityRestricted = "restricted"
+)
+
+// PodSecurity is the set of PSA modes applied to a tenant namespace
+type PodSecurity struct {
+	Enforce string
+	Audit   string
+	Warn    string
+}
+
+// ValidatePodSecurityLevel rejects values PSA does not understand
+func ValidatePodSecurityLevel(level string) error {
+	switch level {
+	case PodSecurityPrivileged, PodSecurityBaseline, PodSecurityRestricted:
+		return nil
+	default:
+		return fmt.Errorf("invalid pod security level %q", level)
+	}
+}
+
+// podSecurityFor returns the PSA levels for a tenant. Shared-node tiers are held
+// to restricted (FREE/STARTER) or baseline (PRO, and ENTERPRISE on shared nodes),
+// which already forbid hostPath, hostNetwork and privileged pods. Only ENTERPRISE
+// tenants on dedicated nodes or clusters get the configurable enforce level.
+func (s *Service) podSecurityFor(orgType acctv1.OrganizationType, tier acctv1.PlanTier) PodSecurity {
+	switch tier {
+	case acctv1.PlanTier_PLAN_TIER_FREE, acctv1.PlanTier_PLAN_TIER_STARTER:
+		return PodSecurity{Enforce: PodSecurityRestricted, Audit: PodSecurityRestricted, Warn: PodSecurityRestricted}
+	case acctv1.PlanTier_PLAN_TIER_ENTERPRISE:
+		if orgType == acctv1.OrganizationType_ORGANIZATION_TYPE_NODE || orgType == acctv1.OrganizationType_ORGANIZATION_TYPE_CLUSTER {
+			return PodSecurity{Enforce: s.enterprisePodSecurity, Audit: PodSecurityRestricted, Warn: PodSecurityRestricted}
+		}
+	}
+	return PodSecurity{Enforce: PodSecurityBaseline, Audit: PodSecurityRestricted, Warn: PodSecurityRestricted}
+}
+
+// applyPodSecurityLabels sets the pod-security.kubernetes.io labels on a namespace label map
+func applyPodSecurityLabels(labels map[string]string, ps PodSecurity) {
+	for mode, level := range map[string]string{"enforce": ps.Enforce, "audit": ps.Audit, "warn": ps.Warn} {
+		labels["pod-security.kubernetes.io/"+mode] = level
+		labels["pod-security.kubernetes.io/"+mode+"-version"] = "latest"
+	}
+}
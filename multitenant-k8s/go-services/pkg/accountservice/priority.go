@@ -0,0 +1,114 @@
+package accountservice
+
+import (
+	"context"
+	"fmt"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/priority"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// priorityGuardQuota is the quota that stops a tenant from using other tiers' classes
+const priorityGuardQuota = "tenant-priority-guard"
+
+// ensurePriorityClasses creates the per-tier PriorityClasses on a cluster if missing.
+// PriorityClass values are immutable, so existing classes are left as they are.
+func (s *Service) ensurePriorityClasses(ctx context.Context, kc kubernetes.Interface) error {
+	for _, c := range priority.All() {
+		policy := corev1.PreemptLowerPriority
+		if !c.Preempt {
+			policy = corev1.PreemptNever
+		}
+		pc := &schedulingv1.PriorityClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: c.Name,
+				Labels: map[string]string{
+					"managed-by": "account-provisioning-service",
+				},
+			},
+			Value:            c.Value,
+			PreemptionPolicy: &policy,
+			Description:      c.Description,
+		}
+
+		_, err := kc.SchedulingV1().PriorityClasses().Create(ctx, pc, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			continue
+		}
+		s.audit.RecordCall(ctx, "kubernetes:CreatePriorityClass", "", err, "priorityclass/"+c.Name)
+		if err != nil {
+			return fmt.Errorf("failed to create priority class %s: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// applyPriorityGuard creates or updates a zero-pod quota scoped to every
+// PriorityClass but the tier's own, so pods in the namespace may use only their
+// own tier's class (or none)
+func (s *Service) applyPriorityGuard(ctx context.Context, kc kubernetes.Interface, orgID, namespace string, tier acctv1.PlanTier) error {
+	desired := priorityGuardObject(namespace, tier)
+
+	existing, err := kc.CoreV1().ResourceQuotas(namespace).Get(ctx, priorityGuardQuota, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = kc.CoreV1().ResourceQuotas(namespace).Create(ctx, desired, metav1.CreateOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:CreateResourceQuota", orgID, err, namespace+"/resourcequota/"+priorityGuardQuota)
+		if err != nil {
+			return fmt.Errorf("failed to create priority guard quota: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get priority guard quota: %w", err)
+	}
+
+	existing.Labels = desired.Labels
+	existing.Spec = desired.Spec
+	_, err = kc.CoreV1().ResourceQuotas(namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	s.audit.RecordCall(ctx, "kubernetes:UpdateResourceQuota", orgID, err, namespace+"/resourcequota/"+priorityGuardQuota)
+	if err != nil {
+		return fmt.Errorf("failed to update priority guard quota: %w", err)
+	}
+	return nil
+}
+
+// priorityGuardObject builds the zero-pod quota on every PriorityClass but the
+// tier's own. The scope also requires a class, since NotIn alone would match
+// pods that set none.
+func priorityGuardObject(namespace string, tier acctv1.PlanTier) *corev1.ResourceQuota {
+	own, _ := priority.ForTier(tier.String())
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      priorityGuardQuota,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"plan-tier": tier.String(),
+			},
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{
+				"pods": resource.MustParse("0"),
+			},
+			ScopeSelector: &corev1.ScopeSelector{
+				MatchExpressions: []corev1.ScopedResourceSelectorRequirement{
+					{
+						ScopeName: corev1.ResourceQuotaScopePriorityClass,
+						Operator:  corev1.ScopeSelectorOpNotIn,
+						Values:    []string{own.Name},
+					},
+					{
+						ScopeName: corev1.ResourceQuotaScopePriorityClass,
+						Operator:  corev1.ScopeSelectorOpExists,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,464 @@
+package accountservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/periodic"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// RegistryPullSecret is the dockerconfigjson secret holding a tenant's
+	// registry credentials in each of its namespaces
+	RegistryPullSecret = "tenant-registry"
+
+	registryPolicyName          = "tenant-ecr-access"
+	registryExpiresAtAnnotation = "account-provisioning/registry-expires-at"
+)
+
+// ErrRegistryDisabled is returned when a tenant registry is requested but no
+// pull role is configured
+var ErrRegistryDisabled = errors.New("container registries are not enabled on this server")
+
+// Actions tenants get on the repositories under their prefix
+var (
+	registryPullActions = []string{
+		"ecr:BatchCheckLayerAvailability",
+		"ecr:BatchGetImage",
+		"ecr:GetDownloadUrlForLayer",
+	}
+	registryPushActions = []string{
+		"ecr:CompleteLayerUpload",
+		"ecr:InitiateLayerUpload",
+		"ecr:PutImage",
+		"ecr:UploadLayerPart",
+	}
+	registryManageActions = []string{
+		"ecr:CreateRepository",
+		"ecr:DescribeImages",
+		"ecr:DescribeRepositories",
+		"ecr:ListImages",
+	}
+)
+
+// RegistryEnabled reports whether tenants can be given a container registry
+func (s *Service) RegistryEnabled() bool {
+	return s.registryPullRole != ""
+}
+
+// registryPrefix is the repository name prefix reserved for a tenant
+func registryPrefix(orgID string) string {
+	return "tenants/" + orgID
+}
+
+// registryRepositoryARN matches every repository under the tenant's prefix
+func (s *Service) registryRepositoryARN(orgID string) string {
+	return fmt.Sprintf("arn:%s:ecr:%s:%s:repository/%s/*",
+		s.registryPartition, s.awsConfig.Region, s.registryAccount, registryPrefix(orgID))
+}
+
+// ============================================================================
+// Provisioning
+// ============================================================================
+
+// enableRegistry lets the tenant role use the tenant's repository prefix and
+// installs the pull secret in namespace. It returns the prefix qualified with
+// the registry host.
+func (s *Service) enableRegistry(ctx context.Context, kc kubernetes.Interface, orgID, roleName, namespace string) (string, error) {
+	if !s.RegistryEnabled() {
+		return "", ErrRegistryDisabled
+	}
+	if err := s.attachRegistryPolicy(ctx, roleName, orgID); err != nil {
+		return "", err
+	}
+	host, err := s.refreshPullSecret(ctx, kc, orgID, []string{namespace})
+	if err != nil {
+		return "", err
+	}
+	return host + "/" + registryPrefix(orgID), nil
+}
+
+// attachRegistryPolicy gives the tenant role pull, push and create access to
+// the repositories under its prefix
+func (s *Service) attachRegistryPolicy(ctx context.Context, roleName, orgID string) error {
+	repositories := s.registryRepositoryARN(orgID)
+	policyDocument := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{
+				// Tokens carry no scope; the statement below limits what they reach
+				"Effect":   "Allow",
+				"Action":   "ecr:GetAuthorizationToken",
+				"Resource": "*",
+			},
+			{
+				"Effect":   "Allow",
+				"Action":   concatActions(registryPullActions, registryPushActions, registryManageActions),
+				"Resource": repositories,
+			},
+		},
+	}
+
+	policyJSON, err := json.Marshal(policyDocument)
+	if err != nil {
+		return fmt.Errorf("failed to marshal policy: %w", err)
+	}
+
+	_, err = s.iamClient.PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyName:     aws.String(registryPolicyName),
+		PolicyDocument: aws.String(string(policyJSON)),
+	})
+	s.audit.RecordCall(ctx, "iam:PutRolePolicy", orgID, err, roleName+"/"+registryPolicyName)
+	if err != nil {
+		return fmt.Errorf("failed to attach registry policy: %w", err)
+	}
+	return nil
+}
+
+// deleteRegistryPolicy removes the tenant's inline registry policy. Roles
+// without one are left alone.
+func (s *Service) deleteRegistryPolicy(ctx context.Context, orgID, roleName string) error {
+	_, err := s.iamClient.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{
+		RoleName:   aws.String(roleName),
+		PolicyName: aws.String(registryPolicyName),
+	})
+	var missing *iamtypes.NoSuchEntityException
+	if errors.As(err, &missing) {
+		return nil
+	}
+	s.audit.RecordCall(ctx, "iam:DeleteRolePolicy", orgID, err, roleName+"/"+registryPolicyName)
+	return err
+}
+
+func concatActions(groups ...[]string) []string {
+	var actions []string
+	for _, g := range groups {
+		actions = append(actions, g...)
+	}
+	return actions
+}
+
+// ============================================================================
+// Pull Secrets
+// ============================================================================
+
+// refreshPullSecret mints registry credentials that can only pull the
+// tenant's repositories, stores them in every namespace and attaches them to
+// the namespaces' service accounts. It returns the registry host.
+func (s *Service) refreshPullSecret(ctx context.Context, kc kubernetes.Interface, orgID string, namespaces []string) (string, error) {
+	host, dockerConfig, expiresAt, err := s.pullCredentials(ctx, orgID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, ns := range namespaces {
+		if err := s.applyPullSecret(ctx, kc, orgID, ns, dockerConfig, expiresAt); err != nil {
+			return "", err
+		}
+		for _, sa := range []string{"tenant-sa", "default"} {
+			if err := s.attachPullSecret(ctx, kc, orgID, ns, sa); err != nil {
+				return "", err
+			}
+		}
+	}
+	return host, nil
+}
+
+// pullCredentials assumes the pull role with a session policy limited to the
+// tenant's repositories and exchanges the session for a registry token
+func (s *Service) pullCredentials(ctx context.Context, orgID string) (host string, dockerConfig []byte, expiresAt time.Time, err error) {
+	sessionPolicy, err := json.Marshal(map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{"Effect": "Allow", "Action": "ecr:GetAuthorizationToken", "Resource": "*"},
+			{"Effect": "Allow", "Action": registryPullActions, "Resource": s.registryRepositoryARN(orgID)},
+		},
+	})
+	if err != nil {
+		return "", nil, time.Time{}, fmt.Errorf("failed to marshal session policy: %w", err)
+	}
+
+	session := "registry-" + orgID
+	if len(session) > 64 {
+		session = session[:64]
+	}
+	assumed, err := sts.NewFromConfig(s.awsConfig).AssumeRole(ctx, &sts.AssumeRoleInput{
+		RoleArn:         aws.String(s.registryPullRole),
+		RoleSessionName: aws.String(session),
+		Policy:          aws.String(string(sessionPolicy)),
+	})
+	if err != nil {
+		return "", nil, time.Time{}, fmt.Errorf("failed to assume registry pull role: %w", err)
+	}
+	creds := assumed.Credentials
+
+	client := ecr.NewFromConfig(s.awsConfig, func(o *ecr.Options) {
+		o.Credentials = aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{
+				AccessKeyID:     aws.ToString(creds.AccessKeyId),
+				SecretAccessKey: aws.ToString(creds.SecretAccessKey),
+				SessionToken:    aws.ToString(creds.SessionToken),
+				CanExpire:       true,
+				Expires:         aws.ToTime(creds.Expiration),
+			}, nil
+		})
+	})
+	out, err := client.GetAuthorizationToken(ctx, &ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return "", nil, time.Time{}, fmt.Errorf("failed to get registry token: %w", err)
+	}
+	if len(out.AuthorizationData) == 0 {
+		return "", nil, time.Time{}, errors.New("registry returned no authorization data")
+	}
+	auth := out.AuthorizationData[0]
+
+	// The token already is base64("AWS:<password>"), the form docker expects
+	host = strings.TrimPrefix(aws.ToString(auth.ProxyEndpoint), "https://")
+	dockerConfig, err = json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			host: map[string]string{"auth": aws.ToString(auth.AuthorizationToken)},
+		},
+	})
+	if err != nil {
+		return "", nil, time.Time{}, fmt.Errorf("failed to marshal docker config: %w", err)
+	}
+
+	// The token stops working with the session it was minted from
+	expiresAt = aws.ToTime(auth.ExpiresAt)
+	if sessionEnd := aws.ToTime(creds.Expiration); !sessionEnd.IsZero() && sessionEnd.Before(expiresAt) {
+		expiresAt = sessionEnd
+	}
+	return host, dockerConfig, expiresAt, nil
+}
+
+// applyPullSecret creates or replaces the pull secret in namespace
+func (s *Service) applyPullSecret(ctx context.Context, kc kubernetes.Interface, orgID, namespace string, dockerConfig []byte, expiresAt time.Time) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RegistryPullSecret,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"tenant-id": orgID,
+			},
+			Annotations: map[string]string{
+				registryExpiresAtAnnotation: expiresAt.UTC().Format(time.RFC3339),
+			},
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig},
+	}
+
+	secrets := kc.CoreV1().Secrets(namespace)
+	_, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:UpdateSecret", orgID, err, namespace+"/secret/"+RegistryPullSecret)
+	} else {
+		s.audit.RecordCall(ctx, "kubernetes:CreateSecret", orgID, err, namespace+"/secret/"+RegistryPullSecret)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply pull secret in %s: %w", namespace, err)
+	}
+	return nil
+}
+
+// attachPullSecret adds the pull secret to a service account's
+// imagePullSecrets. A missing default service account is created, since the
+// token controller may not have caught up with a new namespace yet; other
+// missing accounts are skipped.
+func (s *Service) attachPullSecret(ctx context.Context, kc kubernetes.Interface, orgID, namespace, name string) error {
+	accounts := kc.CoreV1().ServiceAccounts(namespace)
+	ref := corev1.LocalObjectReference{Name: RegistryPullSecret}
+
+	sa, err := accounts.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if name != "default" {
+			return nil
+		}
+		sa = &corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: name, Namespace: namespace},
+			ImagePullSecrets: []corev1.LocalObjectReference{ref},
+		}
+		_, err = accounts.Create(ctx, sa, metav1.CreateOptions{})
+		if !apierrors.IsAlreadyExists(err) {
+			s.audit.RecordCall(ctx, "kubernetes:CreateServiceAccount", orgID, err, namespace+"/serviceaccount/"+name)
+			if err != nil {
+				return fmt.Errorf("failed to create service account %s: %w", name, err)
+			}
+			return nil
+		}
+		sa, err = accounts.Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get service account %s: %w", name, err)
+	}
+
+	for _, existing := range sa.ImagePullSecrets {
+		if existing.Name == RegistryPullSecret {
+			return nil
+		}
+	}
+	sa.ImagePullSecrets = append(sa.ImagePullSecrets, ref)
+	_, err = accounts.Update(ctx, sa, metav1.UpdateOptions{})
+	s.audit.RecordCall(ctx, "kubernetes:UpdateServiceAccount", orgID, err, namespace+"/serviceaccount/"+name)
+	if err != nil {
+		return fmt.Errorf("failed to update service account %s: %w", name, err)
+	}
+	return nil
+}
+
+// ============================================================================
+// Refresh Loop
+// ============================================================================
+
+// RunRegistryRefresh renews pull secrets and repository policies on an
+// interval until ctx is cancelled
+func (s *Service) RunRegistryRefresh(ctx context.Context, interval time.Duration) {
+	periodic.Every(ctx, interval, "registry refresh", s.RefreshRegistries)
+}
+
+// RefreshRegistries renews the pull secret of every tenant with a registry,
+// covering namespaces added since the last run, and grants each tenant role
+// the repositories created under its prefix
+func (s *Service) RefreshRegistries(ctx context.Context) error {
+	records, err := s.accounts.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var tenants []*storage.AccountRecord
+	for i := range records {
+		if records[i].Registry != "" {
+			tenants = append(tenants, &records[i])
+		}
+	}
+	if len(tenants) == 0 {
+		return nil
+	}
+
+	repositories, err := s.listTenantRepositories(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range tenants {
+		if err := s.refreshRegistry(ctx, record, repositories[record.OrganizationID]); err != nil {
+			log.Printf("registry refresh failed for %s: %v", record.OrganizationID, err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) refreshRegistry(ctx context.Context, record *storage.AccountRecord, repositories []string) error {
+	cluster, err := s.clusters.Get(record.Cluster)
+	if err != nil {
+		return err
+	}
+	namespaces, err := tenantNamespaces(ctx, cluster.Client, record)
+	if err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	if _, err := s.refreshPullSecret(ctx, cluster.Client, record.OrganizationID, namespaces); err != nil {
+		return err
+	}
+
+	for _, repo := range repositories {
+		if err := s.applyRepositoryPolicy(ctx, record.OrganizationID, repo, record.IAMRoleARN); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listTenantRepositories returns the repository names under tenants/ by organization ID
+func (s *Service) listTenantRepositories(ctx context.Context) (map[string][]string, error) {
+	byOrg := map[string][]string{}
+	pages := ecr.NewDescribeRepositoriesPaginator(ecr.NewFromConfig(s.awsConfig), &ecr.DescribeRepositoriesInput{})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repositories: %w", err)
+		}
+		for _, repo := range page.Repositories {
+			name := aws.ToString(repo.RepositoryName)
+			rest, ok := strings.CutPrefix(name, "tenants/")
+			if !ok {
+				continue
+			}
+			if orgID, _, ok := strings.Cut(rest, "/"); ok {
+				byOrg[orgID] = append(byOrg[orgID], name)
+			}
+		}
+	}
+	return byOrg, nil
+}
+
+// applyRepositoryPolicy grants the tenant role pull and push on one of its
+// repositories, leaving policies that already do so untouched
+func (s *Service) applyRepositoryPolicy(ctx context.Context, orgID, repository, roleARN string) error {
+	policy := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{
+				"Sid":       "TenantAccess",
+				"Effect":    "Allow",
+				"Principal": map[string]string{"AWS": roleARN},
+				"Action":    concatActions(registryPullActions, registryPushActions),
+			},
+		},
+	}
+	policyJSON, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to marshal repository policy: %w", err)
+	}
+
+	client := ecr.NewFromConfig(s.awsConfig)
+	current, err := client.GetRepositoryPolicy(ctx, &ecr.GetRepositoryPolicyInput{RepositoryName: aws.String(repository)})
+	var missing *ecrtypes.RepositoryPolicyNotFoundException
+	switch {
+	case errors.As(err, &missing):
+	case err != nil:
+		return fmt.Errorf("failed to get policy of repository %s: %w", repository, err)
+	case samePolicy(aws.ToString(current.PolicyText), policyJSON):
+		return nil
+	}
+
+	_, err = client.SetRepositoryPolicy(ctx, &ecr.SetRepositoryPolicyInput{
+		RepositoryName: aws.String(repository),
+		PolicyText:     aws.String(string(policyJSON)),
+	})
+	s.audit.RecordCall(ctx, "ecr:SetRepositoryPolicy", orgID, err, "repository/"+repository)
+	if err != nil {
+		return fmt.Errorf("failed to set policy of repository %s: %w", repository, err)
+	}
+	return nil
+}
+
+// samePolicy compares two policy documents ignoring formatting
+func samePolicy(a string, b []byte) bool {
+	var x, y any
+	if json.Unmarshal([]byte(a), &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
@@ -0,0 +1,386 @@
+package accountservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/clusters"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/egress"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/events"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/periodic"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// replicaOfLabel marks standby copies with the primary cluster they mirror
+const replicaOfLabel = "replica-of"
+
+var (
+	// ErrReplicationDisabled is returned when no standby cluster is registered
+	ErrReplicationDisabled = errors.New("no standby cluster registered")
+	// ErrNotReplicated is returned when failing over a tenant that has never been synced
+	ErrNotReplicated = errors.New("account has no standby copy")
+)
+
+// ReplicationStatus is a tenant's replication state with its current lag
+type ReplicationStatus struct {
+	storage.ReplicationRecord
+	Lag time.Duration // Time since the last successful sync; zero after failover
+}
+
+// ============================================================================
+// Replication
+// ============================================================================
+
+// RunReplication mirrors every tenant to the standby cluster until ctx is cancelled
+func (s *Service) RunReplication(ctx context.Context, interval time.Duration) {
+	periodic.Every(ctx, interval, "replication", s.ReplicateAll)
+}
+
+// ReplicateAll syncs every account to the standby cluster. Per-tenant failures
+// are recorded in the tenant's replication state and do not stop the pass.
+func (s *Service) ReplicateAll(ctx context.Context) error {
+	standby, ok := s.clusters.Standby()
+	if !ok {
+		return ErrReplicationDisabled
+	}
+	records, err := s.accounts.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range records {
+		if _, err := s.replicateAccount(ctx, &records[i], standby); err != nil {
+			log.Printf("replication: %s: %v", records[i].OrganizationID, err)
+		}
+	}
+	return nil
+}
+
+// ReplicationStatuses reports replication state for one organization, or for
+// every account when orgID is empty
+func (s *Service) ReplicationStatuses(ctx context.Context, orgID string) ([]ReplicationStatus, error) {
+	standby, ok := s.clusters.Standby()
+	if !ok {
+		return nil, ErrReplicationDisabled
+	}
+
+	var records []storage.AccountRecord
+	if orgID != "" {
+		record, err := s.accounts.Get(ctx, orgID)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	} else {
+		all, err := s.accounts.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		records = all
+	}
+
+	now := time.Now().UTC()
+	statuses := make([]ReplicationStatus, 0, len(records))
+	for _, record := range records {
+		state, err := s.replication.Get(ctx, record.OrganizationID)
+		if errors.Is(err, storage.ErrNotFound) {
+			state = &storage.ReplicationRecord{OrganizationID: record.OrganizationID, StandbyCluster: standby.Name}
+		} else if err != nil {
+			return nil, err
+		}
+
+		status := ReplicationStatus{ReplicationRecord: *state}
+		if state.FailedOverAt.IsZero() {
+			since := state.LastSyncedAt
+			if since.IsZero() {
+				since = record.CreatedAt
+			}
+			status.Lag = now.Sub(since)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// replicateAccount runs one sync of a tenant and records the outcome
+func (s *Service) replicateAccount(ctx context.Context, record *storage.AccountRecord, standby *clusters.Cluster) (*storage.ReplicationRecord, error) {
+	state, err := s.replication.Get(ctx, record.OrganizationID)
+	if errors.Is(err, storage.ErrNotFound) {
+		state = &storage.ReplicationRecord{OrganizationID: record.OrganizationID}
+	} else if err != nil {
+		return nil, err
+	}
+	if record.Cluster == standby.Name {
+		// Already failed over; the standby is now the tenant's primary
+		return state, nil
+	}
+
+	now := time.Now().UTC()
+	state.StandbyCluster = standby.Name
+	state.LastAttemptAt = now
+	syncErr := s.syncStandby(ctx, record, standby, state)
+	if syncErr != nil {
+		state.LastError = syncErr.Error()
+	} else {
+		state.LastError = ""
+		state.LastSyncedAt = now
+	}
+
+	if err := s.replication.Save(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to save replication state: %w", err)
+	}
+	return state, syncErr
+}
+
+// syncStandby mirrors the tenant's namespaces, quotas, RBAC, network policies
+// and service account to the standby cluster, backed by a standby IAM role.
+// Workloads and data are not copied.
+func (s *Service) syncStandby(ctx context.Context, record *storage.AccountRecord, standby *clusters.Cluster, state *storage.ReplicationRecord) error {
+	orgID := record.OrganizationID
+	primary, err := s.clusterFor(record)
+	if err != nil {
+		return err
+	}
+
+	roleARN, err := s.ensureStandbyRole(ctx, record, standby)
+	if err != nil {
+		return err
+	}
+	state.StandbyRoleARN = roleARN
+
+	if err := s.ensurePriorityClasses(ctx, standby.Client); err != nil {
+		return err
+	}
+
+	namespaces, err := tenantNamespaces(ctx, primary.Client, record)
+	if err != nil {
+		return err
+	}
+	marker := map[string]string{replicaOfLabel: primary.Name}
+	for _, ns := range namespaces {
+		if err := s.replicateNamespace(ctx, primary.Client, standby.Client, orgID, ns, marker); err != nil {
+			return err
+		}
+		if err := s.copyQuotas(ctx, primary.Client, standby.Client, orgID, ns, marker); err != nil {
+			return err
+		}
+		if err := s.copyPolicies(ctx, primary.Client, standby.Client, orgID, ns, ns, marker); err != nil {
+			return err
+		}
+
+		// Hostnames are resolved on the standby side, as in RefreshEgress
+		if list := accountAllowlist(record); !list.Empty() {
+			_, err := s.egress.Apply(ctx, standby.Client, standby.Dynamic, orgID, ns, list)
+			s.audit.RecordCall(ctx, "kubernetes:ApplyEgressPolicy", orgID, err, ns+"/egress/"+egress.PolicyName)
+			if err != nil {
+				return fmt.Errorf("failed to apply egress policy to %s: %w", ns, err)
+			}
+		}
+	}
+
+	return s.ensureServiceAccount(ctx, standby.Client, orgID, record.Namespace, roleARN)
+}
+
+// replicateNamespace creates or relabels a namespace on the standby
+func (s *Service) replicateNamespace(ctx context.Context, src, dst kubernetes.Interface, orgID, namespace string, marker map[string]string) error {
+	ns, err := src.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	labels := maps.Clone(ns.Labels)
+	maps.Copy(labels, marker)
+
+	existing, err := dst.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		replica := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace, Labels: labels, Annotations: ns.Annotations},
+		}
+		_, err = dst.CoreV1().Namespaces().Create(ctx, replica, metav1.CreateOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:CreateNamespace", orgID, err, "namespace/"+namespace)
+	case err == nil && !maps.Equal(existing.Labels, labels):
+		existing.Labels = labels
+		_, err = dst.CoreV1().Namespaces().Update(ctx, existing, metav1.UpdateOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:UpdateNamespace", orgID, err, "namespace/"+namespace)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to replicate namespace %s: %w", namespace, err)
+	}
+	return nil
+}
+
+// copyQuotas creates or updates every ResourceQuota of a namespace on the standby
+func (s *Service) copyQuotas(ctx context.Context, src, dst kubernetes.Interface, orgID, namespace string, marker map[string]string) error {
+	quotas, err := src.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list resource quotas: %w", err)
+	}
+	for _, q := range quotas.Items {
+		q.ObjectMeta = propagatedMeta(q.ObjectMeta, namespace, marker)
+		q.Status = corev1.ResourceQuotaStatus{}
+
+		existing, err := dst.CoreV1().ResourceQuotas(namespace).Get(ctx, q.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			_, err = dst.CoreV1().ResourceQuotas(namespace).Create(ctx, &q, metav1.CreateOptions{})
+		case err == nil && !equality.Semantic.DeepEqual(existing.Spec, q.Spec):
+			existing.Labels = q.Labels
+			existing.Spec = q.Spec
+			_, err = dst.CoreV1().ResourceQuotas(namespace).Update(ctx, existing, metav1.UpdateOptions{})
+		case err == nil:
+			continue
+		}
+		s.audit.RecordCall(ctx, "kubernetes:ApplyResourceQuota", orgID, err, namespace+"/resourcequota/"+q.Name)
+		if err != nil {
+			return fmt.Errorf("failed to copy resource quota %s: %w", q.Name, err)
+		}
+	}
+	return nil
+}
+
+// ensureStandbyRole returns the tenant's standby IAM role, creating it with a
+// trust policy for the standby cluster on first use. IAM is global, so this is
+// a second role rather than a copy in another region.
+func (s *Service) ensureStandbyRole(ctx context.Context, record *storage.AccountRecord, standby *clusters.Cluster) (string, error) {
+	orgID := record.OrganizationID
+	roleName := standbyRoleName(orgID)
+
+	roleARN, created, err := s.ensureIAMRole(ctx, orgID, roleName, record.Namespace, standby.ClusterARN)
+	if err != nil {
+		return "", err
+	}
+	if created && record.S3Bucket != "" {
+		if err := s.attachS3Policy(ctx, roleName, record.S3Bucket, orgID); err != nil {
+			return "", err
+		}
+	}
+	return roleARN, nil
+}
+
+func standbyRoleName(orgID string) string {
+	return fmt.Sprintf("tenant-%s-standby-role", orgID)
+}
+
+// ============================================================================
+// Failover
+// ============================================================================
+
+// FailoverAccount promotes a tenant's standby copy: the account is moved to the
+// standby cluster and its IAM role. A final sync is attempted first but the
+// primary may already be unreachable, so the last successful sync is what the
+// tenant gets.
+func (s *Service) FailoverAccount(ctx context.Context, orgID string) (*storage.AccountRecord, error) {
+	standby, ok := s.clusters.Standby()
+	if !ok {
+		return nil, ErrReplicationDisabled
+	}
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if record.Cluster == standby.Name {
+		return record, nil
+	}
+	if record.GitOpsRevision != "" {
+		return nil, fmt.Errorf("failover: %w", ErrGitOpsUnsupported)
+	}
+	previousCluster := record.Cluster
+
+	if _, err := s.replicateAccount(ctx, record, standby); err != nil {
+		log.Printf("failover %s: final sync failed, using last replica: %v", orgID, err)
+	}
+	state, err := s.replication.Get(ctx, orgID)
+	if errors.Is(err, storage.ErrNotFound) || (err == nil && state.LastSyncedAt.IsZero()) {
+		return nil, ErrNotReplicated
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop the replica marker so the copy is managed like any primary tenant
+	replicas, err := standby.Client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: "tenant-id=" + orgID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list standby namespaces: %w", err)
+	}
+	for i := range replicas.Items {
+		ns := &replicas.Items[i]
+		if _, ok := ns.Labels[replicaOfLabel]; !ok {
+			continue
+		}
+		delete(ns.Labels, replicaOfLabel)
+		_, err := standby.Client.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+		s.audit.RecordCall(ctx, "kubernetes:UpdateNamespace", orgID, err, "namespace/"+ns.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to promote namespace %s: %w", ns.Name, err)
+		}
+	}
+
+	err = s.modifyAccount(ctx, record, func(rec *storage.AccountRecord) {
+		rec.Cluster = standby.Name
+		rec.IAMRoleARN = state.StandbyRoleARN
+		rec.IAMRoleLinked = false // The standby role is always ours
+	}, func(rec *storage.AccountRecord) (storage.OutboxMessage, error) {
+		return events.NewAccountEvent(events.TypeAccountFailedOver, events.AccountData{
+			OrganizationID: orgID,
+			Namespace:      rec.Namespace,
+			PlanTier:       rec.PlanTier,
+			Status:         rec.Status,
+			Reason:         fmt.Sprintf("failed over from cluster %q to %q", previousCluster, standby.Name),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state.FailedOverAt = time.Now().UTC()
+	if err := s.replication.Save(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to save replication state: %w", err)
+	}
+	return record, nil
+}
+
+// deleteReplica removes a tenant's standby copy, standby IAM role and replication
+// state. After a failover the namespaces were already deleted as the primary.
+func (s *Service) deleteReplica(ctx context.Context, orgID, namespace string) error {
+	state, err := s.replication.Get(ctx, orgID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if standby, err := s.clusters.Get(state.StandbyCluster); err == nil && state.FailedOverAt.IsZero() {
+		if err := s.deleteSubNamespaces(ctx, standby.Client, orgID, namespace); err != nil {
+			return err
+		}
+		if err := s.deleteNamespace(ctx, standby.Client, orgID, namespace); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete standby namespace: %w", err)
+		}
+	}
+
+	roleName := standbyRoleName(orgID)
+	s.deleteRolePolicy(ctx, orgID, roleName)
+	if err := s.deleteRole(ctx, orgID, roleName); err != nil {
+		var missing *types.NoSuchEntityException
+		if !errors.As(err, &missing) {
+			return fmt.Errorf("failed to delete standby IAM role: %w", err)
+		}
+	}
+
+	return s.replication.Delete(ctx, orgID)
+}
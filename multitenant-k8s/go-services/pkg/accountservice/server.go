@@ -3,46 +3,147 @@ package accountservice
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"google.golang.org/protobuf/proto"
 
 	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/audit"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/auth"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/billing"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/clusters"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/egress"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/events"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/gitops"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/identity"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/ingress"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/jobtopics"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/manifests"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/templates"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 // Service holds dependencies for the account provisioning service
 type Service struct {
-	k8sClient  kubernetes.Interface
-	iamClient  *iam.Client
-	awsConfig  aws.Config
-	clusterARN string // EKS cluster ARN for IRSA
+	clusters              *clusters.Registry // Workload clusters tenants are placed on
+	iamClient             *iam.Client
+	awsConfig             aws.Config
+	audit                 *audit.Logger
+	accounts              *storage.AccountStore
+	apiKeys               *storage.APIKeyStore
+	replication           *storage.ReplicationStore
+	members               *storage.MemberStore
+	invitations           *storage.InvitationStore
+	invitationSigner      *auth.InvitationSigner // nil disables invitations
+	invitationTTL         time.Duration
+	groups                identity.GroupSync    // nil when no IdP is configured
+	billing               billing.Provider      // nil when billing is disabled
+	billingAccounts       *storage.BillingStore // Customer and subscription per account
+	expiry                ExpiryPolicy          // Trial length, warning period and plan expiry action
+	registryPullRole      string                // Role minting tenant pull secrets; empty disables registries
+	registryAccount       string                // AWS account holding tenant repositories
+	registryPartition     string                // AWS partition of registryAccount
+	jobTopics             *jobtopics.Admin      // nil disables dedicated job topics
+	ingress               *ingress.Manager      // nil disables tenant subdomains
+	templates             *templates.Catalog    // nil disables environment templates
+	gitops                *gitops.Exporter      // nil applies tenant objects directly
+	enterprisePodSecurity string                // PSA enforce level for ENTERPRISE tenants on dedicated nodes
+	egress                *egress.Applier
 }
 
 // Config holds configuration for the service
 type Config struct {
-	KubeConfigPath string // Path to kubeconfig file (empty for in-cluster)
-	AWSRegion      string // AWS region
-	ClusterARN     string // EKS cluster ARN for IAM role trust policy
+	KubeConfigPath        string                    // Path to kubeconfig file (empty for in-cluster)
+	ClusterRegistryPath   string                    // JSON cluster registry (empty for a single cluster from KubeConfigPath)
+	AWSRegion             string                    // AWS region
+	ClusterARN            string                    // EKS cluster ARN for IAM role trust policy (single-cluster mode)
+	AuditLog              *audit.Logger             // Records every Kubernetes/IAM mutation (optional)
+	Accounts              *storage.AccountStore     // Account registry and event outbox
+	APIKeys               *storage.APIKeyStore      // Per-organization API keys
+	Replication           *storage.ReplicationStore // Standby sync state (used when the registry has a standby cluster)
+	Members               *storage.MemberStore      // Organization members
+	Invitations           *storage.InvitationStore  // Member invitations
+	InvitationSigner      *auth.InvitationSigner    // Signs invitation tokens (optional; invitations are disabled without it)
+	InvitationTTL         time.Duration             // How long invitations stay valid (default 7 days)
+	Groups                identity.GroupSync        // Syncs members to IdP groups (optional)
+	Billing               billing.Provider          // Creates subscriptions for new accounts (optional)
+	BillingAccounts       *storage.BillingStore     // Customer and subscription per account (required with Billing)
+	Expiry                ExpiryPolicy              // Trial length, warning period and plan expiry action
+	RegistryPullRoleARN   string                    // Role assumed to mint per-tenant registry pull secrets (optional; registries are disabled without it)
+	JobTopics             *jobtopics.Admin          // Creates dedicated job topics and ACLs (optional)
+	Ingress               *ingress.Manager          // Serves tenant subdomains and custom domains (optional)
+	Templates             *templates.Catalog        // Environment templates new tenants can be seeded from (optional)
+	GitOps                *gitops.Exporter          // Commits new tenants' objects for a GitOps controller instead of creating them (optional)
+	EnterprisePodSecurity string                    // PSA enforce level for ENTERPRISE tenants on dedicated nodes (default "baseline")
 }
 
 // New creates a new account service with AWS and K8s clients
 func New(cfg Config) (*Service, error) {
-	// Initialize Kubernetes client
-	k8sClient, err := newK8sClient(cfg.KubeConfigPath)
+	if cfg.Accounts == nil {
+		return nil, fmt.Errorf("account store must not be nil")
+	}
+	if cfg.APIKeys == nil {
+		return nil, fmt.Errorf("api key store must not be nil")
+	}
+	if cfg.Replication == nil {
+		return nil, fmt.Errorf("replication store must not be nil")
+	}
+	if cfg.Members == nil {
+		return nil, fmt.Errorf("member store must not be nil")
+	}
+	if cfg.Invitations == nil {
+		return nil, fmt.Errorf("invitation store must not be nil")
+	}
+	if cfg.Billing != nil && cfg.BillingAccounts == nil {
+		return nil, fmt.Errorf("billing store must not be nil when billing is enabled")
+	}
+	if cfg.Expiry.PlanAction == "" {
+		cfg.Expiry.PlanAction = ExpiryActionDowngrade
+	}
+	if err := ValidateExpiryAction(cfg.Expiry.PlanAction); err != nil {
+		return nil, err
+	}
+	if cfg.InvitationTTL <= 0 {
+		cfg.InvitationTTL = DefaultInvitationTTL
+	}
+	if cfg.EnterprisePodSecurity == "" {
+		cfg.EnterprisePodSecurity = PodSecurityBaseline
+	}
+	if err := ValidatePodSecurityLevel(cfg.EnterprisePodSecurity); err != nil {
+		return nil, err
+	}
+
+	var pullRole arn.ARN
+	if cfg.RegistryPullRoleARN != "" {
+		parsed, err := arn.Parse(cfg.RegistryPullRoleARN)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registry pull role: %w", err)
+		}
+		pullRole = parsed
+	}
+
+	// Connect to every workload cluster
+	registry, err := clusters.LoadRegistry(context.Background(), cfg.ClusterRegistryPath, cfg.KubeConfigPath, cfg.ClusterARN)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create k8s client: %w", err)
+		return nil, fmt.Errorf("failed to load cluster registry: %w", err)
 	}
 
 	// Initialize AWS config
@@ -57,31 +158,67 @@ func New(cfg Config) (*Service, error) {
 	iamClient := iam.NewFromConfig(awsCfg)
 
 	return &Service{
-		k8sClient:  k8sClient,
-		iamClient:  iamClient,
-		awsConfig:  awsCfg,
-		clusterARN: cfg.ClusterARN,
+		clusters:              registry,
+		iamClient:             iamClient,
+		awsConfig:             awsCfg,
+		audit:                 cfg.AuditLog,
+		accounts:              cfg.Accounts,
+		apiKeys:               cfg.APIKeys,
+		replication:           cfg.Replication,
+		members:               cfg.Members,
+		invitations:           cfg.Invitations,
+		invitationSigner:      cfg.InvitationSigner,
+		invitationTTL:         cfg.InvitationTTL,
+		groups:                cfg.Groups,
+		billing:               cfg.Billing,
+		billingAccounts:       cfg.BillingAccounts,
+		expiry:                cfg.Expiry,
+		registryPullRole:      cfg.RegistryPullRoleARN,
+		registryAccount:       pullRole.AccountID,
+		registryPartition:     pullRole.Partition,
+		jobTopics:             cfg.JobTopics,
+		ingress:               cfg.Ingress,
+		templates:             cfg.Templates,
+		gitops:                cfg.GitOps,
+		enterprisePodSecurity: cfg.EnterprisePodSecurity,
+		egress:                egress.NewApplier(),
 	}, nil
 }
 
-// newK8sClient creates a Kubernetes client
-func newK8sClient(kubeconfigPath string) (kubernetes.Interface, error) {
-	var config *rest.Config
-	var err error
+// Clusters returns the registry of clusters the service provisions tenants on
+func (s *Service) Clusters() *clusters.Registry {
+	return s.clusters
+}
 
-	if kubeconfigPath == "" {
-		// Use in-cluster config (when running in a pod)
-		config, err = rest.InClusterConfig()
-	} else {
-		// Use kubeconfig file (for local development)
-		config, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+// AWSConfig returns the AWS configuration the service calls IAM with
+func (s *Service) AWSConfig() aws.Config {
+	return s.awsConfig
+}
+
+// placeTenant chooses the cluster for a new tenant from tier, region and free capacity.
+// With a single primary cluster there is nothing to choose, and it is used
+// without a capacity check as long as it accepts the tier.
+func (s *Service) placeTenant(ctx context.Context, tier acctv1.PlanTier, region string) (*clusters.Cluster, error) {
+	var primaries []*clusters.Cluster
+	for _, c := range s.clusters.All() {
+		if !c.Standby {
+			primaries = append(primaries, c)
+		}
+	}
+	if len(primaries) == 1 && region == "" && primaries[0].AcceptsTier(tier.String()) {
+		return primaries[0], nil
 	}
 
+	quotaSpec, err := QuotaForTier(tier)
 	if err != nil {
 		return nil, err
 	}
-
-	return kubernetes.NewForConfig(config)
+	return s.clusters.Place(ctx, clusters.Request{
+		Tier:   tier.String(),
+		Region: region,
+		CPU:    resource.MustParse(quotaSpec.RequestsCpu),
+		Memory: resource.MustParse(quotaSpec.RequestsMemory),
+	})
 }
 
 // ============================================================================
@@ -89,17 +226,31 @@ func newK8sClient(kubeconfigPath string) (kubernetes.Interface, error) {
 // ============================================================================
 
 // createK8sNamespace creates a namespace for the tenant
-func (s *Service) createK8sNamespace(ctx context.Context, orgID string, tier acctv1.PlanTier) (string, error) {
-	namespaceName := fmt.Sprintf("tenant-%s", orgID)
+func (s *Service) createK8sNamespace(ctx context.Context, kc kubernetes.Interface, orgID string, orgType acctv1.OrganizationType, tier acctv1.PlanTier) (string, error) {
+	namespace := s.namespaceObject(orgID, orgType, tier, time.Now())
+	namespaceName := namespace.Name
+
+	_, err := kc.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	s.audit.RecordCall(ctx, "kubernetes:CreateNamespace", orgID, err, "namespace/"+namespaceName)
+	if err != nil {
+		return "", fmt.Errorf("failed to create namespace: %w", err)
+	}
+
+	return namespaceName, nil
+}
 
+// namespaceObject builds the tenant namespace with its tenant and PSA labels
+func (s *Service) namespaceObject(orgID string, orgType acctv1.OrganizationType, tier acctv1.PlanTier, createdAt time.Time) *corev1.Namespace {
 	namespace := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: namespaceName,
+			Name: fmt.Sprintf("tenant-%s", orgID),
 			Labels: map[string]string{
-				"tenant-id":  orgID,
-				"plan-tier":  tier.String(),
-				"managed-by": "account-provisioning-service",
-				"created-at": time.Now().Format(time.RFC3339),
+				"tenant":            fmt.Sprintf("tenant-%s", orgID), // Selected by the tenant-isolation policy
+				"tenant-id":         orgID,
+				"plan-tier":         tier.String(),
+				"organization-type": orgType.String(),
+				"managed-by":        "account-provisioning-service",
+				"created-at":        createdAt.Format(time.RFC3339),
 			},
 			Annotations: map[string]string{
 				"organization-id": orgID,
@@ -107,107 +258,153 @@ func (s *Service) createK8sNamespace(ctx context.Context, orgID string, tier acc
 			},
 		},
 	}
-
-	_, err := s.k8sClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
-	if err != nil {
-		return "", fmt.Errorf("failed to create namespace: %w", err)
-	}
-
-	return namespaceName, nil
+	applyPodSecurityLabels(namespace.Labels, s.podSecurityFor(orgType, tier))
+	return namespace
 }
 
-// applyResourceQuota applies resource quotas to the namespace based on plan tier
-func (s *Service) applyResourceQuota(ctx context.Context, namespace string, tier acctv1.PlanTier) (*acctv1.ResourceQuota, error) {
-	// Define quotas per plan tier
-	quotaSpecs := map[acctv1.PlanTier]*acctv1.ResourceQuota{
-		acctv1.PlanTier_PLAN_TIER_FREE: {
-			RequestsCpu:     "2",
-			RequestsMemory:  "4Gi",
-			LimitsCpu:       "4",
-			LimitsMemory:    "8Gi",
-			MaxPvcs:         5,
-			MaxServices:     10,
-			MaxDeployments:  5,
-			MaxStatefulsets: 2,
-		},
-		acctv1.PlanTier_PLAN_TIER_STARTER: {
-			RequestsCpu:     "5",
-			RequestsMemory:  "10Gi",
-			LimitsCpu:       "10",
-			LimitsMemory:    "20Gi",
-			MaxPvcs:         10,
-			MaxServices:     20,
-			MaxDeployments:  10,
-			MaxStatefulsets: 5,
-		},
-		acctv1.PlanTier_PLAN_TIER_PRO: {
-			RequestsCpu:     "20",
-			RequestsMemory:  "40Gi",
-			LimitsCpu:       "40",
-			LimitsMemory:    "80Gi",
-			MaxPvcs:         30,
-			MaxServices:     50,
-			MaxDeployments:  25,
-			MaxStatefulsets: 10,
-		},
-		acctv1.PlanTier_PLAN_TIER_ENTERPRISE: {
-			RequestsCpu:     "100",
-			RequestsMemory:  "200Gi",
-			LimitsCpu:       "200",
-			LimitsMemory:    "400Gi",
-			MaxPvcs:         100,
-			MaxServices:     200,
-			MaxDeployments:  100,
-			MaxStatefulsets: 50,
-		},
-	}
+// quotaSpecs defines quotas per plan tier
+var quotaSpecs = map[acctv1.PlanTier]*acctv1.ResourceQuota{
+	acctv1.PlanTier_PLAN_TIER_FREE: {
+		RequestsCpu:     "2",
+		RequestsMemory:  "4Gi",
+		LimitsCpu:       "4",
+		LimitsMemory:    "8Gi",
+		MaxPvcs:         5,
+		MaxServices:     10,
+		MaxDeployments:  5,
+		MaxStatefulsets: 2,
+	},
+	acctv1.PlanTier_PLAN_TIER_STARTER: {
+		RequestsCpu:     "5",
+		RequestsMemory:  "10Gi",
+		LimitsCpu:       "10",
+		LimitsMemory:    "20Gi",
+		MaxPvcs:         10,
+		MaxServices:     20,
+		MaxDeployments:  10,
+		MaxStatefulsets: 5,
+	},
+	acctv1.PlanTier_PLAN_TIER_PRO: {
+		RequestsCpu:     "20",
+		RequestsMemory:  "40Gi",
+		LimitsCpu:       "40",
+		LimitsMemory:    "80Gi",
+		MaxPvcs:         30,
+		MaxServices:     50,
+		MaxDeployments:  25,
+		MaxStatefulsets: 10,
+	},
+	acctv1.PlanTier_PLAN_TIER_ENTERPRISE: {
+		RequestsCpu:     "100",
+		RequestsMemory:  "200Gi",
+		LimitsCpu:       "200",
+		LimitsMemory:    "400Gi",
+		MaxPvcs:         100,
+		MaxServices:     200,
+		MaxDeployments:  100,
+		MaxStatefulsets: 50,
+	},
+}
 
+// QuotaForTier returns the resource quota applied to tenants on a plan tier
+func QuotaForTier(tier acctv1.PlanTier) (*acctv1.ResourceQuota, error) {
 	quotaSpec, ok := quotaSpecs[tier]
 	if !ok {
 		return nil, fmt.Errorf("unknown plan tier: %v", tier)
 	}
+	return proto.Clone(quotaSpec).(*acctv1.ResourceQuota), nil
+}
 
-	// Create K8s ResourceQuota object
-	resourceQuota := &corev1.ResourceQuota{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "tenant-quota",
-			Namespace: namespace,
-			Labels: map[string]string{
-				"plan-tier": tier.String(),
-			},
-		},
-		Spec: corev1.ResourceQuotaSpec{
-			Hard: corev1.ResourceList{
-				"requests.cpu":            resource.MustParse(quotaSpec.RequestsCpu),
-				"requests.memory":         resource.MustParse(quotaSpec.RequestsMemory),
-				"limits.cpu":              resource.MustParse(quotaSpec.LimitsCpu),
-				"limits.memory":           resource.MustParse(quotaSpec.LimitsMemory),
-				"persistentvolumeclaims":  resource.MustParse(fmt.Sprintf("%d", quotaSpec.MaxPvcs)),
-				"services":                resource.MustParse(fmt.Sprintf("%d", quotaSpec.MaxServices)),
-				"count/deployments.apps":  resource.MustParse(fmt.Sprintf("%d", quotaSpec.MaxDeployments)),
-				"count/statefulsets.apps": resource.MustParse(fmt.Sprintf("%d", quotaSpec.MaxStatefulsets)),
-			},
-		},
+// applyResourceQuota applies resource quotas to the namespace based on plan tier
+func (s *Service) applyResourceQuota(ctx context.Context, kc kubernetes.Interface, namespace string, tier acctv1.PlanTier) (*acctv1.ResourceQuota, error) {
+	quotaSpec, err := QuotaForTier(tier)
+	if err != nil {
+		return nil, err
 	}
 
-	_, err := s.k8sClient.CoreV1().ResourceQuotas(namespace).Create(ctx, resourceQuota, metav1.CreateOptions{})
+	resourceQuota, err := resourceQuotaObject(namespace, tier, quotaSpec)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create resource quota: %w", err)
+		return nil, err
+	}
+	if err := s.applyQuotaObject(ctx, kc, orgIDFromNamespace(namespace), resourceQuota); err != nil {
+		return nil, fmt.Errorf("failed to apply resource quota: %w", err)
 	}
 
 	return quotaSpec, nil
 }
 
-// applyNetworkPolicy creates a network policy for tenant isolation
-func (s *Service) applyNetworkPolicy(ctx context.Context, namespace string) error {
-	// TODO: Implement network policy for tenant isolation
-	// This would restrict ingress/egress to only allowed namespaces
+// resourceQuotaObject renders the tenant-quota manifest for a tier
+func resourceQuotaObject(namespace string, tier acctv1.PlanTier, quotaSpec *acctv1.ResourceQuota) (*corev1.ResourceQuota, error) {
+	quota, err := manifests.ResourceQuota(manifests.Vars{
+		TenantName: namespace,
+		PlanTier:   tier.String(),
+		Quota:      quotaSpec,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render resource quota: %w", err)
+	}
+	return quota, nil
+}
+
+// applyQuotaObject server-side applies a rendered tenant-quota
+func (s *Service) applyQuotaObject(ctx context.Context, kc kubernetes.Interface, orgID string, quota *corev1.ResourceQuota) error {
+	data, opts, err := applyPatch(quota)
+	if err != nil {
+		return err
+	}
+	_, err = kc.CoreV1().ResourceQuotas(quota.Namespace).Patch(ctx, quota.Name, k8stypes.ApplyPatchType, data, opts)
+	s.audit.RecordCall(ctx, "kubernetes:ApplyResourceQuota", orgID, err, quota.Namespace+"/resourcequota/"+quota.Name)
+	return err
+}
+
+// applyNetworkPolicy applies the tenant-isolation policy, which admits traffic
+// only from the tenant's own namespaces and shared services
+func (s *Service) applyNetworkPolicy(ctx context.Context, kc kubernetes.Interface, orgID, namespace string) error {
+	policy, err := manifests.NetworkPolicy(manifests.Vars{TenantName: namespace, OrgID: orgID})
+	if err != nil {
+		return fmt.Errorf("failed to render network policy: %w", err)
+	}
+	data, opts, err := applyPatch(policy)
+	if err != nil {
+		return err
+	}
+	_, err = kc.NetworkingV1().NetworkPolicies(namespace).Patch(ctx, policy.Name, k8stypes.ApplyPatchType, data, opts)
+	s.audit.RecordCall(ctx, "kubernetes:ApplyNetworkPolicy", orgID, err, namespace+"/networkpolicy/"+policy.Name)
+	if err != nil {
+		return fmt.Errorf("failed to apply network policy: %w", err)
+	}
 	return nil
 }
 
+// fieldManager owns the fields of the objects the service server-side applies
+const fieldManager = "account-provisioning-service"
+
+// applyPatch encodes a rendered object as a server-side apply patch
+func applyPatch(obj runtime.Object) ([]byte, metav1.PatchOptions, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, metav1.PatchOptions{}, fmt.Errorf("failed to encode %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, err)
+	}
+	force := true
+	return data, metav1.PatchOptions{FieldManager: fieldManager, Force: &force}, nil
+}
+
 // createServiceAccount creates a Kubernetes service account with IRSA annotations
-func (s *Service) createServiceAccount(ctx context.Context, namespace, orgID, iamRoleARN string) error {
-	serviceAccount := &corev1.ServiceAccount{
+func (s *Service) createServiceAccount(ctx context.Context, kc kubernetes.Interface, namespace, orgID, iamRoleARN string) error {
+	serviceAccount := serviceAccountObject(namespace, orgID, iamRoleARN)
+
+	_, err := kc.CoreV1().ServiceAccounts(namespace).Create(ctx, serviceAccount, metav1.CreateOptions{})
+	s.audit.RecordCall(ctx, "kubernetes:CreateServiceAccount", orgID, err, namespace+"/serviceaccount/tenant-sa")
+	if err != nil {
+		return fmt.Errorf("failed to create service account: %w", err)
+	}
+
+	return nil
+}
+
+// serviceAccountObject builds tenant-sa, bound to the tenant's IAM role through IRSA
+func serviceAccountObject(namespace, orgID, iamRoleARN string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "tenant-sa",
 			Namespace: namespace,
@@ -220,57 +417,85 @@ func (s *Service) createServiceAccount(ctx context.Context, namespace, orgID, ia
 			},
 		},
 	}
+}
 
-	_, err := s.k8sClient.CoreV1().ServiceAccounts(namespace).Create(ctx, serviceAccount, metav1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create service account: %w", err)
+// ensureServiceAccount creates tenant-sa or points an existing one at roleARN
+func (s *Service) ensureServiceAccount(ctx context.Context, kc kubernetes.Interface, orgID, namespace, roleARN string) error {
+	existing, err := kc.CoreV1().ServiceAccounts(namespace).Get(ctx, "tenant-sa", metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return s.createServiceAccount(ctx, kc, namespace, orgID, roleARN)
+	case err != nil:
+		return fmt.Errorf("failed to get service account: %w", err)
+	case existing.Annotations["eks.amazonaws.com/role-arn"] == roleARN:
+		return nil
 	}
 
+	if existing.Annotations == nil {
+		existing.Annotations = map[string]string{}
+	}
+	existing.Annotations["eks.amazonaws.com/role-arn"] = roleARN
+	_, err = kc.CoreV1().ServiceAccounts(namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	s.audit.RecordCall(ctx, "kubernetes:UpdateServiceAccount", orgID, err, namespace+"/serviceaccount/tenant-sa")
+	if err != nil {
+		return fmt.Errorf("failed to update service account: %w", err)
+	}
 	return nil
 }
 
-// createRBAC creates RBAC roles and bindings for the tenant
-func (s *Service) createRBAC(ctx context.Context, namespace, orgID string) error {
-	// Admin role for tenant admins
-	adminRole := &rbacv1.Role{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "tenant-admin",
-			Namespace: namespace,
-		},
-		Rules: []rbacv1.PolicyRule{
-			{
-				APIGroups: []string{"*"},
-				Resources: []string{"*"},
-				Verbs:     []string{"*"},
-			},
-		},
+// tenantRBAC renders the roles and role bindings every tenant namespace gets
+func tenantRBAC(namespace string) ([]*rbacv1.Role, []*rbacv1.RoleBinding, error) {
+	roles, bindings, err := manifests.RBAC(manifests.Vars{TenantName: namespace})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render RBAC: %w", err)
 	}
+	return roles, bindings, nil
+}
 
-	_, err := s.k8sClient.RbacV1().Roles(namespace).Create(ctx, adminRole, metav1.CreateOptions{})
+// applyRBAC creates the tenant roles and role bindings or resets them to the manifests
+func (s *Service) applyRBAC(ctx context.Context, kc kubernetes.Interface, namespace, orgID string) error {
+	roles, bindings, err := tenantRBAC(namespace)
 	if err != nil {
-		return fmt.Errorf("failed to create admin role: %w", err)
+		return err
 	}
+	for _, role := range roles {
+		existing, err := kc.RbacV1().Roles(namespace).Get(ctx, role.Name, metav1.GetOptions{})
+		if err == nil && equality.Semantic.DeepEqual(existing.Rules, role.Rules) {
+			continue
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get role %s: %w", role.Name, err)
+		}
 
-	// User role for regular users (read-only on most resources)
-	userRole := &rbacv1.Role{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "tenant-user",
-			Namespace: namespace,
-		},
-		Rules: []rbacv1.PolicyRule{
-			{
-				APIGroups: []string{"", "apps"},
-				Resources: []string{"pods", "services", "deployments"},
-				Verbs:     []string{"get", "list", "watch"},
-			},
-		},
+		data, opts, err := applyPatch(role)
+		if err != nil {
+			return err
+		}
+		_, err = kc.RbacV1().Roles(namespace).Patch(ctx, role.Name, k8stypes.ApplyPatchType, data, opts)
+		s.audit.RecordCall(ctx, "kubernetes:ApplyRole", orgID, err, namespace+"/role/"+role.Name)
+		if err != nil {
+			return fmt.Errorf("failed to apply role %s: %w", role.Name, err)
+		}
 	}
+	for _, binding := range bindings {
+		existing, err := kc.RbacV1().RoleBindings(namespace).Get(ctx, binding.Name, metav1.GetOptions{})
+		if err == nil && existing.RoleRef == binding.RoleRef && equality.Semantic.DeepEqual(existing.Subjects, binding.Subjects) {
+			continue
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get role binding %s: %w", binding.Name, err)
+		}
 
-	_, err = s.k8sClient.RbacV1().Roles(namespace).Create(ctx, userRole, metav1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create user role: %w", err)
+		data, opts, err := applyPatch(binding)
+		if err != nil {
+			return err
+		}
+		_, err = kc.RbacV1().RoleBindings(namespace).Patch(ctx, binding.Name, k8stypes.ApplyPatchType, data, opts)
+		s.audit.RecordCall(ctx, "kubernetes:ApplyRoleBinding", orgID, err, namespace+"/rolebinding/"+binding.Name)
+		if err != nil {
+			return fmt.Errorf("failed to apply role binding %s: %w", binding.Name, err)
+		}
 	}
-
 	return nil
 }
 
@@ -278,10 +503,9 @@ func (s *Service) createRBAC(ctx context.Context, namespace, orgID string) error
 // AWS IAM Operations
 // ============================================================================
 
-// createIAMRole creates an IAM role for the tenant with IRSA trust policy
-func (s *Service) createIAMRole(ctx context.Context, orgID string) (string, error) {
-	roleName := fmt.Sprintf("tenant-%s-role", orgID)
-
+// createIAMRole creates an IAM role for the tenant with IRSA trust policy for
+// tenant-sa in namespace
+func (s *Service) createIAMRole(ctx context.Context, orgID, roleName, namespace, clusterARN string) (string, error) {
 	// Create trust policy for IRSA (IAM Roles for Service Accounts)
 	trustPolicy := map[string]interface{}{
 		"Version": "2012-10-17",
@@ -289,14 +513,14 @@ func (s *Service) createIAMRole(ctx context.Context, orgID string) (string, erro
 			{
 				"Effect": "Allow",
 				"Principal": map[string]interface{}{
-					"Federated": s.clusterARN,
+					"Federated": clusterARN,
 				},
 				"Action": "sts:AssumeRoleWithWebIdentity",
 				"Condition": map[string]interface{}{
 					"StringEquals": map[string]string{
 						// This would need to be customized per cluster's OIDC provider
 						// Format: oidc.eks.region.amazonaws.com/id/CLUSTER_ID:sub
-						fmt.Sprintf("%s:sub", s.clusterARN): fmt.Sprintf("system:serviceaccount:tenant-%s:tenant-sa", orgID),
+						fmt.Sprintf("%s:sub", clusterARN): fmt.Sprintf("system:serviceaccount:%s:tenant-sa", namespace),
 					},
 				},
 			},
@@ -326,12 +550,33 @@ func (s *Service) createIAMRole(ctx context.Context, orgID string) (string, erro
 	})
 
 	if err != nil {
+		s.audit.RecordCall(ctx, "iam:CreateRole", orgID, err, roleName)
 		return "", fmt.Errorf("failed to create IAM role: %w", err)
 	}
+	s.audit.RecordCall(ctx, "iam:CreateRole", orgID, nil, *createRoleOutput.Role.Arn)
 
 	return *createRoleOutput.Role.Arn, nil
 }
 
+// ensureIAMRole returns the ARN of roleName, creating it with an IRSA trust
+// policy for tenant-sa in namespace if it does not exist yet
+func (s *Service) ensureIAMRole(ctx context.Context, orgID, roleName, namespace, clusterARN string) (arn string, created bool, err error) {
+	out, err := s.iamClient.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(roleName)})
+	if err == nil {
+		return *out.Role.Arn, false, nil
+	}
+	var missing *types.NoSuchEntityException
+	if !errors.As(err, &missing) {
+		return "", false, fmt.Errorf("failed to get IAM role %s: %w", roleName, err)
+	}
+
+	arn, err = s.createIAMRole(ctx, orgID, roleName, namespace, clusterARN)
+	if err != nil {
+		return "", false, err
+	}
+	return arn, true, nil
+}
+
 // attachS3Policy attaches a policy to the IAM role for S3 access
 func (s *Service) attachS3Policy(ctx context.Context, roleName, s3Bucket, orgID string) error {
 	// Create inline policy for S3 access (scoped to tenant's prefix)
@@ -369,6 +614,7 @@ func (s *Service) attachS3Policy(ctx context.Context, roleName, s3Bucket, orgID
 		PolicyName:     aws.String("tenant-s3-access"),
 		PolicyDocument: aws.String(string(policyJSON)),
 	})
+	s.audit.RecordCall(ctx, "iam:PutRolePolicy", orgID, err, roleName+"/tenant-s3-access")
 
 	if err != nil {
 		return fmt.Errorf("failed to attach S3 policy: %w", err)
@@ -381,83 +627,276 @@ func (s *Service) attachS3Policy(ctx context.Context, roleName, s3Bucket, orgID
 // High-Level Provisioning Methods
 // ============================================================================
 
+// ProvisionOptions describes the tenant account to provision
+type ProvisionOptions struct {
+	OrgID            string
+	OrganizationType acctv1.OrganizationType
+	PlanTier         acctv1.PlanTier
+	S3Bucket         string // Bucket the tenant may use under orgs/<org>; empty for none
+	Region           string // Preferred cluster region
+	Registry         bool   // Open a registry prefix and install its pull secret
+	JobTopic         bool   // Create a job topic of the tenant's own
+	Ingress          bool   // Serve the tenant's subdomain
+	Template         string // Environment template to seed the namespace with
+}
+
 // ProvisionAccount creates all resources for a new tenant account
-func (s *Service) ProvisionAccount(ctx context.Context, orgID string, orgType acctv1.OrganizationType, tier acctv1.PlanTier, s3Bucket string) (*AccountProvisioningResult, error) {
+func (s *Service) ProvisionAccount(ctx context.Context, opts ProvisionOptions) (*AccountProvisioningResult, error) {
+	// A GitOps server commits the tenant's objects instead of creating them
+	if s.gitops != nil {
+		return s.provisionGitOps(ctx, opts)
+	}
+
+	orgID, tier := opts.OrgID, opts.PlanTier
 	result := &AccountProvisioningResult{
 		OrganizationID: orgID,
 	}
 
+	// 0. Choose the cluster; every later Kubernetes call goes to its client
+	cluster, err := s.placeTenant(ctx, tier, opts.Region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place tenant: %w", err)
+	}
+	kc := cluster.Client
+	result.Cluster = cluster.Name
+	created := &provisioned{cluster: cluster, orgID: orgID}
+
 	// 1. Create Kubernetes namespace
-	namespace, err := s.createK8sNamespace(ctx, orgID, tier)
+	namespace, err := s.createK8sNamespace(ctx, kc, orgID, opts.OrganizationType, tier)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create namespace: %w", err)
 	}
 	result.Namespace = namespace
+	created.namespace = namespace
 
 	// 2. Apply resource quotas
-	quota, err := s.applyResourceQuota(ctx, namespace, tier)
+	quota, err := s.applyResourceQuota(ctx, kc, namespace, tier)
 	if err != nil {
-		// Cleanup namespace on failure
-		s.k8sClient.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+		s.rollback(ctx, created)
 		return nil, fmt.Errorf("failed to apply quota: %w", err)
 	}
 	result.ResourceQuota = quota
 
-	// 3. Create IAM role
-	iamRoleARN, err := s.createIAMRole(ctx, orgID)
-	if err != nil {
-		// Cleanup namespace on failure
-		s.k8sClient.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
-		return nil, fmt.Errorf("failed to create IAM role: %w", err)
+	// 2b. Ensure tier PriorityClasses exist and restrict the tenant to its own
+	if err := s.ensurePriorityClasses(ctx, kc); err != nil {
+		s.rollback(ctx, created)
+		return nil, fmt.Errorf("failed to ensure priority classes: %w", err)
+	}
+	if err := s.applyPriorityGuard(ctx, kc, orgID, namespace, tier); err != nil {
+		s.rollback(ctx, created)
+		return nil, fmt.Errorf("failed to apply priority guard: %w", err)
 	}
-	result.IAMRoleARN = iamRoleARN
 
-	// 4. Attach S3 policy
-	if s3Bucket != "" {
-		roleName := fmt.Sprintf("tenant-%s-role", orgID)
-		if err := s.attachS3Policy(ctx, roleName, s3Bucket, orgID); err != nil {
-			// Cleanup on failure
-			s.cleanupResources(ctx, orgID, namespace, roleName)
-			return nil, fmt.Errorf("failed to attach S3 policy: %w", err)
-		}
-		result.S3Bucket = s3Bucket
-		result.S3Prefix = fmt.Sprintf("orgs/%s", orgID)
+	// 3-4. Create the IAM role and attach the S3 policy
+	if err := s.provisionIAM(ctx, created, opts.S3Bucket, result); err != nil {
+		return nil, err
 	}
 
 	// 5. Create service account with IRSA
-	if err := s.createServiceAccount(ctx, namespace, orgID, iamRoleARN); err != nil {
-		s.cleanupResources(ctx, orgID, namespace, fmt.Sprintf("tenant-%s-role", orgID))
+	if err := s.createServiceAccount(ctx, kc, namespace, orgID, result.IAMRoleARN); err != nil {
+		s.rollback(ctx, created)
 		return nil, fmt.Errorf("failed to create service account: %w", err)
 	}
 
+	// 5b. Open the tenant's registry prefix and install its pull secret
+	if opts.Registry {
+		result.Registry, err = s.enableRegistry(ctx, kc, orgID, created.roleName, namespace)
+		if err != nil {
+			s.rollback(ctx, created)
+			return nil, fmt.Errorf("failed to set up container registry: %w", err)
+		}
+	}
+
 	// 6. Create RBAC roles
-	if err := s.createRBAC(ctx, namespace, orgID); err != nil {
-		s.cleanupResources(ctx, orgID, namespace, fmt.Sprintf("tenant-%s-role", orgID))
+	if err := s.applyRBAC(ctx, kc, namespace, orgID); err != nil {
+		s.rollback(ctx, created)
 		return nil, fmt.Errorf("failed to create RBAC: %w", err)
 	}
 
+	// 6b. Seed the namespace with the requested environment template
+	if opts.Template != "" {
+		seed := &storage.AccountRecord{OrganizationID: orgID, Namespace: namespace, PlanTier: tier.String()}
+		result.Template, err = s.applyTemplate(ctx, cluster, seed, opts.Template)
+		if err != nil {
+			s.rollback(ctx, created)
+			return nil, fmt.Errorf("failed to apply environment template: %w", err)
+		}
+	}
+
 	// 7. Apply network policies (optional)
-	if err := s.applyNetworkPolicy(ctx, namespace); err != nil {
+	if err := s.applyNetworkPolicy(ctx, kc, orgID, namespace); err != nil {
 		// Non-fatal, just log
 		fmt.Printf("Warning: failed to apply network policy: %v\n", err)
 	}
 
+	// 7b. Serve the tenant's subdomain
+	if opts.Ingress {
+		created.ingress = true
+		result.IngressHostname, err = s.enableIngress(ctx, cluster, orgID, namespace, tier.String())
+		if err != nil {
+			s.rollback(ctx, created)
+			return nil, fmt.Errorf("failed to set up ingress: %w", err)
+		}
+	}
+
+	// 7c-8. Give the tenant a job topic of its own and open billing
+	if err := s.provisionExternal(ctx, created, opts.JobTopic, tier, result); err != nil {
+		return nil, err
+	}
+
+	// 9. Register the account and stage account.created in the same transaction
+	record := provisionedRecord(opts, result)
+	record.Status = storage.AccountStatusActive
+	s.resetTrial(record, time.Now())
+	if err := s.saveAccount(ctx, record, events.TypeAccountCreated, ""); err != nil {
+		s.rollback(ctx, created)
+		return nil, fmt.Errorf("failed to register account: %w", err)
+	}
+	result.Status = record.Status
+	result.CreatedAt = record.CreatedAt
+
 	return result, nil
 }
 
-// cleanupResources removes resources on provisioning failure
-func (s *Service) cleanupResources(ctx context.Context, orgID, namespace, roleName string) {
-	// Delete namespace (cascades to all resources in it)
-	s.k8sClient.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+// provisioned records what a provisioning run has created so far, so that a
+// failed run removes exactly that
+type provisioned struct {
+	cluster   *clusters.Cluster
+	orgID     string
+	namespace string // Set once the namespace exists
+	roleName  string // Set once the IAM role exists
+	ingress   bool   // Set before the Gateway is edited
+	jobTopic  string
+	billing   bool
+	exported  bool // Set once the tenant is committed to the GitOps repository
+}
+
+// provisionIAM creates the tenant's IAM role and attaches the S3 policy for
+// s3Bucket, if any, filling in result
+func (s *Service) provisionIAM(ctx context.Context, created *provisioned, s3Bucket string, result *AccountProvisioningResult) error {
+	orgID := created.orgID
+	roleName := fmt.Sprintf("tenant-%s-role", orgID)
+
+	iamRoleARN, err := s.createIAMRole(ctx, orgID, roleName, result.Namespace, created.cluster.ClusterARN)
+	if err != nil {
+		s.rollback(ctx, created)
+		return fmt.Errorf("failed to create IAM role: %w", err)
+	}
+	created.roleName = roleName
+	result.IAMRoleARN = iamRoleARN
+
+	if s3Bucket != "" {
+		if err := s.attachS3Policy(ctx, roleName, s3Bucket, orgID); err != nil {
+			s.rollback(ctx, created)
+			return fmt.Errorf("failed to attach S3 policy: %w", err)
+		}
+		result.S3Bucket = s3Bucket
+		result.S3Prefix = fmt.Sprintf("orgs/%s", orgID)
+	}
+	return nil
+}
+
+// provisionExternal creates the tenant's job topic, if requested, and opens
+// its billing customer and subscription, filling in result
+func (s *Service) provisionExternal(ctx context.Context, created *provisioned, jobTopic bool, tier acctv1.PlanTier, result *AccountProvisioningResult) error {
+	if jobTopic {
+		topic, err := s.createJobTopic(ctx, created.orgID, tier.String())
+		if err != nil {
+			s.rollback(ctx, created)
+			return fmt.Errorf("failed to create job topic: %w", err)
+		}
+		created.jobTopic = topic
+		result.JobTopic = topic
+	}
+
+	if err := s.startBilling(ctx, created.orgID, tier); err != nil {
+		s.rollback(ctx, created)
+		return fmt.Errorf("failed to set up billing: %w", err)
+	}
+	created.billing = true
+	return nil
+}
+
+// provisionedRecord is the account record for a provisioning result
+func provisionedRecord(opts ProvisionOptions, result *AccountProvisioningResult) *storage.AccountRecord {
+	return &storage.AccountRecord{
+		OrganizationID:   opts.OrgID,
+		Namespace:        result.Namespace,
+		Cluster:          result.Cluster,
+		OrganizationType: opts.OrganizationType.String(),
+		PlanTier:         opts.PlanTier.String(),
+		IAMRoleARN:       result.IAMRoleARN,
+		S3Bucket:         result.S3Bucket,
+		S3Prefix:         result.S3Prefix,
+		Registry:         result.Registry,
+		JobTopic:         result.JobTopic,
+		IngressHostname:  result.IngressHostname,
+		Template:         result.Template,
+	}
+}
+
+// rollback removes what a failed provisioning run created, newest first.
+// Failures are left in the audit log.
+func (s *Service) rollback(ctx context.Context, created *provisioned) {
+	orgID := created.orgID
+	if created.exported {
+		s.removeExport(ctx, created.cluster, orgID)
+	}
+	if created.billing {
+		s.stopBilling(ctx, orgID)
+	}
+	s.deleteJobTopic(ctx, orgID, created.jobTopic)
+	if created.ingress {
+		s.removeIngress(ctx, created.cluster, orgID)
+	}
+
+	// Deleting the namespace cascades to everything in it
+	if created.namespace != "" {
+		s.deleteNamespace(ctx, created.cluster.Client, orgID, created.namespace)
+	}
 
 	// Delete IAM role and attached policies
-	s.iamClient.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{
+	if created.roleName != "" {
+		s.deleteRolePolicy(ctx, orgID, created.roleName)
+		s.deleteRegistryPolicy(ctx, orgID, created.roleName)
+		s.deleteRole(ctx, orgID, created.roleName)
+	}
+}
+
+// deleteNamespace deletes a tenant namespace and records the call
+func (s *Service) deleteNamespace(ctx context.Context, kc kubernetes.Interface, orgID, namespace string) error {
+	err := kc.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+	s.audit.RecordCall(ctx, "kubernetes:DeleteNamespace", orgID, err, "namespace/"+namespace)
+	return err
+}
+
+// deleteRolePolicy removes the tenant's inline S3 policy and records the call
+func (s *Service) deleteRolePolicy(ctx context.Context, orgID, roleName string) error {
+	_, err := s.iamClient.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{
 		RoleName:   aws.String(roleName),
 		PolicyName: aws.String("tenant-s3-access"),
 	})
-	s.iamClient.DeleteRole(ctx, &iam.DeleteRoleInput{
+	s.audit.RecordCall(ctx, "iam:DeleteRolePolicy", orgID, err, roleName+"/tenant-s3-access")
+	return err
+}
+
+// deleteRole deletes the tenant's IAM role and records the call
+func (s *Service) deleteRole(ctx context.Context, orgID, roleName string) error {
+	_, err := s.iamClient.DeleteRole(ctx, &iam.DeleteRoleInput{
 		RoleName: aws.String(roleName),
 	})
+	s.audit.RecordCall(ctx, "iam:DeleteRole", orgID, err, roleName)
+	return err
+}
+
+// roleNameFromARN returns the role name of an IAM role ARN
+func roleNameFromARN(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+// orgIDFromNamespace recovers the organization ID from a tenant namespace name
+func orgIDFromNamespace(namespace string) string {
+	return strings.TrimPrefix(namespace, "tenant-")
 }
 
 // DeleteAccount removes all resources for a tenant
@@ -465,24 +904,102 @@ func (s *Service) DeleteAccount(ctx context.Context, orgID string) error {
 	namespace := fmt.Sprintf("tenant-%s", orgID)
 	roleName := fmt.Sprintf("tenant-%s-role", orgID)
 
-	// Delete namespace (cascades to all K8s resources)
-	err := s.k8sClient.CoreV1().Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+	// Find the tenant's cluster, namespace and role; unregistered tenants fall back
+	// to the default cluster and naming
+	var clusterName, jobTopic, gitOpsRevision string
+	var roleLinked bool
+	if record, err := s.accounts.Get(ctx, orgID); err == nil {
+		clusterName = record.Cluster
+		jobTopic = record.JobTopic
+		gitOpsRevision = record.GitOpsRevision
+		namespace = record.Namespace
+		if record.IAMRoleARN != "" {
+			roleName = roleNameFromARN(record.IAMRoleARN)
+		}
+		roleLinked = record.IAMRoleLinked
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("failed to look up account: %w", err)
+	}
+	cluster, err := s.clusters.Get(clusterName)
+	if err != nil {
+		return err
+	}
+	kc := cluster.Client
+
+	// Take a GitOps tenant out of the repository first, so that the sync
+	// controller does not recreate what is deleted below
+	if gitOpsRevision != "" {
+		if err := s.removeExport(ctx, cluster, orgID); err != nil {
+			return fmt.Errorf("failed to remove tenant from gitops repository: %w", err)
+		}
+	}
+
+	// Take the tenant's hostnames off the shared Gateway
+	if err := s.removeIngress(ctx, cluster, orgID); err != nil {
+		return fmt.Errorf("failed to remove ingress: %w", err)
+	}
+
+	// Delete child namespaces first, then the tenant namespace (cascades to all K8s resources)
+	if err := s.deleteSubNamespaces(ctx, kc, orgID, namespace); err != nil {
+		return fmt.Errorf("failed to delete sub-namespaces: %w", err)
+	}
+	err = s.deleteNamespace(ctx, kc, orgID, namespace)
+	if apierrors.IsNotFound(err) && gitOpsRevision != "" {
+		err = nil // Never synced
+	}
 	if err != nil {
 		return fmt.Errorf("failed to delete namespace: %w", err)
 	}
 
 	// Delete IAM role policies
-	s.iamClient.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{
-		RoleName:   aws.String(roleName),
-		PolicyName: aws.String("tenant-s3-access"),
-	})
+	s.deleteRolePolicy(ctx, orgID, roleName)
+	if err := s.deleteRegistryPolicy(ctx, orgID, roleName); err != nil {
+		return fmt.Errorf("failed to delete registry policy: %w", err)
+	}
 
-	// Delete IAM role
-	_, err = s.iamClient.DeleteRole(ctx, &iam.DeleteRoleInput{
-		RoleName: aws.String(roleName),
+	// Delete IAM role, unless it was linked on import and belongs to the tenant
+	if !roleLinked {
+		if err := s.deleteRole(ctx, orgID, roleName); err != nil {
+			return fmt.Errorf("failed to delete IAM role: %w", err)
+		}
+	}
+
+	// Remove the standby copy and its IAM role
+	if err := s.deleteReplica(ctx, orgID, namespace); err != nil {
+		return fmt.Errorf("failed to delete standby replica: %w", err)
+	}
+
+	// Machine credentials must not outlive the tenant
+	if err := s.revokeAllAPIKeys(ctx, orgID); err != nil {
+		return fmt.Errorf("failed to revoke api keys: %w", err)
+	}
+
+	// Neither may memberships, invitations or IdP group membership
+	if err := s.deleteMembers(ctx, orgID); err != nil {
+		return fmt.Errorf("failed to remove members: %w", err)
+	}
+
+	// Drop the tenant's job topic with any jobs still queued on it
+	if err := s.deleteJobTopic(ctx, orgID, jobTopic); err != nil {
+		return fmt.Errorf("failed to delete job topic: %w", err)
+	}
+
+	// Stop invoicing the tenant
+	if err := s.stopBilling(ctx, orgID); err != nil {
+		return fmt.Errorf("failed to cancel subscription: %w", err)
+	}
+
+	// Remove from the registry and stage account.deleted
+	event, err := events.NewAccountEvent(events.TypeAccountDeleted, events.AccountData{
+		OrganizationID: orgID,
+		Namespace:      namespace,
+		Status:         "DELETED",
 	})
 	if err != nil {
-		return fmt.Errorf("failed to delete IAM role: %w", err)
+		return err
+	}
+	if err := s.accounts.Delete(ctx, orgID, event); err != nil {
+		return fmt.Errorf("failed to deregister account: %w", err)
 	}
 
 	return nil
@@ -490,10 +1007,18 @@ func (s *Service) DeleteAccount(ctx context.Context, orgID string) error {
 
 // AccountProvisioningResult holds the result of account provisioning
 type AccountProvisioningResult struct {
-	OrganizationID string
-	Namespace      string
-	IAMRoleARN     string
-	S3Bucket       string
-	S3Prefix       string
-	ResourceQuota  *acctv1.ResourceQuota
+	OrganizationID  string
+	Namespace       string
+	Cluster         string
+	IAMRoleARN      string
+	S3Bucket        string
+	S3Prefix        string
+	Registry        string
+	JobTopic        string
+	IngressHostname string
+	Template        *storage.AppliedTemplate
+	ResourceQuota   *acctv1.ResourceQuota
+	Status          string // ACTIVE, or PROVISIONING until the GitOps commit is synced
+	GitOpsRevision  string // Commit holding the tenant's objects; empty when created directly
+	CreatedAt       time.Time
 }
@@ -0,0 +1,403 @@
+package accountservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/clusters"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/egress"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Labels linking a child namespace to its tenant namespace
+const (
+	parentNamespaceLabel = "parent-namespace"
+	subNamespaceLabel    = "sub-namespace"
+)
+
+var (
+	// ErrInvalidSubNamespace is returned for names that do not form a valid namespace
+	ErrInvalidSubNamespace = errors.New("invalid sub-namespace name")
+	// ErrSubNamespaceExists is returned when the tenant already has a child of that name
+	ErrSubNamespaceExists = errors.New("sub-namespace already exists")
+)
+
+// SubNamespace is a child namespace of a tenant, e.g. tenant-acme-staging
+type SubNamespace struct {
+	Name      string // Short name, e.g. "staging"
+	Namespace string // Full namespace name
+	Quota     *acctv1.ResourceQuota
+	CreatedAt time.Time
+}
+
+// ============================================================================
+// Sub-Namespaces
+// ============================================================================
+
+// CreateSubNamespace creates a child namespace under the tenant namespace. The
+// tier quota is re-split equally across the parent and all children, and the
+// parent's roles, role bindings and network policies are copied into every child.
+func (s *Service) CreateSubNamespace(ctx context.Context, orgID, name string) (*SubNamespace, error) {
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if record.GitOpsRevision != "" {
+		return nil, fmt.Errorf("sub-namespaces: %w", ErrGitOpsUnsupported)
+	}
+	namespace := fmt.Sprintf("%s-%s", record.Namespace, name)
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSubNamespace, errs[0])
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidSubNamespace, namespace, errs[0])
+	}
+
+	cluster, err := s.clusterFor(record)
+	if err != nil {
+		return nil, err
+	}
+	kc := cluster.Client
+	tier := acctv1.PlanTier(acctv1.PlanTier_value[record.PlanTier])
+	orgType := acctv1.OrganizationType(acctv1.OrganizationType_value[record.OrganizationType])
+
+	child := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespace,
+			Labels: map[string]string{
+				"tenant":             record.Namespace, // Shares the parent's tenant-isolation policy
+				"tenant-id":          orgID,
+				"plan-tier":          record.PlanTier,
+				"organization-type":  record.OrganizationType,
+				"managed-by":         "account-provisioning-service",
+				"created-at":         time.Now().Format(time.RFC3339),
+				parentNamespaceLabel: record.Namespace,
+				subNamespaceLabel:    name,
+			},
+			Annotations: map[string]string{
+				"organization-id": orgID,
+				"description":     fmt.Sprintf("Sub-namespace %s of organization %s", name, orgID),
+			},
+		},
+	}
+	applyPodSecurityLabels(child.Labels, s.podSecurityFor(orgType, tier))
+
+	created, err := kc.CoreV1().Namespaces().Create(ctx, child, metav1.CreateOptions{})
+	s.audit.RecordCall(ctx, "kubernetes:CreateNamespace", orgID, err, "namespace/"+namespace)
+	if apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("%w: %s", ErrSubNamespaceExists, namespace)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create namespace: %w", err)
+	}
+
+	if err := s.scaffoldSubNamespace(ctx, cluster, record, tier, namespace); err != nil {
+		s.deleteNamespace(ctx, kc, orgID, namespace)
+		return nil, err
+	}
+	// A suspended tenant's new child is suspended with it
+	if record.Status == storage.AccountStatusSuspended {
+		if err := s.suspendNamespace(ctx, kc, orgID, namespace); err != nil {
+			s.deleteNamespace(ctx, kc, orgID, namespace)
+			return nil, err
+		}
+	}
+
+	// Re-split the quota and bring every child's RBAC and policies up to date
+	share, err := s.applyQuotaShares(ctx, kc, record, tier)
+	if err != nil {
+		return nil, err
+	}
+	children, err := subNamespaces(ctx, kc, record.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range children {
+		if err := s.syncFromParent(ctx, kc, orgID, record.Namespace, c.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	return &SubNamespace{
+		Name:      name,
+		Namespace: namespace,
+		Quota:     share,
+		CreatedAt: created.CreationTimestamp.Time,
+	}, nil
+}
+
+// ListSubNamespaces returns a tenant's child namespaces with their quota share
+func (s *Service) ListSubNamespaces(ctx context.Context, orgID string) ([]SubNamespace, error) {
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	cluster, err := s.clusterFor(record)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := subNamespaces(ctx, cluster.Client, record.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	quotaSpec, err := QuotaForTier(acctv1.PlanTier(acctv1.PlanTier_value[record.PlanTier]))
+	if err != nil {
+		return nil, err
+	}
+	share := splitQuota(quotaSpec, 1+len(children))
+
+	result := make([]SubNamespace, 0, len(children))
+	for _, c := range children {
+		result = append(result, SubNamespace{
+			Name:      c.Labels[subNamespaceLabel],
+			Namespace: c.Name,
+			Quota:     share,
+			CreatedAt: c.CreationTimestamp.Time,
+		})
+	}
+	return result, nil
+}
+
+// scaffoldSubNamespace applies the per-namespace guards a new child needs
+func (s *Service) scaffoldSubNamespace(ctx context.Context, cluster *clusters.Cluster, record *storage.AccountRecord, tier acctv1.PlanTier, namespace string) error {
+	if err := s.applyPriorityGuard(ctx, cluster.Client, record.OrganizationID, namespace, tier); err != nil {
+		return err
+	}
+
+	list := accountAllowlist(record)
+	if list.Empty() {
+		return nil
+	}
+	_, err := s.egress.Apply(ctx, cluster.Client, cluster.Dynamic, record.OrganizationID, namespace, list)
+	s.audit.RecordCall(ctx, "kubernetes:ApplyEgressPolicy", record.OrganizationID, err, namespace+"/egress/"+egress.PolicyName)
+	if err != nil {
+		return fmt.Errorf("failed to apply egress policy: %w", err)
+	}
+	return nil
+}
+
+// subNamespaces lists the live child namespaces of a tenant namespace
+func subNamespaces(ctx context.Context, kc kubernetes.Interface, parent string) ([]corev1.Namespace, error) {
+	list, err := kc.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: parentNamespaceLabel + "=" + parent,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sub-namespaces: %w", err)
+	}
+
+	var live []corev1.Namespace
+	for _, ns := range list.Items {
+		if ns.DeletionTimestamp == nil {
+			live = append(live, ns)
+		}
+	}
+	return live, nil
+}
+
+// tenantNamespaces returns the tenant namespace followed by its children
+func tenantNamespaces(ctx context.Context, kc kubernetes.Interface, record *storage.AccountRecord) ([]string, error) {
+	children, err := subNamespaces(ctx, kc, record.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{record.Namespace}
+	for _, c := range children {
+		names = append(names, c.Name)
+	}
+	return names, nil
+}
+
+// deleteSubNamespaces deletes every child of a tenant namespace
+func (s *Service) deleteSubNamespaces(ctx context.Context, kc kubernetes.Interface, orgID, parent string) error {
+	children, err := subNamespaces(ctx, kc, parent)
+	if err != nil {
+		return err
+	}
+	for _, c := range children {
+		if err := s.deleteNamespace(ctx, kc, orgID, c.Name); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete namespace %s: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// Quota Split
+// ============================================================================
+
+// applyQuotaShares writes an equal share of the tier quota to the tenant
+// namespace and each child, so the tree as a whole never exceeds the plan
+func (s *Service) applyQuotaShares(ctx context.Context, kc kubernetes.Interface, record *storage.AccountRecord, tier acctv1.PlanTier) (*acctv1.ResourceQuota, error) {
+	quotaSpec, err := QuotaForTier(tier)
+	if err != nil {
+		return nil, err
+	}
+	namespaces, err := tenantNamespaces(ctx, kc, record)
+	if err != nil {
+		return nil, err
+	}
+	share := splitQuota(quotaSpec, len(namespaces))
+
+	for _, ns := range namespaces {
+		quota, err := resourceQuotaObject(ns, tier, share)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.applyQuotaObject(ctx, kc, record.OrganizationID, quota); err != nil {
+			return nil, fmt.Errorf("failed to apply resource quota in %s: %w", ns, err)
+		}
+	}
+	return share, nil
+}
+
+// splitQuota divides a quota into n equal shares. Object counts are rounded
+// down but never below one, so every namespace can run something.
+func splitQuota(q *acctv1.ResourceQuota, n int) *acctv1.ResourceQuota {
+	if n <= 1 {
+		return q
+	}
+	cpu := func(v string) string {
+		qty := resource.MustParse(v)
+		return resource.NewMilliQuantity(qty.MilliValue()/int64(n), resource.DecimalSI).String()
+	}
+	memory := func(v string) string {
+		qty := resource.MustParse(v)
+		return resource.NewQuantity(qty.Value()/int64(n), resource.BinarySI).String()
+	}
+	count := func(v int32) int32 {
+		return max(v/int32(n), 1)
+	}
+
+	return &acctv1.ResourceQuota{
+		RequestsCpu:     cpu(q.RequestsCpu),
+		RequestsMemory:  memory(q.RequestsMemory),
+		LimitsCpu:       cpu(q.LimitsCpu),
+		LimitsMemory:    memory(q.LimitsMemory),
+		MaxPvcs:         count(q.MaxPvcs),
+		MaxServices:     count(q.MaxServices),
+		MaxDeployments:  count(q.MaxDeployments),
+		MaxStatefulsets: count(q.MaxStatefulsets),
+	}
+}
+
+// ============================================================================
+// Propagation
+// ============================================================================
+
+// syncFromParent copies the tenant namespace's roles, role bindings and network
+// policies into a child
+func (s *Service) syncFromParent(ctx context.Context, kc kubernetes.Interface, orgID, parent, child string) error {
+	return s.copyPolicies(ctx, kc, kc, orgID, parent, child, map[string]string{parentNamespaceLabel: parent})
+}
+
+// copyPolicies creates or updates the roles, role bindings and network policies
+// of srcNS in dstNS, possibly on another cluster, adding labels to each copy. The
+// egress allowlist policy is rendered per namespace by the egress applier and is
+// skipped here.
+func (s *Service) copyPolicies(ctx context.Context, src, dst kubernetes.Interface, orgID, srcNS, dstNS string, labels map[string]string) error {
+	roles, err := src.RbacV1().Roles(srcNS).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list roles: %w", err)
+	}
+	for _, r := range roles.Items {
+		r.ObjectMeta = propagatedMeta(r.ObjectMeta, dstNS, labels)
+		existing, err := dst.RbacV1().Roles(dstNS).Get(ctx, r.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			_, err = dst.RbacV1().Roles(dstNS).Create(ctx, &r, metav1.CreateOptions{})
+		case err == nil && !equality.Semantic.DeepEqual(existing.Rules, r.Rules):
+			existing.Rules = r.Rules
+			_, err = dst.RbacV1().Roles(dstNS).Update(ctx, existing, metav1.UpdateOptions{})
+		case err == nil:
+			continue
+		}
+		s.audit.RecordCall(ctx, "kubernetes:ApplyRole", orgID, err, dstNS+"/role/"+r.Name)
+		if err != nil {
+			return fmt.Errorf("failed to copy role %s: %w", r.Name, err)
+		}
+	}
+
+	bindings, err := src.RbacV1().RoleBindings(srcNS).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list role bindings: %w", err)
+	}
+	for _, b := range bindings.Items {
+		b.ObjectMeta = propagatedMeta(b.ObjectMeta, dstNS, labels)
+		existing, err := dst.RbacV1().RoleBindings(dstNS).Get(ctx, b.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			_, err = dst.RbacV1().RoleBindings(dstNS).Create(ctx, &b, metav1.CreateOptions{})
+		case err == nil && existing.RoleRef != b.RoleRef:
+			// roleRef is immutable; replace the binding
+			if err = dst.RbacV1().RoleBindings(dstNS).Delete(ctx, b.Name, metav1.DeleteOptions{}); err == nil {
+				_, err = dst.RbacV1().RoleBindings(dstNS).Create(ctx, &b, metav1.CreateOptions{})
+			}
+		case err == nil && !equality.Semantic.DeepEqual(existing.Subjects, b.Subjects):
+			existing.Subjects = b.Subjects
+			_, err = dst.RbacV1().RoleBindings(dstNS).Update(ctx, existing, metav1.UpdateOptions{})
+		case err == nil:
+			continue
+		}
+		s.audit.RecordCall(ctx, "kubernetes:ApplyRoleBinding", orgID, err, dstNS+"/rolebinding/"+b.Name)
+		if err != nil {
+			return fmt.Errorf("failed to copy role binding %s: %w", b.Name, err)
+		}
+	}
+
+	policies, err := src.NetworkingV1().NetworkPolicies(srcNS).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list network policies: %w", err)
+	}
+	for _, p := range policies.Items {
+		if p.Name == egress.PolicyName {
+			continue
+		}
+		p.ObjectMeta = propagatedMeta(p.ObjectMeta, dstNS, labels)
+		existing, err := dst.NetworkingV1().NetworkPolicies(dstNS).Get(ctx, p.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			_, err = dst.NetworkingV1().NetworkPolicies(dstNS).Create(ctx, &p, metav1.CreateOptions{})
+		case err == nil && !equality.Semantic.DeepEqual(existing.Spec, p.Spec):
+			existing.Spec = p.Spec
+			_, err = dst.NetworkingV1().NetworkPolicies(dstNS).Update(ctx, existing, metav1.UpdateOptions{})
+		case err == nil:
+			continue
+		}
+		s.audit.RecordCall(ctx, "kubernetes:ApplyNetworkPolicy", orgID, err, dstNS+"/networkpolicy/"+p.Name)
+		if err != nil {
+			return fmt.Errorf("failed to copy network policy %s: %w", p.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// propagatedMeta keeps an object's name, labels and annotations for a copy in namespace
+func propagatedMeta(meta metav1.ObjectMeta, namespace string, extra map[string]string) metav1.ObjectMeta {
+	labels := map[string]string{}
+	for k, v := range meta.Labels {
+		labels[k] = v
+	}
+	for k, v := range extra {
+		labels[k] = v
+	}
+
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   namespace,
+		Labels:      labels,
+		Annotations: meta.Annotations,
+	}
+}
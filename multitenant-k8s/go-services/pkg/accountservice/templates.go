@@ -0,0 +1,143 @@
+package accountservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/clusters"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/templates"
+)
+
+// Errors returned by template provisioning and UpgradeTemplate
+var (
+	ErrTemplatesDisabled = errors.New("environment templates are not enabled on this server")
+	ErrNoTemplate        = errors.New("account has no environment template")
+)
+
+// ============================================================================
+// Environment Templates
+// ============================================================================
+
+// TemplatesEnabled reports whether new tenants can be seeded from a template
+func (s *Service) TemplatesEnabled() bool {
+	return s.templates != nil
+}
+
+// ListTemplates returns the templates tenants can be seeded from, at the
+// version an upgrade would move them to
+func (s *Service) ListTemplates() ([]*templates.Template, error) {
+	if s.templates == nil {
+		return nil, ErrTemplatesDisabled
+	}
+	return s.templates.List(), nil
+}
+
+// applyTemplate renders the named template for the tenant and applies it to
+// its namespace. Objects of record.Template that the new render no longer
+// contains are deleted, so the same call installs, upgrades and switches.
+func (s *Service) applyTemplate(ctx context.Context, cluster *clusters.Cluster, record *storage.AccountRecord, name string) (*storage.AppliedTemplate, error) {
+	if s.templates == nil {
+		return nil, ErrTemplatesDisabled
+	}
+	t, err := s.templates.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	objs, err := s.templates.Render(ctx, t, templates.Values{
+		OrgID:     record.OrganizationID,
+		Namespace: record.Namespace,
+		Tier:      record.PlanTier,
+		Cluster:   cluster.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+
+	applier := templates.NewApplier(cluster.Client, cluster.Dynamic)
+	applied, err := applier.Apply(ctx, t, record.Namespace, objs)
+	s.audit.RecordCall(ctx, "kubernetes:ApplyTemplate", record.OrganizationID, err, record.Namespace+"/template/"+name+"@"+t.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &storage.AppliedTemplate{
+		Name:      t.Name,
+		Version:   t.Version,
+		AppliedAt: time.Now().UTC(),
+	}
+	for _, o := range applied {
+		result.Objects = append(result.Objects, storage.TemplateObject(o))
+	}
+
+	if record.Template != nil {
+		previous := make([]templates.Object, 0, len(record.Template.Objects))
+		for _, o := range record.Template.Objects {
+			previous = append(previous, templates.Object(o))
+		}
+		err := applier.Prune(ctx, record.Namespace, previous, applied)
+		s.audit.RecordCall(ctx, "kubernetes:PruneTemplate", record.OrganizationID, err, record.Namespace+"/template/"+record.Template.Name+"@"+record.Template.Version)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// UpgradeTemplate re-applies the account's template at the catalog's current
+// version, or moves the account to another template when name is set. The
+// previously applied version is returned alongside the updated record.
+func (s *Service) UpgradeTemplate(ctx context.Context, orgID, name string) (*storage.AccountRecord, string, error) {
+	if s.templates == nil {
+		return nil, "", ErrTemplatesDisabled
+	}
+	record, err := s.accounts.Get(ctx, orgID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var previous string
+	if record.Template != nil {
+		previous = record.Template.Version
+		if name == "" {
+			name = record.Template.Name
+		}
+	}
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: organization %s", ErrNoTemplate, orgID)
+	}
+
+	cluster, err := s.clusterFor(record)
+	if err != nil {
+		return nil, "", err
+	}
+	// A GitOps tenant gets the new version by commit; files it dropped are
+	// pruned by the sync controller
+	if record.GitOpsRevision != "" {
+		if record.Template == nil || record.Template.Name != name {
+			record.Template = &storage.AppliedTemplate{Name: name}
+		}
+		if err := s.exportTenant(ctx, cluster, record); err != nil {
+			return nil, "", err
+		}
+	} else {
+		applied, err := s.applyTemplate(ctx, cluster, record, name)
+		if err != nil {
+			return nil, "", err
+		}
+		record.Template = applied
+	}
+
+	template, revision := record.Template, record.GitOpsRevision
+	err = s.modifyAccount(ctx, record, func(rec *storage.AccountRecord) {
+		rec.Template = template
+		rec.GitOpsRevision = revision
+	}, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	return record, previous, nil
+}
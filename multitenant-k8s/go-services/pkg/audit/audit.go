@@ -0,0 +1,173 @@
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+// Outcome values recorded on every event.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Event describes one mutation: either an RPC received by the account service
+// or a Kubernetes/IAM call it made on a tenant's behalf.
+type Event struct {
+	Time           time.Time       `json:"time"`
+	Caller         Caller          `json:"caller"`
+	Action         string          `json:"action"` // e.g. "CreateAccount", "kubernetes:CreateNamespace", "iam:CreateRole"
+	OrganizationID string          `json:"organization_id,omitempty"`
+	Request        json.RawMessage `json:"request,omitempty"`
+	ResourceIDs    []string        `json:"resource_ids,omitempty"`
+	Outcome        string          `json:"outcome"`
+	Error          string          `json:"error,omitempty"`
+}
+
+// Entry is an event together with its position in the hash chain.
+type Entry struct {
+	Sequence uint64
+	PrevHash string
+	Hash     string
+	Event    Event
+}
+
+// Logger writes events to the hash-chained audit log.
+type Logger struct {
+	store *storage.AuditStore
+	key   []byte
+}
+
+// NewLogger creates an audit logger backed by store. Record hashes are HMACs
+// under key, which must be kept outside the storage: whoever can write the
+// store but does not hold the key cannot rebuild a valid chain.
+func NewLogger(store *storage.AuditStore, key []byte) (*Logger, error) {
+	if len(key) < 32 {
+		return nil, errors.New("audit key must be at least 32 bytes")
+	}
+	return &Logger{store: store, key: key}, nil
+}
+
+// Record appends an event. The caller is taken from ctx when the event has none.
+func (l *Logger) Record(ctx context.Context, e Event) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if e.Caller == (Caller{}) {
+		e.Caller = CallerFromContext(ctx)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+
+	if _, err := l.store.Append(ctx, data, l.computeHash); err != nil {
+		return fmt.Errorf("failed to append audit event: %w", err)
+	}
+	return nil
+}
+
+// RecordCall records the outcome of a single mutating call. Failures to write the
+// audit log are logged rather than returned so they never mask err.
+func (l *Logger) RecordCall(ctx context.Context, action, orgID string, err error, resourceIDs ...string) {
+	if l == nil {
+		return
+	}
+
+	e := Event{
+		Action:         action,
+		OrganizationID: orgID,
+		ResourceIDs:    resourceIDs,
+		Outcome:        OutcomeSuccess,
+	}
+	if err != nil {
+		e.Outcome = OutcomeFailure
+		e.Error = err.Error()
+	}
+
+	if recErr := l.Record(ctx, e); recErr != nil {
+		log.Printf("audit: failed to record %s for %s: %v", action, orgID, recErr)
+	}
+}
+
+// RecordRPC records a mutating RPC together with its request message.
+func (l *Logger) RecordRPC(ctx context.Context, procedure, orgID string, req proto.Message, err error, resourceIDs ...string) {
+	if l == nil {
+		return
+	}
+
+	e := Event{
+		Action:         procedure,
+		OrganizationID: orgID,
+		ResourceIDs:    resourceIDs,
+		Outcome:        OutcomeSuccess,
+	}
+	if req != nil {
+		if data, mErr := protojson.Marshal(req); mErr == nil {
+			e.Request = data
+		}
+	}
+	if err != nil {
+		e.Outcome = OutcomeFailure
+		e.Error = err.Error()
+	}
+
+	if recErr := l.Record(ctx, e); recErr != nil {
+		log.Printf("audit: failed to record %s for %s: %v", procedure, orgID, recErr)
+	}
+}
+
+// List returns entries matching orgID (all if empty) with a sequence greater than
+// afterSeq, up to limit entries (no limit if <= 0).
+func (l *Logger) List(ctx context.Context, orgID string, afterSeq uint64, limit int) ([]Entry, error) {
+	records, err := l.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, r := range records {
+		if r.Sequence <= afterSeq {
+			continue
+		}
+
+		var e Event
+		if err := json.Unmarshal(r.Event, &e); err != nil {
+			return nil, fmt.Errorf("failed to decode audit event %d: %w", r.Sequence, err)
+		}
+		if orgID != "" && e.OrganizationID != orgID {
+			continue
+		}
+
+		entries = append(entries, Entry{Sequence: r.Sequence, PrevHash: r.PrevHash, Hash: r.Hash, Event: e})
+		if limit > 0 && len(entries) == limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// computeHash chains a record to its predecessor: HMAC-SHA256(key, seq || prevHash || event).
+func (l *Logger) computeHash(seq uint64, prevHash string, event []byte) string {
+	h := hmac.New(sha256.New, l.key)
+	h.Write([]byte(strconv.FormatUint(seq, 10)))
+	h.Write([]byte{0})
+	h.Write([]byte(prevHash))
+	h.Write([]byte{0})
+	h.Write(event)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"context"
+
+	connect "connectrpc.com/connect"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/auth"
+)
+
+// Caller identifies who triggered a mutation.
+type Caller struct {
+	Subject string `json:"subject"`           // authenticated principal, "anonymous" if unknown
+	Address string `json:"address,omitempty"` // remote address of the request
+}
+
+type callerKey struct{}
+
+// WithCaller returns a context carrying the caller identity.
+func WithCaller(ctx context.Context, c Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, c)
+}
+
+// CallerFromContext returns the caller stored in ctx, or an anonymous caller.
+func CallerFromContext(ctx context.Context) Caller {
+	if c, ok := ctx.Value(callerKey{}).(Caller); ok {
+		return c
+	}
+	return Caller{Subject: "anonymous"}
+}
+
+// NewCallerInterceptor stores the caller of every unary RPC in the request context
+// so audit events recorded further down the call stack can attribute it. Install it
+// after auth.NewInterceptor so the authenticated subject is used.
+func NewCallerInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			caller := CallerFromContext(ctx)
+			if p, ok := auth.PrincipalFromContext(ctx); ok {
+				caller.Subject = p.Subject
+			}
+			caller.Address = req.Peer().Addr
+			return next(WithCaller(ctx, caller), req)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+)
+
+// VerifyResult reports whether the audit log is intact.
+type VerifyResult struct {
+	Valid           bool
+	RecordsChecked  uint64
+	FirstInvalidSeq uint64 // 0 when the log is valid
+	Reason          string
+}
+
+// Verify walks the whole chain and recomputes every hash. It detects modified,
+// removed, reordered or inserted records as well as a truncated tail, and a
+// chain rewritten without the logger's key.
+func (l *Logger) Verify(ctx context.Context) (*VerifyResult, error) {
+	records, err := l.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	head, err := l.store.Head(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerifyResult{Valid: true}
+	prevHash := ""
+	for i, r := range records {
+		expectedSeq := uint64(i) + 1
+		result.RecordsChecked = expectedSeq
+
+		switch {
+		case r.Sequence != expectedSeq:
+			return result.fail(expectedSeq, fmt.Sprintf("expected sequence %d, found %d", expectedSeq, r.Sequence)), nil
+		case r.PrevHash != prevHash:
+			return result.fail(r.Sequence, "previous hash does not match the preceding record"), nil
+		case l.computeHash(r.Sequence, r.PrevHash, r.Event) != r.Hash:
+			return result.fail(r.Sequence, "record hash does not match its contents"), nil
+		}
+		prevHash = r.Hash
+	}
+
+	if head.Sequence != uint64(len(records)) || head.Hash != prevHash {
+		return result.fail(uint64(len(records))+1, fmt.Sprintf("head points at sequence %d but the chain ends at %d", head.Sequence, len(records))), nil
+	}
+
+	return result, nil
+}
+
+func (r *VerifyResult) fail(seq uint64, reason string) *VerifyResult {
+	r.Valid = false
+	r.FirstInvalidSeq = seq
+	r.Reason = reason
+	return r
+}
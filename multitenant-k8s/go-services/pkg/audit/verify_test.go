@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+func TestVerify(t *testing.T) {
+	recordKey := func(seq uint64) string { return fmt.Sprintf("audit/records/%020d", seq) }
+	key := []byte("audit-test-key-0123456789abcdefghij")
+	forger, err := NewLogger(nil, []byte("someone-else-key-0123456789abcdefgh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func(ctx context.Context, b *storage.MemoryBackend) error
+		valid   bool
+		invalid uint64
+	}{
+		{
+			name:   "intact",
+			tamper: func(context.Context, *storage.MemoryBackend) error { return nil },
+			valid:  true,
+		},
+		{
+			name: "modified event",
+			tamper: func(ctx context.Context, b *storage.MemoryBackend) error {
+				return rewrite(ctx, b, recordKey(2), func(r *storage.AuditRecord) {
+					r.Event = json.RawMessage(`{"action":"DeleteAccount","outcome":"success"}`)
+				})
+			},
+			invalid: 2,
+		},
+		{
+			name: "removed record",
+			tamper: func(ctx context.Context, b *storage.MemoryBackend) error {
+				return b.Delete(ctx, recordKey(2))
+			},
+			invalid: 2,
+		},
+		{
+			name: "rehashed record",
+			tamper: func(ctx context.Context, b *storage.MemoryBackend) error {
+				return rewrite(ctx, b, recordKey(2), func(r *storage.AuditRecord) {
+					r.Event = json.RawMessage(`{"action":"DeleteAccount","outcome":"success"}`)
+					r.Hash = forger.computeHash(r.Sequence, r.PrevHash, r.Event)
+				})
+			},
+			invalid: 2,
+		},
+		{
+			name: "rewritten chain",
+			tamper: func(ctx context.Context, b *storage.MemoryBackend) error {
+				// Build a consistent chain and head under another key and
+				// replace every stored entry with it
+				forged := storage.NewMemoryBackend()
+				forger := *forger
+				forger.store = storage.NewAuditStore(forged)
+				for _, action := range []string{"CreateAccount", "DeleteAccount", "SuspendAccount"} {
+					if err := forger.Record(ctx, Event{Action: action, OrganizationID: "acme", Outcome: OutcomeSuccess}); err != nil {
+						return err
+					}
+				}
+				entries, err := forged.List(ctx, "audit/")
+				if err != nil {
+					return err
+				}
+				for _, e := range entries {
+					if err := b.Put(ctx, e.Key, e.Value); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			invalid: 1,
+		},
+		{
+			name: "truncated tail",
+			tamper: func(ctx context.Context, b *storage.MemoryBackend) error {
+				return b.Delete(ctx, recordKey(3))
+			},
+			invalid: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			backend := storage.NewMemoryBackend()
+			logger, err := NewLogger(storage.NewAuditStore(backend), key)
+			if err != nil {
+				t.Fatalf("NewLogger: %v", err)
+			}
+			for _, action := range []string{"CreateAccount", "UpdateAccount", "SuspendAccount"} {
+				if err := logger.Record(ctx, Event{Action: action, OrganizationID: "acme", Outcome: OutcomeSuccess}); err != nil {
+					t.Fatalf("Record: %v", err)
+				}
+			}
+			if err := tt.tamper(ctx, backend); err != nil {
+				t.Fatalf("tamper: %v", err)
+			}
+
+			result, err := logger.Verify(ctx)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if result.Valid != tt.valid || result.FirstInvalidSeq != tt.invalid {
+				t.Errorf("Verify = valid %v at %d (%s), want valid %v at %d",
+					result.Valid, result.FirstInvalidSeq, result.Reason, tt.valid, tt.invalid)
+			}
+		})
+	}
+}
+
+// rewrite replaces the stored record at key with a modified copy
+func rewrite(ctx context.Context, b *storage.MemoryBackend, key string, change func(*storage.AuditRecord)) error {
+	raw, err := b.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	var r storage.AuditRecord
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return err
+	}
+	change(&r)
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return b.Put(ctx, key, data)
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+// APIKeyPrefix marks bearer tokens that are API keys rather than JWTs.
+const APIKeyPrefix = "mcpk_"
+
+// Scopes an API key can be granted.
+const (
+	ScopeJobsRead  = "jobs:read"
+	ScopeJobsWrite = "jobs:write"
+)
+
+// KnownScopes lists every scope accepted when minting a key.
+var KnownScopes = []string{ScopeJobsRead, ScopeJobsWrite}
+
+// NewAPIKey generates a key ID and secret and returns them with the full bearer
+// token ("mcpk_<id>_<secret>") handed to the client.
+func NewAPIKey() (id, secret, token string, err error) {
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", "", fmt.Errorf("failed to generate key id: %w", err)
+	}
+	secret, err = NewAPIKeySecret()
+	if err != nil {
+		return "", "", "", err
+	}
+	id = hex.EncodeToString(idBytes)
+	return id, secret, FormatAPIKey(id, secret), nil
+}
+
+// NewAPIKeySecret generates a fresh secret, e.g. when rotating a key.
+func NewAPIKeySecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate key secret: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// FormatAPIKey builds the bearer token for a key ID and secret.
+func FormatAPIKey(id, secret string) string {
+	return APIKeyPrefix + id + "_" + secret
+}
+
+// HashAPIKeySecret returns the stored form of a secret. Secrets are 256-bit
+// random values, so a plain SHA-256 is sufficient.
+func HashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func parseAPIKey(token string) (id, secret string, ok bool) {
+	rest, found := strings.CutPrefix(token, APIKeyPrefix)
+	if !found {
+		return "", "", false
+	}
+	id, secret, ok = strings.Cut(rest, "_")
+	return id, secret, ok && id != "" && secret != ""
+}
+
+// APIKeyAuthenticator resolves API keys to the organization that owns them.
+type APIKeyAuthenticator struct {
+	keys *storage.APIKeyStore
+}
+
+// NewAPIKeyAuthenticator creates an authenticator backed by keys.
+func NewAPIKeyAuthenticator(keys *storage.APIKeyStore) (*APIKeyAuthenticator, error) {
+	if keys == nil {
+		return nil, fmt.Errorf("api key store must not be nil")
+	}
+	return &APIKeyAuthenticator{keys: keys}, nil
+}
+
+// Authenticate validates an API key and returns a tenant principal limited to the key's scopes.
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	id, secret, ok := parseAPIKey(token)
+	if !ok {
+		return nil, errors.New("malformed api key")
+	}
+
+	rec, err := a.keys.Get(ctx, id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, errors.New("unknown api key")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up api key: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(HashAPIKeySecret(secret)), []byte(rec.SecretHash)) != 1 {
+		return nil, errors.New("invalid api key")
+	}
+	if !rec.Active(time.Now()) {
+		return nil, errors.New("api key revoked or expired")
+	}
+
+	return &Principal{
+		Subject:        "apikey:" + rec.ID,
+		OrganizationID: rec.OrganizationID,
+		Role:           RoleTenantUser,
+		Scopes:         rec.Scopes,
+	}, nil
+}
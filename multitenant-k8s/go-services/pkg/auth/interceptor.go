@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	connect "connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Authenticator resolves a bearer token to a principal.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+// Tokens routes API keys to apiKeys and everything else to jwt.
+// Either may be nil to disable that kind of credential.
+func Tokens(jwt, apiKeys Authenticator) Authenticator {
+	return tokenRouter{jwt: jwt, apiKeys: apiKeys}
+}
+
+type tokenRouter struct {
+	jwt     Authenticator
+	apiKeys Authenticator
+}
+
+func (r tokenRouter) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	if strings.HasPrefix(token, APIKeyPrefix) {
+		if r.apiKeys == nil {
+			return nil, errors.New("api keys are not accepted by this service")
+		}
+		return r.apiKeys.Authenticate(ctx, token)
+	}
+	if r.jwt == nil {
+		return nil, errors.New("only api keys are accepted by this service")
+	}
+	return r.jwt.Authenticate(ctx, token)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated principal.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// Policy decides which procedures need more than an authenticated tenant caller.
+type Policy struct {
+	// AdminPrefixes lists procedure prefixes (e.g. "/acctapi.v1.AccountProvisioningService/")
+	// that only platform admins may call.
+	AdminPrefixes []string
+
+	// Scopes maps a procedure to the scope an API key needs to call it.
+	// Principals without scopes (user tokens) are not restricted by this map.
+	Scopes map[string]string
+
+	// TenantAdmin lists admin-only procedures that tenant admins may also call
+	// for their own organization.
+	TenantAdmin []string
+
+	// Public lists procedures whose request carries its own credential (such as
+	// a signed invitation token). A bearer token is optional; a valid one still
+	// identifies the caller.
+	Public []string
+}
+
+func (p Policy) adminOnly(procedure string) bool {
+	for _, prefix := range p.AdminPrefixes {
+		if strings.HasPrefix(procedure, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewInterceptor authenticates every unary RPC with a bearer token and enforces
+// tenant isolation: a request whose organization_id differs from the caller's
+// organization is rejected unless the caller is a platform admin.
+func NewInterceptor(authn Authenticator, policy Policy) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			procedure := req.Spec().Procedure
+			token, ok := bearerToken(req.Header().Get("Authorization"))
+			if slices.Contains(policy.Public, procedure) {
+				if ok {
+					if principal, err := authn.Authenticate(ctx, token); err == nil {
+						ctx = WithPrincipal(ctx, principal)
+					}
+				}
+				return next(ctx, req)
+			}
+			if !ok {
+				return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("missing bearer token"))
+			}
+			principal, err := authn.Authenticate(ctx, token)
+			if err != nil {
+				return nil, connect.NewError(connect.CodeUnauthenticated, err)
+			}
+
+			tenantAdmin := principal.Role == RoleTenantAdmin && slices.Contains(policy.TenantAdmin, procedure)
+			if policy.adminOnly(procedure) && !principal.IsPlatformAdmin() && !tenantAdmin {
+				return nil, connect.NewError(connect.CodePermissionDenied, errors.New("platform admin role required"))
+			}
+			if scope, ok := policy.Scopes[procedure]; ok && principal.Scopes != nil && !slices.Contains(principal.Scopes, scope) {
+				return nil, connect.NewError(connect.CodePermissionDenied, errors.New("api key lacks scope "+scope))
+			}
+			if !principal.IsPlatformAdmin() {
+				if orgID := organizationID(req.Any()); orgID != "" && orgID != principal.OrganizationID {
+					return nil, connect.NewError(connect.CodePermissionDenied, errors.New("organization_id does not match caller"))
+				}
+			}
+
+			return next(WithPrincipal(ctx, principal), req)
+		}
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(prefix):]), true
+}
+
+// organizationID reads a top-level organization_id string field from msg, if present
+func organizationID(msg any) string {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return ""
+	}
+	r := m.ProtoReflect()
+	fd := r.Descriptor().Fields().ByName("organization_id")
+	if fd == nil || fd.Kind() != protoreflect.StringKind || fd.IsList() {
+		return ""
+	}
+	return r.Get(fd).String()
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InvitationPrefix marks invitation tokens.
+const InvitationPrefix = "mcpi_"
+
+// ErrInvalidInvitationToken is returned for malformed, forged or expired invitation tokens.
+var ErrInvalidInvitationToken = errors.New("invalid invitation token")
+
+// InvitationSigner signs and verifies invitation tokens
+// ("mcpi_<id>.<expiry unix>.<HMAC-SHA256>"). Tokens are self-contained, so only
+// the invitation record is stored, never the token.
+type InvitationSigner struct {
+	key []byte
+}
+
+// NewInvitationSigner creates a signer. The key should be at least 32 random bytes.
+func NewInvitationSigner(key []byte) (*InvitationSigner, error) {
+	if len(key) < 32 {
+		return nil, errors.New("invitation signing key must be at least 32 bytes")
+	}
+	return &InvitationSigner{key: key}, nil
+}
+
+// NewInvitationID generates a random invitation ID.
+func NewInvitationID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate invitation id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Sign returns the token for an invitation that expires at expiresAt.
+func (s *InvitationSigner) Sign(id string, expiresAt time.Time) string {
+	payload := id + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return InvitationPrefix + payload + "." + s.mac(payload)
+}
+
+// Verify checks a token's signature and expiry and returns the invitation ID.
+func (s *InvitationSigner) Verify(token string, now time.Time) (string, error) {
+	rest, ok := strings.CutPrefix(token, InvitationPrefix)
+	if !ok {
+		return "", ErrInvalidInvitationToken
+	}
+	payload, sig, ok := cutLast(rest, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.mac(payload))) {
+		return "", ErrInvalidInvitationToken
+	}
+	id, exp, ok := strings.Cut(payload, ".")
+	expiry, err := strconv.ParseInt(exp, 10, 64)
+	if !ok || id == "" || err != nil {
+		return "", ErrInvalidInvitationToken
+	}
+	if !now.Before(time.Unix(expiry, 0)) {
+		return "", fmt.Errorf("%w: expired", ErrInvalidInvitationToken)
+	}
+	return id, nil
+}
+
+func (s *InvitationSigner) mac(payload string) string {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInvitationSigner(t *testing.T) {
+	signer, err := NewInvitationSigner(bytes.Repeat([]byte("k"), 32))
+	if err != nil {
+		t.Fatalf("NewInvitationSigner: %v", err)
+	}
+	other, err := NewInvitationSigner(bytes.Repeat([]byte("o"), 32))
+	if err != nil {
+		t.Fatalf("NewInvitationSigner: %v", err)
+	}
+
+	now := time.Unix(1_700_000_000, 0)
+	valid := signer.Sign("inv1", now.Add(time.Hour))
+
+	tests := []struct {
+		name    string
+		token   string
+		now     time.Time
+		wantID  string
+		wantErr bool
+	}{
+		{name: "valid", token: valid, now: now, wantID: "inv1"},
+		{name: "expired", token: valid, now: now.Add(time.Hour), wantErr: true},
+		{name: "missing prefix", token: strings.TrimPrefix(valid, InvitationPrefix), now: now, wantErr: true},
+		{name: "other key", token: other.Sign("inv1", now.Add(time.Hour)), now: now, wantErr: true},
+		{name: "changed id", token: strings.Replace(valid, "inv1", "inv2", 1), now: now, wantErr: true},
+		{name: "extended expiry", token: strings.Replace(valid, ".1700003600.", ".1800000000.", 1), now: now, wantErr: true},
+		{name: "no signature", token: InvitationPrefix + "inv1.1700003600", now: now, wantErr: true},
+		{name: "empty", token: "", now: now, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := signer.Verify(tt.token, tt.now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInvitationToken) {
+					t.Fatalf("Verify(%q) error = %v, want ErrInvalidInvitationToken", tt.token, err)
+				}
+				return
+			}
+			if err != nil || id != tt.wantID {
+				t.Fatalf("Verify(%q) = %q, %v, want %q", tt.token, id, err, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestNewInvitationSignerShortKey(t *testing.T) {
+	if _, err := NewInvitationSigner(make([]byte, 31)); err == nil {
+		t.Fatal("NewInvitationSigner accepted a 31-byte key")
+	}
+}
@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Roles a caller can hold. Tenant roles match the personas in the platform README.
+const (
+	RolePlatformAdmin = "platform-admin"
+	RoleTenantAdmin   = "admin"
+	RoleTenantUser    = "user"
+	RoleTenantViewer  = "viewer"
+)
+
+// Verification modes.
+const (
+	ModeJWKS   = "jwks"   // RS/ES-signed tokens verified against the issuer's JWKS
+	ModeStatic = "static" // HS256 tokens signed with a shared secret, for local use
+)
+
+// Config controls how bearer tokens are verified and mapped to principals.
+type Config struct {
+	Mode         string // ModeJWKS or ModeStatic
+	Issuer       string // Expected "iss"; also used to derive the JWKS URL
+	Audience     string // Expected "aud" (optional)
+	JWKSURL      string // Defaults to <Issuer>/.well-known/jwks.json
+	StaticSecret string // HS256 secret for ModeStatic
+	OrgClaim     string // Claim holding the caller's organization ID
+	RoleClaim    string // Claim holding the caller's role (string or list)
+	AdminRole    string // Role value that grants platform-admin
+}
+
+// ConfigFromEnv reads verifier configuration from environment variables:
+//
+//	AUTH_MODE           - "jwks" (default) or "static"
+//	AUTH_ISSUER         - expected token issuer
+//	AUTH_AUDIENCE       - expected token audience (optional)
+//	AUTH_JWKS_URL       - JWKS endpoint (optional, derived from AUTH_ISSUER)
+//	AUTH_STATIC_SECRET  - HS256 signing secret for static mode
+//	AUTH_ORG_CLAIM      - organization claim name (optional, default "org_id")
+//	AUTH_ROLE_CLAIM     - role claim name (optional, default "role")
+//	AUTH_ADMIN_ROLE     - role value for platform admins (optional, default "platform-admin")
+func ConfigFromEnv() Config {
+	return Config{
+		Mode:         envOrDefault("AUTH_MODE", ModeJWKS),
+		Issuer:       os.Getenv("AUTH_ISSUER"),
+		Audience:     os.Getenv("AUTH_AUDIENCE"),
+		JWKSURL:      os.Getenv("AUTH_JWKS_URL"),
+		StaticSecret: os.Getenv("AUTH_STATIC_SECRET"),
+		OrgClaim:     envOrDefault("AUTH_ORG_CLAIM", "org_id"),
+		RoleClaim:    envOrDefault("AUTH_ROLE_CLAIM", "role"),
+		AdminRole:    envOrDefault("AUTH_ADMIN_ROLE", RolePlatformAdmin),
+	}
+}
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Subject        string
+	OrganizationID string
+	Role           string
+	Scopes         []string // nil for user tokens; API keys are limited to these scopes
+}
+
+// IsPlatformAdmin reports whether the principal may act on any organization.
+func (p *Principal) IsPlatformAdmin() bool {
+	return p.Role == RolePlatformAdmin
+}
+
+// Verifier validates bearer tokens and maps their claims to a Principal.
+type Verifier struct {
+	cfg     Config
+	keyFunc jwt.Keyfunc
+	parser  *jwt.Parser
+}
+
+// NewVerifier creates a verifier. In JWKS mode the key set is fetched from the
+// issuer and refreshed in the background until ctx is cancelled.
+func NewVerifier(ctx context.Context, cfg Config) (*Verifier, error) {
+	opts := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	if cfg.OrgClaim == "" {
+		cfg.OrgClaim = "org_id"
+	}
+	if cfg.RoleClaim == "" {
+		cfg.RoleClaim = "role"
+	}
+	if cfg.AdminRole == "" {
+		cfg.AdminRole = RolePlatformAdmin
+	}
+
+	v := &Verifier{cfg: cfg}
+
+	switch cfg.Mode {
+	case ModeStatic:
+		if cfg.StaticSecret == "" {
+			return nil, fmt.Errorf("static auth mode requires a secret")
+		}
+		secret := []byte(cfg.StaticSecret)
+		v.keyFunc = func(*jwt.Token) (interface{}, error) { return secret, nil }
+		opts = append(opts, jwt.WithValidMethods([]string{"HS256"}))
+	case ModeJWKS, "":
+		url := cfg.JWKSURL
+		if url == "" {
+			if cfg.Issuer == "" {
+				return nil, fmt.Errorf("jwks auth mode requires an issuer or JWKS URL")
+			}
+			url = strings.TrimSuffix(cfg.Issuer, "/") + "/.well-known/jwks.json"
+		}
+		k, err := keyfunc.NewDefaultCtx(ctx, []string{url})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWKS from %s: %w", url, err)
+		}
+		v.keyFunc = k.Keyfunc
+		opts = append(opts, jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "PS256"}))
+	default:
+		return nil, fmt.Errorf("unknown auth mode %q", cfg.Mode)
+	}
+
+	v.parser = jwt.NewParser(opts...)
+	return v, nil
+}
+
+// Authenticate implements Authenticator for JWT bearer tokens.
+func (v *Verifier) Authenticate(_ context.Context, token string) (*Principal, error) {
+	return v.Verify(token)
+}
+
+// Verify validates a raw token and returns the principal it represents.
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyFunc); err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("token has no subject")
+	}
+
+	p := &Principal{Subject: subject}
+	if org, ok := claims[v.cfg.OrgClaim].(string); ok {
+		p.OrganizationID = org
+	}
+	p.Role = v.role(claims[v.cfg.RoleClaim])
+
+	if !p.IsPlatformAdmin() && p.OrganizationID == "" {
+		return nil, fmt.Errorf("token has no %s claim", v.cfg.OrgClaim)
+	}
+
+	return p, nil
+}
+
+// role picks the most privileged known role from a string or list claim.
+func (v *Verifier) role(claim interface{}) string {
+	var values []string
+	switch c := claim.(type) {
+	case string:
+		values = []string{c}
+	case []interface{}:
+		for _, item := range c {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	rank := map[string]int{
+		v.cfg.AdminRole:  4,
+		RoleTenantAdmin:  3,
+		RoleTenantUser:   2,
+		RoleTenantViewer: 1,
+	}
+	best := ""
+	for _, r := range values {
+		if rank[r] > rank[best] {
+			best = r
+		}
+	}
+	if best == v.cfg.AdminRole {
+		return RolePlatformAdmin
+	}
+	return best
+}
+
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,85 @@
+package billing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Provider is a billing system that tenants are invoiced through.
+// StripeClient implements it against the Stripe API (or FakeServer locally).
+type Provider interface {
+	// CreateCustomer creates the customer invoices are addressed to.
+	CreateCustomer(ctx context.Context, orgID string) (customerID string, err error)
+
+	// CreateSubscription subscribes a customer to a plan tier and, when a
+	// metered price is configured, to per-job usage billing.
+	CreateSubscription(ctx context.Context, customerID, orgID, plan string) (*Subscription, error)
+
+	// ChangePlan moves the plan item of a subscription to another plan tier.
+	ChangePlan(ctx context.Context, sub *Subscription, plan string) error
+
+	// CancelSubscription ends a subscription immediately.
+	CancelSubscription(ctx context.Context, subscriptionID string) error
+
+	// ReportUsage adds metered usage to a subscription item. Reports with the
+	// same IdempotencyKey are only counted once.
+	ReportUsage(ctx context.Context, usage UsageRecord) error
+}
+
+// Subscription identifies a subscription and its items in the provider.
+type Subscription struct {
+	ID            string
+	PlanItemID    string // Item billed at the plan tier's price
+	MeteredItemID string // Item job usage is reported against; empty without a metered price
+	Status        string
+}
+
+// UsageRecord is a quantity of metered usage at a point in time.
+type UsageRecord struct {
+	SubscriptionItemID string
+	Quantity           int64
+	Timestamp          time.Time
+	IdempotencyKey     string
+}
+
+// Prices maps plan tiers to provider price IDs.
+type Prices struct {
+	Plans  map[string]string // Plan tier name (e.g. "PLAN_TIER_PRO") to price ID
+	JobRun string            // Metered price charged per completed job (optional)
+}
+
+// PricesFromEnv reads price IDs from environment variables:
+//
+//	BILLING_PRICE_FREE, BILLING_PRICE_STARTER,
+//	BILLING_PRICE_PRO, BILLING_PRICE_ENTERPRISE - flat price of each plan tier
+//	BILLING_PRICE_JOB_RUN                       - metered price per completed job (optional)
+func PricesFromEnv() Prices {
+	prices := Prices{Plans: map[string]string{}, JobRun: os.Getenv("BILLING_PRICE_JOB_RUN")}
+	for _, tier := range []string{"FREE", "STARTER", "PRO", "ENTERPRISE"} {
+		if id := os.Getenv("BILLING_PRICE_" + tier); id != "" {
+			prices.Plans["PLAN_TIER_"+tier] = id
+		}
+	}
+	return prices
+}
+
+// PlanPrice returns the price ID of a plan tier.
+func (p Prices) PlanPrice(plan string) (string, error) {
+	id, ok := p.Plans[plan]
+	if !ok {
+		return "", fmt.Errorf("no billing price configured for plan %s", plan)
+	}
+	return id, nil
+}
+
+// PlanForPrice returns the plan tier billed at a price ID.
+func (p Prices) PlanForPrice(priceID string) (string, bool) {
+	for plan, id := range p.Plans {
+		if id == priceID {
+			return plan, true
+		}
+	}
+	return "", false
+}
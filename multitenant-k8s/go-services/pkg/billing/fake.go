@@ -0,0 +1,275 @@
+package billing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeServer is an in-memory stand-in for the subset of the Stripe API that
+// StripeClient uses, for local development. Subscription changes are sent as
+// signed webhooks to the configured URL, and the /fake endpoints let a
+// developer inspect usage and simulate payment failures or plan changes:
+//
+//	GET  /fake/usage                 - reported quantity per subscription item
+//	POST /fake/subscriptions/{id}    - form fields status and/or price; fires customer.subscription.updated
+type FakeServer struct {
+	webhookURL string
+	secret     []byte
+	client     *http.Client
+	mux        *http.ServeMux
+
+	mu            sync.Mutex
+	nextID        int
+	subscriptions map[string]*fakeSubscription
+	usage         map[string]int64
+	idempotency   map[string]bool
+}
+
+type fakeSubscription struct {
+	ID       string            `json:"id"`
+	Object   string            `json:"object"`
+	Customer string            `json:"customer"`
+	Status   string            `json:"status"`
+	Metadata map[string]string `json:"metadata"`
+	Items    struct {
+		Data []stripeItem `json:"data"`
+	} `json:"items"`
+}
+
+// NewFakeServer creates a fake billing API. Webhooks are signed with secret and
+// posted to webhookURL; an empty webhookURL disables them.
+func NewFakeServer(webhookURL, secret string) *FakeServer {
+	s := &FakeServer{
+		webhookURL:    webhookURL,
+		secret:        []byte(secret),
+		client:        &http.Client{Timeout: 10 * time.Second},
+		mux:           http.NewServeMux(),
+		subscriptions: map[string]*fakeSubscription{},
+		usage:         map[string]int64{},
+		idempotency:   map[string]bool{},
+	}
+	s.mux.HandleFunc("POST /v1/customers", s.createCustomer)
+	s.mux.HandleFunc("POST /v1/subscriptions", s.createSubscription)
+	s.mux.HandleFunc("POST /v1/subscriptions/{id}", s.updateSubscription)
+	s.mux.HandleFunc("DELETE /v1/subscriptions/{id}", s.cancelSubscription)
+	s.mux.HandleFunc("POST /v1/subscription_items/{id}/usage_records", s.reportUsage)
+	s.mux.HandleFunc("GET /fake/usage", s.listUsage)
+	s.mux.HandleFunc("POST /fake/subscriptions/{id}", s.simulate)
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *FakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") == "" && !strings.HasPrefix(r.URL.Path, "/fake/") {
+		writeFakeError(w, http.StatusUnauthorized, "missing api key")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeFakeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *FakeServer) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s_fake%06d", prefix, s.nextID)
+}
+
+func (s *FakeServer) createCustomer(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	id := s.newID("cus")
+	s.mu.Unlock()
+	writeFakeJSON(w, map[string]string{"id": id, "object": "customer", "name": r.PostForm.Get("name")})
+}
+
+func (s *FakeServer) createSubscription(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	sub := &fakeSubscription{
+		ID:       s.newID("sub"),
+		Object:   "subscription",
+		Customer: r.PostForm.Get("customer"),
+		Status:   "active",
+		Metadata: map[string]string{"organization_id": r.PostForm.Get("metadata[organization_id]")},
+	}
+	for i := 0; ; i++ {
+		price := r.PostForm.Get(fmt.Sprintf("items[%d][price]", i))
+		if price == "" {
+			break
+		}
+		item := stripeItem{ID: s.newID("si")}
+		item.Price.ID = price
+		sub.Items.Data = append(sub.Items.Data, item)
+	}
+	s.subscriptions[sub.ID] = sub
+	event := s.snapshot(sub)
+	s.mu.Unlock()
+
+	writeFakeJSON(w, event)
+	s.sendWebhook("customer.subscription.created", event)
+}
+
+func (s *FakeServer) updateSubscription(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[r.PathValue("id")]
+	if !ok {
+		s.mu.Unlock()
+		writeFakeError(w, http.StatusNotFound, "no such subscription")
+		return
+	}
+	for i := 0; ; i++ {
+		id := r.PostForm.Get(fmt.Sprintf("items[%d][id]", i))
+		if id == "" {
+			break
+		}
+		for j := range sub.Items.Data {
+			if sub.Items.Data[j].ID == id {
+				sub.Items.Data[j].Price.ID = r.PostForm.Get(fmt.Sprintf("items[%d][price]", i))
+			}
+		}
+	}
+	event := s.snapshot(sub)
+	s.mu.Unlock()
+
+	writeFakeJSON(w, event)
+	s.sendWebhook("customer.subscription.updated", event)
+}
+
+func (s *FakeServer) cancelSubscription(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[r.PathValue("id")]
+	if !ok {
+		s.mu.Unlock()
+		writeFakeError(w, http.StatusNotFound, "no such subscription")
+		return
+	}
+	sub.Status = "canceled"
+	event := s.snapshot(sub)
+	s.mu.Unlock()
+
+	writeFakeJSON(w, event)
+	s.sendWebhook("customer.subscription.deleted", event)
+}
+
+func (s *FakeServer) reportUsage(w http.ResponseWriter, r *http.Request) {
+	quantity, err := strconv.ParseInt(r.PostForm.Get("quantity"), 10, 64)
+	if err != nil {
+		writeFakeError(w, http.StatusBadRequest, "invalid quantity")
+		return
+	}
+
+	s.mu.Lock()
+	key := r.Header.Get("Idempotency-Key")
+	if key == "" || !s.idempotency[key] {
+		s.usage[r.PathValue("id")] += quantity
+		s.idempotency[key] = key != ""
+	}
+	s.mu.Unlock()
+
+	writeFakeJSON(w, map[string]any{"object": "usage_record", "subscription_item": r.PathValue("id"), "quantity": quantity})
+}
+
+func (s *FakeServer) listUsage(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeFakeJSON(w, s.usage)
+}
+
+// simulate changes a subscription as if it happened in the provider, e.g. a
+// failed payment (status=past_due) or a customer upgrading (price=<id>).
+func (s *FakeServer) simulate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	sub, ok := s.subscriptions[r.PathValue("id")]
+	if !ok {
+		s.mu.Unlock()
+		writeFakeError(w, http.StatusNotFound, "no such subscription")
+		return
+	}
+	if status := r.PostForm.Get("status"); status != "" {
+		sub.Status = status
+	}
+	if price := r.PostForm.Get("price"); price != "" && len(sub.Items.Data) > 0 {
+		sub.Items.Data[0].Price.ID = price
+	}
+	event := s.snapshot(sub)
+	s.mu.Unlock()
+
+	eventType := "customer.subscription.updated"
+	if event.Status == "canceled" {
+		eventType = "customer.subscription.deleted"
+	}
+	writeFakeJSON(w, event)
+	s.sendWebhook(eventType, event)
+}
+
+// snapshot copies a subscription so it can be encoded without holding the lock.
+func (s *FakeServer) snapshot(sub *fakeSubscription) fakeSubscription {
+	c := *sub
+	c.Items.Data = append([]stripeItem(nil), sub.Items.Data...)
+	return c
+}
+
+// sendWebhook posts a signed event in the background, like the provider does,
+// retrying a few times while the receiver fails.
+func (s *FakeServer) sendWebhook(eventType string, sub fakeSubscription) {
+	if s.webhookURL == "" {
+		return
+	}
+	s.mu.Lock()
+	id := s.newID("evt")
+	s.mu.Unlock()
+
+	payload, err := json.Marshal(map[string]any{
+		"id":      id,
+		"object":  "event",
+		"type":    eventType,
+		"created": time.Now().Unix(),
+		"data":    map[string]any{"object": sub},
+	})
+	if err != nil {
+		log.Printf("fake billing: failed to encode event: %v", err)
+		return
+	}
+
+	go func() {
+		for attempt := 0; attempt < 5; attempt++ {
+			if attempt > 0 {
+				time.Sleep(time.Duration(attempt) * time.Second)
+			}
+			req, err := http.NewRequest(http.MethodPost, s.webhookURL, bytes.NewReader(payload))
+			if err != nil {
+				log.Printf("fake billing: failed to build webhook: %v", err)
+				return
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(SignatureHeader, Sign(s.secret, payload, time.Now()))
+			resp, err := s.client.Do(req)
+			if err == nil {
+				resp.Body.Close()
+				if resp.StatusCode < 300 {
+					return
+				}
+				err = fmt.Errorf("webhook returned %s", resp.Status)
+			}
+			log.Printf("fake billing: %s %s delivery failed: %v", eventType, id, err)
+		}
+	}()
+}
+
+func writeFakeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeFakeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"error": map[string]string{"message": msg}})
+}
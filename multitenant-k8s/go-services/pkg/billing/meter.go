@@ -0,0 +1,140 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/periodic"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// meteredAtAnnotation marks jobs whose usage has been reported, so each
+// completed job is billed once. Jobs are garbage collected with the mark.
+const meteredAtAnnotation = "account-provisioning/metered-at"
+
+// Meter reports one unit of metered usage for every job that completes in a
+// tenant namespace of one cluster.
+type Meter struct {
+	k8sClient kubernetes.Interface
+	store     *storage.BillingStore
+	provider  Provider
+	interval  time.Duration
+}
+
+// NewMeter creates a job meter that scans for completed jobs every interval.
+func NewMeter(k8sClient kubernetes.Interface, store *storage.BillingStore, provider Provider, interval time.Duration) (*Meter, error) {
+	if k8sClient == nil {
+		return nil, fmt.Errorf("k8s client must not be nil")
+	}
+	if store == nil {
+		return nil, fmt.Errorf("billing store must not be nil")
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("billing provider must not be nil")
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &Meter{
+		k8sClient: k8sClient,
+		store:     store,
+		provider:  provider,
+		interval:  interval,
+	}, nil
+}
+
+// Run meters completed jobs until ctx is cancelled.
+func (m *Meter) Run(ctx context.Context) {
+	periodic.Every(ctx, m.interval, "job metering", m.Meter)
+}
+
+// Meter reports usage for every completed job not yet metered.
+func (m *Meter) Meter(ctx context.Context) error {
+	namespaces, err := m.k8sClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: "managed-by=account-provisioning-service",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list tenant namespaces: %w", err)
+	}
+
+	for _, ns := range namespaces.Items {
+		orgID := ns.Labels["tenant-id"]
+		if orgID == "" {
+			continue
+		}
+
+		record, err := m.store.Get(ctx, orgID)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read billing record for %s: %w", orgID, err)
+		}
+		if record.MeteredItemID == "" {
+			continue
+		}
+
+		// One tenant's failures should not block metering for the others.
+		if err := m.meterNamespace(ctx, ns.Name, record); err != nil {
+			log.Printf("billing: skipping namespace %s: %v", ns.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Meter) meterNamespace(ctx context.Context, namespace string, record *storage.BillingRecord) error {
+	jobs, err := m.k8sClient.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if _, done := job.Annotations[meteredAtAnnotation]; done || !jobSucceeded(job) {
+			continue
+		}
+
+		completed := job.CreationTimestamp.Time
+		if job.Status.CompletionTime != nil {
+			completed = job.Status.CompletionTime.Time
+		}
+		// The job UID keeps a retry after a failed annotation from double billing.
+		err := m.provider.ReportUsage(ctx, UsageRecord{
+			SubscriptionItemID: record.MeteredItemID,
+			Quantity:           1,
+			Timestamp:          completed,
+			IdempotencyKey:     "job-" + string(job.UID),
+		})
+		if err != nil {
+			return fmt.Errorf("job %s: %w", job.Name, err)
+		}
+
+		patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, meteredAtAnnotation, time.Now().UTC().Format(time.RFC3339))
+		_, err = m.k8sClient.BatchV1().Jobs(namespace).Patch(ctx, job.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to mark job %s metered: %w", job.Name, err)
+		}
+	}
+	return nil
+}
+
+// jobSucceeded reports whether a job ran to completion; failed jobs are not billed.
+func jobSucceeded(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobComplete && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
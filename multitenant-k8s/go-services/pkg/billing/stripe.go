@@ -0,0 +1,187 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStripeURL is the Stripe API endpoint.
+const DefaultStripeURL = "https://api.stripe.com"
+
+// StripeClient is a Provider that speaks the Stripe REST API. Any service
+// implementing the same subset (such as FakeServer) can stand in for Stripe.
+type StripeClient struct {
+	baseURL string
+	apiKey  string
+	prices  Prices
+	http    *http.Client
+}
+
+// NewStripeClient creates a client for the API at baseURL (DefaultStripeURL if
+// empty) that authenticates with a secret API key.
+func NewStripeClient(baseURL, apiKey string, prices Prices) (*StripeClient, error) {
+	if apiKey == "" {
+		return nil, errors.New("billing api key must not be empty")
+	}
+	if len(prices.Plans) == 0 {
+		return nil, errors.New("at least one plan price must be configured")
+	}
+	if baseURL == "" {
+		baseURL = DefaultStripeURL
+	}
+	return &StripeClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		prices:  prices,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+type stripeObject struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Items  struct {
+		Data []stripeItem `json:"data"`
+	} `json:"items"`
+}
+
+type stripeItem struct {
+	ID    string `json:"id"`
+	Price struct {
+		ID string `json:"id"`
+	} `json:"price"`
+}
+
+// CreateCustomer implements Provider.
+func (c *StripeClient) CreateCustomer(ctx context.Context, orgID string) (string, error) {
+	form := url.Values{
+		"name":                      {orgID},
+		"metadata[organization_id]": {orgID},
+	}
+	var customer stripeObject
+	if err := c.do(ctx, http.MethodPost, "/v1/customers", form, "", &customer); err != nil {
+		return "", fmt.Errorf("failed to create customer: %w", err)
+	}
+	return customer.ID, nil
+}
+
+// CreateSubscription implements Provider.
+func (c *StripeClient) CreateSubscription(ctx context.Context, customerID, orgID, plan string) (*Subscription, error) {
+	planPrice, err := c.prices.PlanPrice(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"customer":                  {customerID},
+		"items[0][price]":           {planPrice},
+		"metadata[organization_id]": {orgID},
+	}
+	if c.prices.JobRun != "" {
+		form.Set("items[1][price]", c.prices.JobRun)
+	}
+
+	var sub stripeObject
+	if err := c.do(ctx, http.MethodPost, "/v1/subscriptions", form, "", &sub); err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	result := &Subscription{ID: sub.ID, Status: sub.Status}
+	for _, item := range sub.Items.Data {
+		switch item.Price.ID {
+		case planPrice:
+			result.PlanItemID = item.ID
+		case c.prices.JobRun:
+			result.MeteredItemID = item.ID
+		}
+	}
+	return result, nil
+}
+
+// ChangePlan implements Provider. The change is prorated.
+func (c *StripeClient) ChangePlan(ctx context.Context, sub *Subscription, plan string) error {
+	planPrice, err := c.prices.PlanPrice(plan)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{
+		"items[0][id]":       {sub.PlanItemID},
+		"items[0][price]":    {planPrice},
+		"proration_behavior": {"create_prorations"},
+	}
+	if err := c.do(ctx, http.MethodPost, "/v1/subscriptions/"+url.PathEscape(sub.ID), form, "", nil); err != nil {
+		return fmt.Errorf("failed to change plan of subscription %s: %w", sub.ID, err)
+	}
+	return nil
+}
+
+// CancelSubscription implements Provider.
+func (c *StripeClient) CancelSubscription(ctx context.Context, subscriptionID string) error {
+	if err := c.do(ctx, http.MethodDelete, "/v1/subscriptions/"+url.PathEscape(subscriptionID), nil, "", nil); err != nil {
+		return fmt.Errorf("failed to cancel subscription %s: %w", subscriptionID, err)
+	}
+	return nil
+}
+
+// ReportUsage implements Provider.
+func (c *StripeClient) ReportUsage(ctx context.Context, usage UsageRecord) error {
+	form := url.Values{
+		"quantity":  {strconv.FormatInt(usage.Quantity, 10)},
+		"timestamp": {strconv.FormatInt(usage.Timestamp.Unix(), 10)},
+		"action":    {"increment"},
+	}
+	path := "/v1/subscription_items/" + url.PathEscape(usage.SubscriptionItemID) + "/usage_records"
+	if err := c.do(ctx, http.MethodPost, path, form, usage.IdempotencyKey, nil); err != nil {
+		return fmt.Errorf("failed to report usage: %w", err)
+	}
+	return nil
+}
+
+func (c *StripeClient) do(ctx context.Context, method, path string, form url.Values, idempotencyKey string, out any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(msg, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, apiErr.Error.Message)
+		}
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,177 @@
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the webhook signature ("t=<unix>,v1=<hex HMAC-SHA256>").
+const SignatureHeader = "Stripe-Signature"
+
+// signatureTolerance bounds how old a signed webhook may be, limiting replays.
+const signatureTolerance = 5 * time.Minute
+
+// maxWebhookBytes bounds the size of a webhook body.
+const maxWebhookBytes = 1 << 20
+
+// ErrInvalidSignature is returned for webhooks with a missing, forged or stale signature.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// SubscriptionChange is the state of a subscription reported by a webhook.
+type SubscriptionChange struct {
+	OrganizationID string
+	SubscriptionID string
+	Plan           string // Plan tier of the subscription's plan price; empty if none is configured
+	Status         string // Provider status, e.g. "active", "past_due", "canceled"
+	OccurredAt     time.Time
+}
+
+// Accounts applies subscription changes to tenant accounts.
+// accountservice.Service implements this interface.
+type Accounts interface {
+	ApplySubscriptionChange(ctx context.Context, change SubscriptionChange) error
+}
+
+// WebhookHandler receives subscription webhooks from the billing provider and
+// turns plan changes and payment state into account updates and suspensions.
+type WebhookHandler struct {
+	secret   []byte
+	prices   Prices
+	accounts Accounts
+}
+
+// NewWebhookHandler creates a handler that verifies webhooks with the endpoint's signing secret.
+func NewWebhookHandler(secret string, prices Prices, accounts Accounts) (*WebhookHandler, error) {
+	if secret == "" {
+		return nil, errors.New("webhook secret must not be empty")
+	}
+	if accounts == nil {
+		return nil, errors.New("accounts must not be nil")
+	}
+	return &WebhookHandler{secret: []byte(secret), prices: prices, accounts: accounts}, nil
+}
+
+type webhookEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object struct {
+			stripeObject
+			Metadata map[string]string `json:"metadata"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// ServeHTTP implements http.Handler. Failures to apply an event return 500 so
+// the provider retries the delivery.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBytes+1))
+	if err != nil || len(body) > maxWebhookBytes {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+	if err := VerifySignature(h.secret, r.Header.Get(SignatureHeader), body, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var event webhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+
+	sub := event.Data.Object
+	change := SubscriptionChange{
+		OrganizationID: sub.Metadata["organization_id"],
+		SubscriptionID: sub.ID,
+		Status:         sub.Status,
+		OccurredAt:     time.Unix(event.Created, 0).UTC(),
+	}
+	switch event.Type {
+	case "customer.subscription.created", "customer.subscription.updated":
+	case "customer.subscription.deleted":
+		change.Status = "canceled"
+	default:
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if change.OrganizationID == "" {
+		log.Printf("billing webhook: ignoring event %s for subscription %s without organization_id", event.ID, sub.ID)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	for _, item := range sub.Items.Data {
+		if plan, ok := h.prices.PlanForPrice(item.Price.ID); ok {
+			change.Plan = plan
+			break
+		}
+	}
+
+	if err := h.accounts.ApplySubscriptionChange(r.Context(), change); err != nil {
+		log.Printf("billing webhook: failed to apply event %s for %s: %v", event.ID, change.OrganizationID, err)
+		http.Error(w, "failed to apply event", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Sign returns the signature header value for a payload sent at t.
+func Sign(secret []byte, payload []byte, t time.Time) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + signature(secret, ts, payload)
+}
+
+// VerifySignature checks a signature header against the payload. Any of the
+// v1 signatures may match, so the signing secret can be rolled.
+func VerifySignature(secret []byte, header string, payload []byte, now time.Time) error {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sigs = append(sigs, value)
+		}
+	}
+	sent, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(sent, 0)); age > signatureTolerance || age < -signatureTolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+
+	expected := signature(secret, ts, payload)
+	for _, sig := range sigs {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func signature(secret []byte, ts string, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,48 @@
+package billing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("whsec_test")
+	payload := []byte(`{"type":"invoice.payment_failed"}`)
+	sent := time.Unix(1_700_000_000, 0)
+	valid := Sign(secret, payload, sent)
+	_, sig, _ := strings.Cut(valid, ",v1=")
+
+	tests := []struct {
+		name    string
+		header  string
+		payload []byte
+		now     time.Time
+		wantErr bool
+	}{
+		{name: "valid", header: valid, payload: payload, now: sent},
+		{name: "within tolerance", header: valid, payload: payload, now: sent.Add(signatureTolerance)},
+		{name: "rolled secret", header: valid + ",v1=" + strings.Repeat("0", 64), payload: payload, now: sent},
+		{name: "stale", header: valid, payload: payload, now: sent.Add(signatureTolerance + time.Second), wantErr: true},
+		{name: "from the future", header: valid, payload: payload, now: sent.Add(-signatureTolerance - time.Second), wantErr: true},
+		{name: "changed payload", header: valid, payload: []byte(`{"type":"invoice.paid"}`), now: sent, wantErr: true},
+		{name: "other secret", header: Sign([]byte("whsec_other"), payload, sent), payload: payload, now: sent, wantErr: true},
+		{name: "changed timestamp", header: "t=1700000001,v1=" + sig, payload: payload, now: sent, wantErr: true},
+		{name: "no timestamp", header: "v1=" + sig, payload: payload, now: sent, wantErr: true},
+		{name: "no signature", header: "t=1700000000", payload: payload, now: sent, wantErr: true},
+		{name: "empty", header: "", payload: payload, now: sent, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(secret, tt.header, tt.payload, tt.now)
+			if tt.wantErr && !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("VerifySignature(%q) error = %v, want ErrInvalidSignature", tt.header, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("VerifySignature(%q) error = %v", tt.header, err)
+			}
+		})
+	}
+}
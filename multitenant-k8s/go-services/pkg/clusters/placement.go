@@ -0,0 +1,122 @@
+package clusters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrNoCapacity is returned when no eligible cluster can fit a new tenant.
+var ErrNoCapacity = errors.New("no cluster has capacity for the tenant")
+
+// Capacity is a cluster's shared-node allocatable resources and how much of it
+// is already promised to tenants through their ResourceQuota requests.
+type Capacity struct {
+	Cluster           string
+	AllocatableCPU    resource.Quantity
+	AllocatableMemory resource.Quantity
+	CommittedCPU      resource.Quantity
+	CommittedMemory   resource.Quantity
+	Tenants           int
+}
+
+// FreeCPU returns allocatable CPU not yet committed to tenants.
+func (c Capacity) FreeCPU() resource.Quantity {
+	free := c.AllocatableCPU.DeepCopy()
+	free.Sub(c.CommittedCPU)
+	return free
+}
+
+// FreeMemory returns allocatable memory not yet committed to tenants.
+func (c Capacity) FreeMemory() resource.Quantity {
+	free := c.AllocatableMemory.DeepCopy()
+	free.Sub(c.CommittedMemory)
+	return free
+}
+
+// Request describes a tenant to place.
+type Request struct {
+	Tier   string            // Plan tier name, e.g. "PLAN_TIER_PRO"
+	Region string            // Required region (optional)
+	CPU    resource.Quantity // Quota requests.cpu for the tier
+	Memory resource.Quantity // Quota requests.memory for the tier
+}
+
+// Capacity measures one cluster. Nodes dedicated to a tenant (labelled
+// tenant-id) and unschedulable nodes are not counted as shared capacity.
+func (r *Registry) Capacity(ctx context.Context, c *Cluster) (Capacity, error) {
+	capacity := Capacity{Cluster: c.Name}
+
+	nodes, err := c.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: "!tenant-id"})
+	if err != nil {
+		return capacity, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for _, n := range nodes.Items {
+		if n.Spec.Unschedulable {
+			continue
+		}
+		capacity.AllocatableCPU.Add(n.Status.Allocatable[corev1.ResourceCPU])
+		capacity.AllocatableMemory.Add(n.Status.Allocatable[corev1.ResourceMemory])
+	}
+
+	quotas, err := c.Client.CoreV1().ResourceQuotas(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: "metadata.name=tenant-quota",
+	})
+	if err != nil {
+		return capacity, fmt.Errorf("failed to list tenant quotas: %w", err)
+	}
+	for _, q := range quotas.Items {
+		capacity.CommittedCPU.Add(q.Spec.Hard[corev1.ResourceRequestsCPU])
+		capacity.CommittedMemory.Add(q.Spec.Hard[corev1.ResourceRequestsMemory])
+		capacity.Tenants++
+	}
+
+	return capacity, nil
+}
+
+// Place picks the eligible cluster with the most free CPU that can still fit the
+// tenant's quota. Clusters that cannot be measured are skipped.
+func (r *Registry) Place(ctx context.Context, req Request) (*Cluster, error) {
+	var (
+		best     *Cluster
+		bestFree resource.Quantity
+		eligible int
+	)
+
+	for _, c := range r.clusters {
+		if c.Standby {
+			continue
+		}
+		if req.Region != "" && c.Region != req.Region {
+			continue
+		}
+		if !c.AcceptsTier(req.Tier) {
+			continue
+		}
+		eligible++
+
+		capacity, err := r.Capacity(ctx, c)
+		if err != nil {
+			continue
+		}
+		freeCPU, freeMemory := capacity.FreeCPU(), capacity.FreeMemory()
+		if freeCPU.Cmp(req.CPU) < 0 || freeMemory.Cmp(req.Memory) < 0 {
+			continue
+		}
+		if best == nil || freeCPU.Cmp(bestFree) > 0 {
+			best, bestFree = c, freeCPU
+		}
+	}
+
+	if eligible == 0 {
+		return nil, fmt.Errorf("no cluster accepts tier %s in region %q", req.Tier, req.Region)
+	}
+	if best == nil {
+		return nil, ErrNoCapacity
+	}
+	return best, nil
+}
@@ -0,0 +1,147 @@
+package clusters
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeCluster is a cluster with one shared node of cpu and memory, one
+// dedicated node, and a tenant-quota committing committedCPU
+func fakeCluster(spec Spec, cpu, committedCPU string) *Cluster {
+	objs := []runtime.Object{
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+			}},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "dedicated", Labels: map[string]string{"tenant-id": "acme"}},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("64"),
+				corev1.ResourceMemory: resource.MustParse("256Gi"),
+			}},
+		},
+		&corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "tenant-quota", Namespace: "tenant-existing"},
+			Spec: corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse(committedCPU),
+				corev1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+			}},
+		},
+	}
+	return &Cluster{Spec: spec, Client: fake.NewSimpleClientset(objs...)}
+}
+
+func TestPlace(t *testing.T) {
+	pro := Request{Tier: "PLAN_TIER_PRO", CPU: resource.MustParse("4"), Memory: resource.MustParse("8Gi")}
+	inRegion := func(req Request, region string) Request {
+		req.Region = region
+		return req
+	}
+
+	tests := []struct {
+		name     string
+		clusters []*Cluster
+		req      Request
+		want     string
+		wantErr  error
+	}{
+		{
+			name: "most free CPU",
+			clusters: []*Cluster{
+				fakeCluster(Spec{Name: "a"}, "16", "8"),
+				fakeCluster(Spec{Name: "b"}, "16", "4"),
+			},
+			req:  pro,
+			want: "b",
+		},
+		{
+			name: "dedicated nodes are not shared capacity",
+			clusters: []*Cluster{
+				fakeCluster(Spec{Name: "a"}, "8", "6"),
+			},
+			req:     pro,
+			wantErr: ErrNoCapacity,
+		},
+		{
+			name: "standby never chosen",
+			clusters: []*Cluster{
+				fakeCluster(Spec{Name: "a"}, "16", "8"),
+				fakeCluster(Spec{Name: "dr", Standby: true}, "64", "0"),
+			},
+			req:  pro,
+			want: "a",
+		},
+		{
+			name: "region",
+			clusters: []*Cluster{
+				fakeCluster(Spec{Name: "use1", Region: "us-east-1"}, "64", "0"),
+				fakeCluster(Spec{Name: "euw1", Region: "eu-west-1"}, "16", "0"),
+			},
+			req:  inRegion(pro, "eu-west-1"),
+			want: "euw1",
+		},
+		{
+			name: "tier",
+			clusters: []*Cluster{
+				fakeCluster(Spec{Name: "free", Tiers: []string{"PLAN_TIER_FREE"}}, "64", "0"),
+				fakeCluster(Spec{Name: "paid", Tiers: []string{"PLAN_TIER_STARTER", "PLAN_TIER_PRO"}}, "16", "0"),
+			},
+			req:  pro,
+			want: "paid",
+		},
+		{
+			name: "single cluster of another tier",
+			clusters: []*Cluster{
+				fakeCluster(Spec{Name: "free", Tiers: []string{"PLAN_TIER_FREE"}}, "64", "0"),
+			},
+			req:     pro,
+			wantErr: errNoEligible,
+		},
+		{
+			name: "no cluster in region",
+			clusters: []*Cluster{
+				fakeCluster(Spec{Name: "use1", Region: "us-east-1"}, "64", "0"),
+			},
+			req:     inRegion(pro, "ap-south-1"),
+			wantErr: errNoEligible,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, err := NewRegistry(tt.clusters...)
+			if err != nil {
+				t.Fatalf("NewRegistry: %v", err)
+			}
+
+			got, err := registry.Place(context.Background(), tt.req)
+			switch {
+			case tt.wantErr == errNoEligible:
+				if err == nil || errors.Is(err, ErrNoCapacity) {
+					t.Fatalf("Place error = %v, want no eligible cluster", err)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Place error = %v, want %v", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("Place: %v", err)
+			case got.Name != tt.want:
+				t.Errorf("Place = %s, want %s", got.Name, tt.want)
+			}
+		})
+	}
+}
+
+// errNoEligible marks cases where no cluster matches the tier and region
+var errNoEligible = errors.New("no eligible cluster")
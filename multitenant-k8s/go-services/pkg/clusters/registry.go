@@ -0,0 +1,240 @@
+package clusters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DefaultName is the cluster name used when no registry file is configured.
+const DefaultName = "default"
+
+// Spec describes one workload cluster in the registry file.
+type Spec struct {
+	Name             string   `json:"name"`
+	Region           string   `json:"region"`
+	Tiers            []string `json:"tiers,omitempty"`             // Plan tiers placed here (e.g. "PLAN_TIER_PRO"); empty means all
+	KubeConfigPath   string   `json:"kubeconfig_path,omitempty"`   // kubeconfig file on disk
+	KubeConfigSecret string   `json:"kubeconfig_secret,omitempty"` // "<namespace>/<name>" secret with a "kubeconfig" key in the home cluster
+	Context          string   `json:"context,omitempty"`           // kubeconfig context (optional)
+	InCluster        bool     `json:"in_cluster,omitempty"`        // use the service's own cluster
+	ClusterARN       string   `json:"cluster_arn,omitempty"`       // EKS cluster ARN for IRSA trust policies
+	Standby          bool     `json:"standby,omitempty"`           // DR target: receives tenant replicas, never chosen by placement
+}
+
+// AcceptsTier reports whether tenants on tier may be placed on the cluster.
+func (s Spec) AcceptsTier(tier string) bool {
+	return len(s.Tiers) == 0 || slices.Contains(s.Tiers, tier)
+}
+
+// Cluster is a registry entry with connected clients.
+type Cluster struct {
+	Spec
+	Client  kubernetes.Interface
+	Dynamic dynamic.Interface // For CRDs (network policy engines, cert-manager, ...)
+}
+
+// Registry holds the clusters tenants can be placed on.
+type Registry struct {
+	clusters []*Cluster
+	byName   map[string]*Cluster
+}
+
+// NewRegistry creates a registry from already connected clusters.
+// The first cluster is the default for accounts with no recorded cluster.
+func NewRegistry(clusters ...*Cluster) (*Registry, error) {
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("registry needs at least one cluster")
+	}
+
+	r := &Registry{byName: make(map[string]*Cluster, len(clusters))}
+	for _, c := range clusters {
+		if c.Name == "" {
+			return nil, fmt.Errorf("cluster name must not be empty")
+		}
+		if len(r.clusters) == 0 && c.Standby {
+			return nil, fmt.Errorf("the first (default) cluster must not be a standby")
+		}
+		if _, dup := r.byName[c.Name]; dup {
+			return nil, fmt.Errorf("duplicate cluster %q", c.Name)
+		}
+		r.clusters = append(r.clusters, c)
+		r.byName[c.Name] = c
+	}
+	return r, nil
+}
+
+// LoadRegistry connects to every cluster listed in the JSON file at path
+// ({"clusters": [Spec, ...]}). With an empty path the registry holds a single
+// "default" cluster built from kubeconfigPath (or in-cluster config) and clusterARN.
+func LoadRegistry(ctx context.Context, path, kubeconfigPath, clusterARN string) (*Registry, error) {
+	home := &homeCluster{kubeconfigPath: kubeconfigPath}
+
+	if path == "" {
+		cfg, err := home.config()
+		if err != nil {
+			return nil, err
+		}
+		c, err := connect(Spec{Name: DefaultName, ClusterARN: clusterARN}, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewRegistry(c)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster registry: %w", err)
+	}
+	var file struct {
+		Clusters []Spec `json:"clusters"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse cluster registry: %w", err)
+	}
+
+	var clusters []*Cluster
+	for _, spec := range file.Clusters {
+		cfg, err := specConfig(ctx, spec, home)
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: %w", spec.Name, err)
+		}
+		c, err := connect(spec, cfg)
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, c)
+	}
+
+	return NewRegistry(clusters...)
+}
+
+// Get returns the named cluster. An empty name selects the default cluster.
+func (r *Registry) Get(name string) (*Cluster, error) {
+	if name == "" {
+		return r.clusters[0], nil
+	}
+	c, ok := r.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown cluster %q", name)
+	}
+	return c, nil
+}
+
+// Standby returns the first cluster marked standby, if any.
+func (r *Registry) Standby() (*Cluster, bool) {
+	for _, c := range r.clusters {
+		if c.Standby {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// All returns every registered cluster in registry order.
+func (r *Registry) All() []*Cluster {
+	return r.clusters
+}
+
+// connect creates the typed and dynamic clients for a cluster
+func connect(spec Spec, cfg *rest.Config) (*Cluster, error) {
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: failed to create client: %w", spec.Name, err)
+	}
+	dyn, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cluster %s: failed to create dynamic client: %w", spec.Name, err)
+	}
+	return &Cluster{Spec: spec, Client: client, Dynamic: dyn}, nil
+}
+
+// homeCluster connects to the service's own cluster on first use, so that a
+// registry of kubeconfig files also works outside of any cluster
+type homeCluster struct {
+	kubeconfigPath string
+	cfg            *rest.Config
+	client         kubernetes.Interface
+}
+
+// config loads the home cluster's REST config
+func (h *homeCluster) config() (*rest.Config, error) {
+	if h.cfg == nil {
+		cfg, err := restConfig(h.kubeconfigPath, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load home cluster config: %w", err)
+		}
+		h.cfg = cfg
+	}
+	return h.cfg, nil
+}
+
+// kubeClient creates the home cluster's client
+func (h *homeCluster) kubeClient() (kubernetes.Interface, error) {
+	if h.client == nil {
+		cfg, err := h.config()
+		if err != nil {
+			return nil, err
+		}
+		client, err := kubernetes.NewForConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create home cluster client: %w", err)
+		}
+		h.client = client
+	}
+	return h.client, nil
+}
+
+// specConfig builds the REST config for one registry entry
+func specConfig(ctx context.Context, spec Spec, home *homeCluster) (*rest.Config, error) {
+	switch {
+	case spec.InCluster:
+		return home.config()
+	case spec.KubeConfigPath != "":
+		return restConfig(spec.KubeConfigPath, spec.Context)
+	case spec.KubeConfigSecret != "":
+		namespace, name, ok := strings.Cut(spec.KubeConfigSecret, "/")
+		if !ok {
+			return nil, fmt.Errorf("kubeconfig_secret must be <namespace>/<name>")
+		}
+		homeClient, err := home.kubeClient()
+		if err != nil {
+			return nil, err
+		}
+		secret, err := homeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kubeconfig secret: %w", err)
+		}
+		raw, ok := secret.Data["kubeconfig"]
+		if !ok {
+			return nil, fmt.Errorf("secret %s has no kubeconfig key", spec.KubeConfigSecret)
+		}
+		cfg, err := clientcmd.Load(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse kubeconfig secret: %w", err)
+		}
+		return clientcmd.NewDefaultClientConfig(*cfg, &clientcmd.ConfigOverrides{CurrentContext: spec.Context}).ClientConfig()
+	default:
+		return nil, fmt.Errorf("one of in_cluster, kubeconfig_path or kubeconfig_secret is required")
+	}
+}
+
+// restConfig loads a kubeconfig file, or in-cluster config when path is empty
+func restConfig(path, kubeContext string) (*rest.Config, error) {
+	if path == "" {
+		return rest.InClusterConfig()
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: path},
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	).ClientConfig()
+}
@@ -0,0 +1,160 @@
+package egress
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PolicyName is the name of the egress policy created in each tenant namespace.
+const PolicyName = "tenant-egress-allowlist"
+
+// Mode is how an allowlist is enforced on a cluster.
+type Mode string
+
+// Enforcement modes, in order of preference.
+const (
+	ModeCilium   Mode = "cilium"   // CiliumNetworkPolicy toFQDNs
+	ModeCalico   Mode = "calico"   // projectcalico.org/v3 NetworkPolicy destination.domains
+	ModeResolved Mode = "resolved" // Kubernetes NetworkPolicy with IPs resolved by this service
+)
+
+var hostnamePattern = regexp.MustCompile(`^(\*\.)?([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z][a-z0-9-]{0,62}$`)
+
+// Allowlist is the set of external destinations a tenant's pods may reach.
+type Allowlist struct {
+	Hostnames []string `json:"hostnames,omitempty"` // "api.stripe.com" or "*.example.com"
+	CIDRs     []string `json:"cidrs,omitempty"`
+}
+
+// Empty reports whether the allowlist places no restriction.
+func (a Allowlist) Empty() bool {
+	return len(a.Hostnames) == 0 && len(a.CIDRs) == 0
+}
+
+// Normalize lower-cases hostnames, canonicalizes CIDRs and validates both.
+func (a Allowlist) Normalize() (Allowlist, error) {
+	var out Allowlist
+	for _, h := range a.Hostnames {
+		h = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(h)), ".")
+		if !hostnamePattern.MatchString(h) {
+			return Allowlist{}, fmt.Errorf("invalid hostname %q", h)
+		}
+		out.Hostnames = appendUnique(out.Hostnames, h)
+	}
+	for _, c := range a.CIDRs {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(c))
+		if err != nil {
+			return Allowlist{}, fmt.Errorf("invalid CIDR %q", c)
+		}
+		out.CIDRs = appendUnique(out.CIDRs, ipNet.String())
+	}
+	return out, nil
+}
+
+// Applier renders allowlists into the network policy engine of a cluster.
+type Applier struct {
+	resolver *net.Resolver
+}
+
+// NewApplier creates an applier that resolves hostnames with the system resolver.
+func NewApplier() *Applier {
+	return &Applier{resolver: net.DefaultResolver}
+}
+
+// DetectMode picks the best enforcement available on the cluster.
+func DetectMode(kc kubernetes.Interface) Mode {
+	if hasResource(kc, "cilium.io/v2", "ciliumnetworkpolicies") {
+		return ModeCilium
+	}
+	if hasResource(kc, "projectcalico.org/v3", "networkpolicies") {
+		return ModeCalico
+	}
+	return ModeResolved
+}
+
+// Apply creates or updates the egress policy of one of orgID's namespaces. An
+// empty allowlist removes the policy, leaving egress to the tenant-isolation
+// policy.
+func (a *Applier) Apply(ctx context.Context, kc kubernetes.Interface, dyn dynamic.Interface, orgID, namespace string, list Allowlist) (Mode, error) {
+	mode := DetectMode(kc)
+	if list.Empty() {
+		return mode, a.Remove(ctx, kc, dyn, namespace)
+	}
+
+	switch mode {
+	case ModeCilium:
+		return mode, applyUnstructured(ctx, dyn, ciliumGVR, ciliumPolicy(orgID, namespace, list))
+	case ModeCalico:
+		return mode, applyUnstructured(ctx, dyn, calicoGVR, calicoPolicy(orgID, namespace, list))
+	default:
+		return mode, a.applyResolved(ctx, kc, orgID, namespace, list)
+	}
+}
+
+// Remove deletes the tenant's egress policy from every engine.
+func (a *Applier) Remove(ctx context.Context, kc kubernetes.Interface, dyn dynamic.Interface, namespace string) error {
+	if err := deleteNetworkPolicy(ctx, kc, namespace); err != nil {
+		return err
+	}
+	for _, gvr := range []schema.GroupVersionResource{ciliumGVR, calicoGVR} {
+		err := dyn.Resource(gvr).Namespace(namespace).Delete(ctx, PolicyName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete %s %s: %w", gvr.Resource, PolicyName, err)
+		}
+	}
+	return nil
+}
+
+// applyUnstructured creates obj or replaces the existing object of the same name
+func applyUnstructured(ctx context.Context, dyn dynamic.Interface, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	client := dyn.Resource(gvr).Namespace(obj.GetNamespace())
+
+	existing, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := client.Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create %s: %w", gvr.Resource, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", gvr.Resource, err)
+	}
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	if _, err := client.Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update %s: %w", gvr.Resource, err)
+	}
+	return nil
+}
+
+func hasResource(kc kubernetes.Interface, groupVersion, resource string) bool {
+	list, err := kc.Discovery().ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return false
+	}
+	for _, r := range list.APIResources {
+		if r.Name == resource {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, v string) []string {
+	for _, existing := range list {
+		if existing == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
@@ -0,0 +1,134 @@
+package egress
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	ciliumGVR = schema.GroupVersionResource{Group: "cilium.io", Version: "v2", Resource: "ciliumnetworkpolicies"}
+	calicoGVR = schema.GroupVersionResource{Group: "projectcalico.org", Version: "v3", Resource: "networkpolicies"}
+)
+
+// ciliumPolicy allows DNS (with L7 visibility, which toFQDNs needs), traffic
+// to the tenant's namespaces and shared services, and the allowlisted FQDNs
+// and CIDRs
+func ciliumPolicy(orgID, namespace string, list Allowlist) *unstructured.Unstructured {
+	egress := []interface{}{
+		map[string]interface{}{
+			"toEndpoints": []interface{}{
+				map[string]interface{}{},
+				map[string]interface{}{
+					"matchLabels": map[string]interface{}{"k8s:io.cilium.k8s.namespace.labels.tenant-id": orgID},
+				},
+				map[string]interface{}{
+					"matchLabels": map[string]interface{}{"k8s:io.cilium.k8s.namespace.labels.common-services": "true"},
+				},
+			},
+		},
+		map[string]interface{}{
+			"toEndpoints": []interface{}{
+				map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						"k8s:io.kubernetes.pod.namespace": "kube-system",
+						"k8s:k8s-app":                     "kube-dns",
+					},
+				},
+			},
+			"toPorts": []interface{}{
+				map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{"port": "53", "protocol": "ANY"},
+					},
+					"rules": map[string]interface{}{
+						"dns": []interface{}{map[string]interface{}{"matchPattern": "*"}},
+					},
+				},
+			},
+		},
+	}
+
+	if len(list.Hostnames) > 0 {
+		var fqdns []interface{}
+		for _, h := range list.Hostnames {
+			if strings.HasPrefix(h, "*.") {
+				fqdns = append(fqdns, map[string]interface{}{"matchPattern": h})
+			} else {
+				fqdns = append(fqdns, map[string]interface{}{"matchName": h})
+			}
+		}
+		egress = append(egress, map[string]interface{}{"toFQDNs": fqdns})
+	}
+	if len(list.CIDRs) > 0 {
+		egress = append(egress, map[string]interface{}{"toCIDR": toInterfaces(list.CIDRs)})
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cilium.io/v2",
+		"kind":       "CiliumNetworkPolicy",
+		"metadata":   policyMeta(namespace),
+		"spec": map[string]interface{}{
+			"endpointSelector": map[string]interface{}{},
+			"egress":           egress,
+		},
+	}}
+}
+
+// calicoPolicy allows DNS, traffic to the tenant's namespaces and shared
+// services, and the allowlisted domains and networks
+func calicoPolicy(orgID, namespace string, list Allowlist) *unstructured.Unstructured {
+	allow := func(destination map[string]interface{}, protocol string) map[string]interface{} {
+		rule := map[string]interface{}{"action": "Allow", "destination": destination}
+		if protocol != "" {
+			rule["protocol"] = protocol
+		}
+		return rule
+	}
+
+	dnsPorts := map[string]interface{}{"ports": []interface{}{int64(53)}}
+	egress := []interface{}{
+		allow(map[string]interface{}{"selector": "all()"}, ""),
+		allow(map[string]interface{}{"namespaceSelector": fmt.Sprintf("tenant-id == '%s'", orgID)}, ""),
+		allow(map[string]interface{}{"namespaceSelector": "common-services == 'true'"}, ""),
+		allow(dnsPorts, "UDP"),
+		allow(dnsPorts, "TCP"),
+	}
+	if len(list.Hostnames) > 0 {
+		egress = append(egress, allow(map[string]interface{}{"domains": toInterfaces(list.Hostnames)}, ""))
+	}
+	if len(list.CIDRs) > 0 {
+		egress = append(egress, allow(map[string]interface{}{"nets": toInterfaces(list.CIDRs)}, ""))
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "projectcalico.org/v3",
+		"kind":       "NetworkPolicy",
+		"metadata":   policyMeta(namespace),
+		"spec": map[string]interface{}{
+			"selector": "all()",
+			"types":    []interface{}{"Egress"},
+			"egress":   egress,
+		},
+	}}
+}
+
+func policyMeta(namespace string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":      PolicyName,
+		"namespace": namespace,
+		"labels": map[string]interface{}{
+			"managed-by": "account-provisioning-service",
+		},
+	}
+}
+
+func toInterfaces(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,136 @@
+package egress
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResolvedAtAnnotation records when hostnames were last resolved into the policy.
+const ResolvedAtAnnotation = "account-provisioning/egress-resolved-at"
+
+// applyResolved renders the allowlist as a plain NetworkPolicy. Hostnames are
+// resolved now; callers re-apply periodically so address changes are picked up.
+// If any hostname fails to resolve the existing policy is left untouched.
+func (a *Applier) applyResolved(ctx context.Context, kc kubernetes.Interface, orgID, namespace string, list Allowlist) error {
+	cidrs := append([]string(nil), list.CIDRs...)
+	for _, h := range list.Hostnames {
+		if strings.HasPrefix(h, "*.") {
+			return fmt.Errorf("wildcard hostname %s requires Cilium or Calico", h)
+		}
+		ips, err := a.resolver.LookupIPAddr(ctx, h)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", h, err)
+		}
+		for _, ip := range ips {
+			addr, bits := ip.IP.To4(), 32
+			if addr == nil {
+				addr, bits = ip.IP, 128
+			}
+			cidrs = appendUnique(cidrs, (&net.IPNet{IP: addr, Mask: net.CIDRMask(bits, bits)}).String())
+		}
+	}
+	sort.Strings(cidrs)
+	if len(cidrs) == 0 {
+		// An egress rule with no peers would allow every destination.
+		return fmt.Errorf("allowlist resolved to no addresses")
+	}
+
+	policy := resolvedPolicy(orgID, namespace, list.Hostnames, cidrs)
+
+	existing, err := kc.NetworkingV1().NetworkPolicies(namespace).Get(ctx, PolicyName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = kc.NetworkingV1().NetworkPolicies(namespace).Create(ctx, policy, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create network policy: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get network policy: %w", err)
+	}
+
+	existing.Labels = policy.Labels
+	existing.Annotations = policy.Annotations
+	existing.Spec = policy.Spec
+	if _, err := kc.NetworkingV1().NetworkPolicies(namespace).Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update network policy: %w", err)
+	}
+	return nil
+}
+
+// resolvedPolicy allows DNS, traffic to the tenant's namespaces and shared
+// services, and the given networks
+func resolvedPolicy(orgID, namespace string, hostnames, cidrs []string) *networkingv1.NetworkPolicy {
+	udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+	dnsPort := intstr.FromInt32(53)
+
+	var blocks []networkingv1.NetworkPolicyPeer
+	for _, c := range cidrs {
+		blocks = append(blocks, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: c}})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PolicyName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"managed-by": "account-provisioning-service",
+			},
+			Annotations: map[string]string{
+				"account-provisioning/egress-hostnames": strings.Join(hostnames, ","),
+				ResolvedAtAnnotation:                    time.Now().UTC().Format(time.RFC3339),
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{
+					To: []networkingv1.NetworkPolicyPeer{
+						{PodSelector: &metav1.LabelSelector{}},
+						{NamespaceSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"tenant-id": orgID},
+						}},
+						{NamespaceSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"common-services": "true"},
+						}},
+					},
+				},
+				{
+					To: []networkingv1.NetworkPolicyPeer{{
+						NamespaceSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"kubernetes.io/metadata.name": "kube-system"},
+						},
+					}},
+					Ports: []networkingv1.NetworkPolicyPort{
+						{Protocol: &udp, Port: &dnsPort},
+						{Protocol: &tcp, Port: &dnsPort},
+					},
+				},
+				{
+					To: blocks,
+				},
+			},
+		},
+	}
+}
+
+// deleteNetworkPolicy removes the resolved-mode policy if present
+func deleteNetworkPolicy(ctx context.Context, kc kubernetes.Interface, namespace string) error {
+	err := kc.NetworkingV1().NetworkPolicies(namespace).Delete(ctx, PolicyName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete network policy: %w", err)
+	}
+	return nil
+}
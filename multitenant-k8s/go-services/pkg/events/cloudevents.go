@@ -0,0 +1,86 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+// Account lifecycle event types.
+const (
+	TypeAccountCreated     = "account.created"
+	TypeAccountPlanChanged = "account.plan_changed"
+	TypeAccountSuspended   = "account.suspended"
+	TypeAccountResumed     = "account.resumed"
+	TypeAccountDeleted     = "account.deleted"
+	TypeAccountFailedOver  = "account.failed_over"
+
+	TypeAccountExpiryWarning  = "account.expiry_warning"  // Trial or plan ends within the warning period
+	TypeAccountExpired        = "account.expired"         // Trial or plan ended; the account was downgraded or suspended
+	TypeAccountExpiryChanged  = "account.expiry_changed"  // An admin set or cleared a deadline
+	TypeAccountExpiryReverted = "account.expiry_reverted" // An admin undid an expiry
+)
+
+// Source identifies this service as the producer of account events.
+const Source = "/account-provisioning-service"
+
+// CloudEvent is a CloudEvents 1.0 envelope in structured JSON mode.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// AccountData is the payload of every account lifecycle event.
+type AccountData struct {
+	OrganizationID   string `json:"organization_id"`
+	Namespace        string `json:"namespace,omitempty"`
+	OrganizationType string `json:"organization_type,omitempty"`
+	PlanTier         string `json:"plan_tier,omitempty"`
+	PreviousPlanTier string `json:"previous_plan_tier,omitempty"` // account.plan_changed only
+	Status           string `json:"status,omitempty"`
+	Reason           string `json:"reason,omitempty"`
+	ExpiresAt        string `json:"expires_at,omitempty"` // RFC 3339 deadline; expiry events only
+}
+
+// NewAccountEvent wraps data in a CloudEvent and returns it as an outbox message
+// keyed by organization ID, so a tenant's events stay ordered within a partition.
+func NewAccountEvent(eventType string, data AccountData) (storage.OutboxMessage, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return storage.OutboxMessage{}, fmt.Errorf("failed to encode event data: %w", err)
+	}
+
+	now := time.Now().UTC()
+	ce := CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              uuid.NewString(),
+		Source:          Source,
+		Type:            eventType,
+		Subject:         data.OrganizationID,
+		Time:            now,
+		DataContentType: "application/json",
+		Data:            payload,
+	}
+
+	value, err := json.Marshal(ce)
+	if err != nil {
+		return storage.OutboxMessage{}, fmt.Errorf("failed to encode cloud event: %w", err)
+	}
+
+	return storage.OutboxMessage{
+		ID:        ce.ID,
+		Key:       []byte(data.OrganizationID),
+		Payload:   value,
+		CreatedAt: now,
+	}, nil
+}
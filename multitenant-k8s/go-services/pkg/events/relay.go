@@ -0,0 +1,77 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/periodic"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+// Publisher sends a keyed message to the event topic.
+// schedulerservice.KafkaQueue implements this interface.
+type Publisher interface {
+	Enqueue(ctx context.Context, key []byte, payload []byte) error
+}
+
+// Relay moves committed outbox messages to the publisher. Delivery is
+// at-least-once: consumers should de-duplicate on the CloudEvent id.
+type Relay struct {
+	outbox    *storage.OutboxStore
+	publisher Publisher
+	interval  time.Duration
+	batchSize int
+}
+
+// NewRelay creates an outbox relay that polls every interval.
+func NewRelay(outbox *storage.OutboxStore, publisher Publisher, interval time.Duration) (*Relay, error) {
+	if outbox == nil {
+		return nil, fmt.Errorf("outbox must not be nil")
+	}
+	if publisher == nil {
+		return nil, fmt.Errorf("publisher must not be nil")
+	}
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	return &Relay{
+		outbox:    outbox,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: 100,
+	}, nil
+}
+
+// Run publishes pending messages until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	periodic.Every(ctx, r.interval, "event relay", r.Flush)
+}
+
+// Flush publishes pending messages in order, stopping at the first failure so
+// later events are never delivered ahead of earlier ones.
+func (r *Relay) Flush(ctx context.Context) error {
+	for {
+		msgs, err := r.outbox.Pending(ctx, r.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(msgs) == 0 {
+			return nil
+		}
+
+		for _, m := range msgs {
+			if err := r.publisher.Enqueue(ctx, m.Key, m.Payload); err != nil {
+				return fmt.Errorf("failed to publish event %s: %w", m.ID, err)
+			}
+			if err := r.outbox.Ack(ctx, m); err != nil {
+				return fmt.Errorf("failed to ack event %s: %w", m.ID, err)
+			}
+		}
+
+		if len(msgs) < r.batchSize {
+			return nil
+		}
+	}
+}
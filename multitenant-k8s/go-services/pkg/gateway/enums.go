@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// enumConverter rewrites one enum value name
+type enumConverter func(ed protoreflect.EnumDescriptor, name string) string
+
+// enumPrefix is the prefix shared by an enum's values, taken from its
+// *_UNSPECIFIED zero value, e.g. "PLAN_TIER_"
+func enumPrefix(ed protoreflect.EnumDescriptor) string {
+	zero := string(ed.Values().Get(0).Name())
+	if prefix, ok := strings.CutSuffix(zero, "UNSPECIFIED"); ok {
+		return prefix
+	}
+	return ""
+}
+
+// expandEnum turns "pro" or "PRO" into "PLAN_TIER_PRO". Unknown names are left
+// alone so the JSON codec reports them.
+func expandEnum(ed protoreflect.EnumDescriptor, name string) string {
+	upper := strings.ToUpper(name)
+	if ed.Values().ByName(protoreflect.Name(upper)) != nil {
+		return upper
+	}
+	if full := enumPrefix(ed) + upper; ed.Values().ByName(protoreflect.Name(full)) != nil {
+		return full
+	}
+	return name
+}
+
+// shortenEnum turns "PLAN_TIER_PRO" into "pro"
+func shortenEnum(ed protoreflect.EnumDescriptor, name string) string {
+	return strings.ToLower(strings.TrimPrefix(name, enumPrefix(ed)))
+}
+
+// fieldByName finds a field by its proto or JSON name
+func fieldByName(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	if fd := md.Fields().ByName(protoreflect.Name(name)); fd != nil {
+		return fd
+	}
+	return md.Fields().ByJSONName(name)
+}
+
+// convertMessage rewrites every enum value in a decoded JSON object of type md
+func convertMessage(obj map[string]any, md protoreflect.MessageDescriptor, conv enumConverter) {
+	for key, v := range obj {
+		fd := fieldByName(md, key)
+		if fd == nil {
+			continue
+		}
+		switch {
+		case fd.IsMap():
+			if m, ok := v.(map[string]any); ok {
+				for k, mv := range m {
+					m[k] = convertValue(mv, fd.MapValue(), conv)
+				}
+			}
+		case fd.IsList():
+			if list, ok := v.([]any); ok {
+				for i, lv := range list {
+					list[i] = convertValue(lv, fd, conv)
+				}
+			}
+		default:
+			obj[key] = convertValue(v, fd, conv)
+		}
+	}
+}
+
+func convertValue(v any, fd protoreflect.FieldDescriptor, conv enumConverter) any {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if s, ok := v.(string); ok {
+			return conv(fd.Enum(), s)
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if m, ok := v.(map[string]any); ok && !isWellKnown(fd.Message()) {
+			convertMessage(m, fd.Message(), conv)
+		}
+	}
+	return v
+}
+
+// isWellKnown reports whether protojson encodes the message specially
+func isWellKnown(md protoreflect.MessageDescriptor) bool {
+	return md.ParentFile() != nil && strings.HasPrefix(string(md.FullName()), "google.protobuf.")
+}
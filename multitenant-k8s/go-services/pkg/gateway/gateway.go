@@ -0,0 +1,246 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maxBodyBytes caps the size of a REST request body.
+const maxBodyBytes = 4 << 20
+
+// pathParam matches the {field} placeholders of a route path.
+var pathParam = regexp.MustCompile(`\{([a-z0-9_]+)\}`)
+
+// Route maps a REST endpoint onto a Connect procedure. Path placeholders, query
+// parameters (GET and DELETE) and the JSON body (other methods) all name fields
+// of the request message by their proto (snake_case) names.
+type Route struct {
+	Method    string        // HTTP method, e.g. http.MethodPost
+	Path      string        // e.g. /api/accounts/{organization_id}
+	Procedure string        // Connect procedure, e.g. acctconnect.AccountProvisioningServiceGetAccountProcedure
+	Request   proto.Message // Zero value of the request message
+	Response  proto.Message // Zero value of the response message
+	Summary   string        // One line for the OpenAPI document
+}
+
+// Gateway serves REST/JSON routes by translating them into in-process Connect
+// calls, so authentication, authorization and auditing run exactly as they do
+// for Connect clients.
+type Gateway struct {
+	connect http.Handler
+	routes  []Route
+	openapi []byte
+}
+
+// New validates the routes and builds the OpenAPI document for them. connect is
+// the handler returned by the generated New...ServiceHandler.
+func New(title, version string, connect http.Handler, routes []Route) (*Gateway, error) {
+	if connect == nil {
+		return nil, errors.New("connect handler must not be nil")
+	}
+	for _, r := range routes {
+		if r.Method == "" || r.Path == "" || r.Procedure == "" || r.Request == nil || r.Response == nil {
+			return nil, fmt.Errorf("incomplete route %s %s", r.Method, r.Path)
+		}
+		fields := r.Request.ProtoReflect().Descriptor().Fields()
+		for _, m := range pathParam.FindAllStringSubmatch(r.Path, -1) {
+			if fields.ByName(protoreflect.Name(m[1])) == nil {
+				return nil, fmt.Errorf("route %s %s: %s has no field %q", r.Method, r.Path, r.Request.ProtoReflect().Descriptor().FullName(), m[1])
+			}
+		}
+	}
+
+	doc, err := openAPIDocument(title, version, routes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate OpenAPI document: %w", err)
+	}
+	return &Gateway{connect: connect, routes: routes, openapi: doc}, nil
+}
+
+// Register adds every route and GET /openapi.json to mux.
+func (g *Gateway) Register(mux *http.ServeMux) {
+	for _, r := range g.routes {
+		mux.Handle(r.Method+" "+r.Path, g.handler(r))
+	}
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(g.openapi)
+	})
+}
+
+// OpenAPI returns the OpenAPI 3 document describing the routes.
+func (g *Gateway) OpenAPI() []byte {
+	return g.openapi
+}
+
+func (g *Gateway) handler(route Route) http.Handler {
+	reqDesc := route.Request.ProtoReflect().Descriptor()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := requestBody(r, route, reqDesc)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+			return
+		}
+
+		// Replay the call as a Connect unary JSON request
+		inner := r.Clone(r.Context())
+		inner.Method = http.MethodPost
+		inner.URL.Path, inner.URL.RawPath, inner.URL.RawQuery = route.Procedure, "", ""
+		inner.RequestURI = route.Procedure
+		inner.Body = io.NopCloser(bytes.NewReader(body))
+		inner.ContentLength = int64(len(body))
+		inner.Header.Set("Content-Type", "application/json")
+		inner.Header.Del("Content-Encoding")
+		inner.Header.Del("Accept-Encoding")
+
+		rec := &recorder{header: http.Header{}, status: http.StatusOK}
+		g.connect.ServeHTTP(rec, inner)
+
+		w.Header().Set("Content-Type", "application/json")
+		if rec.status != http.StatusOK {
+			// Connect already maps error codes to HTTP statuses; its error body
+			// ({"code": ..., "message": ...}) is returned unchanged
+			w.WriteHeader(rec.status)
+			w.Write(rec.body.Bytes())
+			return
+		}
+		out, err := responseBody(rec.body.Bytes(), route.Response)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "internal", err.Error())
+			return
+		}
+		w.Write(out)
+	})
+}
+
+// requestBody merges the JSON body, path and query parameters into one
+// snake_case object with full enum names, ready for Connect's JSON codec
+func requestBody(r *http.Request, route Route, md protoreflect.MessageDescriptor) ([]byte, error) {
+	obj := map[string]any{}
+	if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		data, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body: %w", err)
+		}
+		if len(data) > maxBodyBytes {
+			return nil, fmt.Errorf("body exceeds %d bytes", maxBodyBytes)
+		}
+		if len(bytes.TrimSpace(data)) > 0 {
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.UseNumber()
+			if err := dec.Decode(&obj); err != nil {
+				return nil, fmt.Errorf("body must be a JSON object: %w", err)
+			}
+		}
+	} else {
+		for key, values := range r.URL.Query() {
+			fd := fieldByName(md, key)
+			if fd == nil || fd.Kind() == protoreflect.MessageKind || fd.IsMap() {
+				return nil, fmt.Errorf("unknown query parameter %q", key)
+			}
+			v, err := paramValue(fd, values)
+			if err != nil {
+				return nil, err
+			}
+			obj[string(fd.Name())] = v
+		}
+	}
+
+	// Path parameters always win over the body
+	for _, m := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+		fd := md.Fields().ByName(protoreflect.Name(m[1]))
+		v, err := paramValue(fd, []string{r.PathValue(m[1])})
+		if err != nil {
+			return nil, err
+		}
+		delete(obj, fd.JSONName())
+		obj[m[1]] = v
+	}
+
+	convertMessage(obj, md, expandEnum)
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	// Connect's codec discards unknown fields and enum values; reject them here
+	if err := protojson.Unmarshal(data, route.Request.ProtoReflect().New().Interface()); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// paramValue converts string parameters to the JSON protojson expects for the
+// field; numbers may stay quoted
+func paramValue(fd protoreflect.FieldDescriptor, values []string) (any, error) {
+	scalar := func(s string) (any, error) {
+		if fd.Kind() == protoreflect.BoolKind {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return nil, fmt.Errorf("parameter %s: %w", fd.Name(), err)
+			}
+			return b, nil
+		}
+		return s, nil
+	}
+	if !fd.IsList() {
+		return scalar(values[len(values)-1])
+	}
+	list := make([]any, 0, len(values))
+	for _, s := range values {
+		v, err := scalar(s)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+// responseBody re-encodes a Connect JSON response with snake_case field names
+// and short enum names
+func responseBody(data []byte, prototype proto.Message) ([]byte, error) {
+	msg := prototype.ProtoReflect().New().Interface()
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+	obj := map[string]any{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+	convertMessage(obj, msg.ProtoReflect().Descriptor(), shortenEnum)
+	return json.Marshal(obj)
+}
+
+// writeError writes an error in the same shape as Connect errors.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"code": code, "message": message})
+}
+
+// recorder buffers the response of the in-process Connect call
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header         { return r.header }
+func (r *recorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *recorder) WriteHeader(status int)      { r.status = status }
@@ -0,0 +1,159 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+)
+
+func TestRequestBody(t *testing.T) {
+	create := Route{Method: http.MethodPost, Path: "/api/accounts", Request: &acctv1.CreateAccountRequest{}}
+	update := Route{Method: http.MethodPatch, Path: "/api/accounts/{organization_id}", Request: &acctv1.UpdateAccountRequest{}}
+	list := Route{Method: http.MethodGet, Path: "/api/accounts", Request: &acctv1.ListAccountsRequest{}}
+
+	tests := []struct {
+		name    string
+		route   Route
+		target  string
+		path    map[string]string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "short enum names",
+			route:  create,
+			target: "/api/accounts",
+			body:   `{"organization_id": "acme", "plan_tier": "pro", "organization_type": "NODE"}`,
+			want:   `{"organization_id": "acme", "plan_tier": "PLAN_TIER_PRO", "organization_type": "ORGANIZATION_TYPE_NODE"}`,
+		},
+		{
+			name:   "full enum and JSON names",
+			route:  create,
+			target: "/api/accounts",
+			body:   `{"organizationId": "acme", "planTier": "PLAN_TIER_STARTER"}`,
+			want:   `{"organizationId": "acme", "planTier": "PLAN_TIER_STARTER"}`,
+		},
+		{
+			name:   "empty body",
+			route:  create,
+			target: "/api/accounts",
+			want:   `{}`,
+		},
+		{
+			name:   "path parameter wins over body",
+			route:  update,
+			target: "/api/accounts/acme",
+			path:   map[string]string{"organization_id": "acme"},
+			body:   `{"organizationId": "other", "plan_tier": "enterprise"}`,
+			want:   `{"organization_id": "acme", "plan_tier": "PLAN_TIER_ENTERPRISE"}`,
+		},
+		{
+			name:   "query parameters",
+			route:  list,
+			target: "/api/accounts?page_size=10&statusFilter=ACTIVE",
+			want:   `{"page_size": "10", "status_filter": "ACTIVE"}`,
+		},
+		{
+			name:    "unknown query parameter",
+			route:   list,
+			target:  "/api/accounts?owner=acme",
+			wantErr: true,
+		},
+		{
+			name:    "unknown enum value",
+			route:   create,
+			target:  "/api/accounts",
+			body:    `{"plan_tier": "platinum"}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			route:   create,
+			target:  "/api/accounts",
+			body:    `{"owner": "acme"}`,
+			wantErr: true,
+		},
+		{
+			name:    "body not an object",
+			route:   create,
+			target:  "/api/accounts",
+			body:    `["acme"]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.route.Method, tt.target, strings.NewReader(tt.body))
+			for k, v := range tt.path {
+				r.SetPathValue(k, v)
+			}
+
+			got, err := requestBody(r, tt.route, tt.route.Request.ProtoReflect().Descriptor())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("requestBody = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("requestBody: %v", err)
+			}
+
+			var gotObj, wantObj map[string]any
+			if err := json.Unmarshal(got, &gotObj); err != nil {
+				t.Fatalf("requestBody returned invalid JSON %s: %v", got, err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &wantObj); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotObj, wantObj) {
+				t.Errorf("requestBody = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseBody(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "short enum names and proto field names",
+			in:   `{"organizationId": "acme", "planTier": "PLAN_TIER_PRO", "organizationType": "ORGANIZATION_TYPE_NODE"}`,
+			want: `{"organization_id": "acme", "plan_tier": "pro", "organization_type": "node"}`,
+		},
+		{
+			name: "unknown fields dropped",
+			in:   `{"organizationId": "acme", "addedLater": true}`,
+			want: `{"organization_id": "acme"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := responseBody([]byte(tt.in), &acctv1.UpdateAccountRequest{})
+			if err != nil {
+				t.Fatalf("responseBody: %v", err)
+			}
+			var gotObj, wantObj map[string]any
+			if err := json.Unmarshal(got, &gotObj); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &wantObj); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotObj, wantObj) {
+				t.Errorf("responseBody = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
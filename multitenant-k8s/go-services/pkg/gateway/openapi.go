@@ -0,0 +1,180 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// openAPIDocument generates an OpenAPI 3.0 document for the routes from the
+// request and response descriptors, using the same snake_case names and short
+// enum values the gateway speaks.
+func openAPIDocument(title, version string, routes []Route) ([]byte, error) {
+	schemas := map[string]any{}
+	paths := map[string]map[string]any{}
+
+	for _, r := range routes {
+		reqDesc := r.Request.ProtoReflect().Descriptor()
+		respDesc := r.Response.ProtoReflect().Descriptor()
+
+		var params []any
+		inPath := map[string]bool{}
+		for _, m := range pathParam.FindAllStringSubmatch(r.Path, -1) {
+			inPath[m[1]] = true
+			params = append(params, map[string]any{
+				"name":     m[1],
+				"in":       "path",
+				"required": true,
+				"schema":   fieldSchema(reqDesc.Fields().ByName(protoreflect.Name(m[1])), schemas),
+			})
+		}
+
+		op := map[string]any{
+			"operationId": r.Procedure[strings.LastIndex(r.Procedure, "/")+1:],
+			"summary":     r.Summary,
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "OK",
+					"content":     jsonContent(messageRef(respDesc, schemas)),
+				},
+				"default": map[string]any{
+					"description": "Error",
+					"content":     jsonContent(map[string]any{"$ref": "#/components/schemas/Error"}),
+				},
+			},
+		}
+		if r.Method == http.MethodGet || r.Method == http.MethodDelete {
+			fields := reqDesc.Fields()
+			for i := 0; i < fields.Len(); i++ {
+				fd := fields.Get(i)
+				if inPath[string(fd.Name())] || fd.IsMap() || fd.Kind() == protoreflect.MessageKind {
+					continue
+				}
+				params = append(params, map[string]any{
+					"name":   string(fd.Name()),
+					"in":     "query",
+					"schema": fieldSchema(fd, schemas),
+				})
+			}
+		} else {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content":  jsonContent(messageRef(reqDesc, schemas)),
+			}
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		if paths[r.Path] == nil {
+			paths[r.Path] = map[string]any{}
+		}
+		paths[r.Path][strings.ToLower(r.Method)] = op
+	}
+
+	schemas["Error"] = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"code":    map[string]any{"type": "string", "example": "not_found"},
+			"message": map[string]any{"type": "string"},
+		},
+	}
+
+	return json.MarshalIndent(map[string]any{
+		"openapi": "3.0.3",
+		"info":    map[string]any{"title": title, "version": version},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearer": map[string]any{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "JWT or mcpk_ API key",
+				},
+			},
+		},
+		"security": []any{map[string]any{"bearer": []any{}}},
+	}, "", "  ")
+}
+
+func jsonContent(schema any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// messageRef returns a $ref to the message's schema, adding it (and every
+// message it references) to schemas
+func messageRef(md protoreflect.MessageDescriptor, schemas map[string]any) map[string]any {
+	name := string(md.FullName())
+	ref := map[string]any{"$ref": "#/components/schemas/" + name}
+	if _, ok := schemas[name]; ok {
+		return ref
+	}
+	schemas[name] = nil // Placeholder breaks cycles
+
+	props := map[string]any{}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		props[string(fd.Name())] = fieldSchema(fd, schemas)
+	}
+	schemas[name] = map[string]any{"type": "object", "properties": props}
+	return ref
+}
+
+func fieldSchema(fd protoreflect.FieldDescriptor, schemas map[string]any) map[string]any {
+	switch {
+	case fd.IsMap():
+		return map[string]any{"type": "object", "additionalProperties": singularSchema(fd.MapValue(), schemas)}
+	case fd.IsList():
+		return map[string]any{"type": "array", "items": singularSchema(fd, schemas)}
+	default:
+		return singularSchema(fd, schemas)
+	}
+}
+
+// singularSchema describes one value of the field as protojson encodes it
+func singularSchema(fd protoreflect.FieldDescriptor, schemas map[string]any) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer", "format": "int64", "minimum": 0}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson quotes 64-bit integers
+		return map[string]any{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind:
+		return map[string]any{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]any{"type": "number", "format": "double"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		var names []string
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			if v := values.Get(i); v.Number() != 0 {
+				names = append(names, shortenEnum(fd.Enum(), string(v.Name())))
+			}
+		}
+		return map[string]any{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		switch fd.Message().FullName() {
+		case "google.protobuf.Timestamp":
+			return map[string]any{"type": "string", "format": "date-time"}
+		case "google.protobuf.Duration":
+			return map[string]any{"type": "string", "example": "1.5s"}
+		}
+		if isWellKnown(fd.Message()) {
+			return map[string]any{}
+		}
+		return messageRef(fd.Message(), schemas)
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
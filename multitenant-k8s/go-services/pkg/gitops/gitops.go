@@ -0,0 +1,372 @@
+// Package gitops writes tenant objects to a Git repository for a GitOps
+// controller such as Argo CD or Flux to apply, and reports when the
+// controller has synced a commit.
+package gitops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// Format is how a tenant's objects are laid out in its directory.
+type Format string
+
+// Supported formats.
+const (
+	// FormatKustomize writes one file per object and a kustomization.yaml listing them.
+	FormatKustomize Format = "kustomize"
+	// FormatHelm writes a values.yaml holding the tenant and its objects, for
+	// a chart that renders .Values.resources as they are.
+	FormatHelm Format = "helm"
+)
+
+// Files written to a tenant directory.
+const (
+	KustomizationFile = "kustomization.yaml"
+	ValuesFile        = "values.yaml"
+)
+
+// DefaultDir is the repository directory tenant directories are written under.
+const DefaultDir = "tenants"
+
+// Config configures an Exporter.
+type Config struct {
+	RepoPath    string // Working tree of an existing Git repository
+	Dir         string // Directory inside the repository holding <cluster>/<org>/ (default "tenants")
+	Format      Format // Layout of each tenant directory (default kustomize)
+	AuthorName  string // Commit author (default "account-provisioning-service")
+	AuthorEmail string
+	Push        bool   // Commit on top of origin and push every commit; unpushed commits are dropped
+	Sync        string // Controller to watch, "argocd:<namespace>/<application>" or "flux:<namespace>/<kustomization>"; empty treats a commit as synced
+}
+
+// Tenant is what a tenant directory is written for.
+type Tenant struct {
+	OrgID     string `json:"organizationId"`
+	Namespace string `json:"namespace"`
+	Cluster   string `json:"cluster"`
+	PlanTier  string `json:"planTier"`
+}
+
+// Exporter commits tenant directories to a Git repository. Commits are
+// serialized, so one Exporter must own the working tree.
+type Exporter struct {
+	mu     sync.Mutex
+	repo   *git.Repository
+	cfg    Config
+	source *Source // nil when no controller is watched
+}
+
+// NewExporter opens the repository at cfg.RepoPath.
+func NewExporter(cfg Config) (*Exporter, error) {
+	if cfg.RepoPath == "" {
+		return nil, fmt.Errorf("gitops repository path must not be empty")
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = DefaultDir
+	}
+	cfg.Dir = path.Clean(filepath.ToSlash(cfg.Dir))
+	if path.IsAbs(cfg.Dir) || cfg.Dir == ".." || strings.HasPrefix(cfg.Dir, "../") {
+		return nil, fmt.Errorf("gitops directory %q must be inside the repository", cfg.Dir)
+	}
+	if cfg.Format == "" {
+		cfg.Format = FormatKustomize
+	}
+	if cfg.Format != FormatKustomize && cfg.Format != FormatHelm {
+		return nil, fmt.Errorf("unknown gitops format %q (must be %s or %s)", cfg.Format, FormatKustomize, FormatHelm)
+	}
+	if cfg.AuthorName == "" {
+		cfg.AuthorName = "account-provisioning-service"
+	}
+
+	var source *Source
+	if cfg.Sync != "" {
+		parsed, err := ParseSource(cfg.Sync)
+		if err != nil {
+			return nil, err
+		}
+		if parsed.Kind == SourceFlux && cfg.Format != FormatKustomize {
+			return nil, fmt.Errorf("flux sync is tracked through a Kustomization and needs the %s format", FormatKustomize)
+		}
+		source = &parsed
+	}
+
+	repo, err := git.PlainOpen(cfg.RepoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gitops repository: %w", err)
+	}
+	return &Exporter{repo: repo, cfg: cfg, source: source}, nil
+}
+
+// Path returns the repository directory of a tenant.
+func (e *Exporter) Path(cluster, orgID string) string {
+	return path.Join(e.cfg.Dir, cluster, orgID)
+}
+
+// Export writes the tenant's directory and commits it, returning the commit
+// that holds it. Files of a previous export that objs no longer produce are
+// removed. If nothing changed, the current HEAD is returned.
+func (e *Exporter) Export(ctx context.Context, t Tenant, objs []runtime.Object) (string, error) {
+	files, err := e.render(t, objs)
+	if err != nil {
+		return "", err
+	}
+	return e.commit(ctx, e.Path(t.Cluster, t.OrgID), files, fmt.Sprintf("Update tenant %s (%s)", t.OrgID, t.PlanTier))
+}
+
+// Remove deletes the tenant's directory and commits the removal.
+func (e *Exporter) Remove(ctx context.Context, cluster, orgID string) (string, error) {
+	return e.commit(ctx, e.Path(cluster, orgID), nil, fmt.Sprintf("Remove tenant %s", orgID))
+}
+
+// render lays out objs in the configured format
+func (e *Exporter) render(t Tenant, objs []runtime.Object) (map[string][]byte, error) {
+	docs := make([]map[string]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		doc, err := manifest(obj)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	files := map[string][]byte{}
+	switch e.cfg.Format {
+	case FormatHelm:
+		data, err := yaml.Marshal(map[string]interface{}{
+			"tenant":    t,
+			"resources": docs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", ValuesFile, err)
+		}
+		files[ValuesFile] = data
+
+	default:
+		resources := make([]string, 0, len(docs))
+		for _, doc := range docs {
+			u := unstructured.Unstructured{Object: doc}
+			name := strings.ToLower(u.GetKind()) + "-" + u.GetName() + ".yaml"
+			if _, dup := files[name]; dup {
+				return nil, fmt.Errorf("two objects would be written to %s", name)
+			}
+			data, err := yaml.Marshal(doc)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+			}
+			files[name] = data
+			resources = append(resources, name)
+		}
+		data, err := yaml.Marshal(map[string]interface{}{
+			"apiVersion": "kustomize.config.k8s.io/v1beta1",
+			"kind":       "Kustomization",
+			"resources":  resources,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", KustomizationFile, err)
+		}
+		files[KustomizationFile] = data
+	}
+	return files, nil
+}
+
+// manifest converts obj to the document committed for it: apiVersion and
+// kind set, and no status or server-populated metadata
+func manifest(obj runtime.Object) (map[string]interface{}, error) {
+	if obj.GetObjectKind().GroupVersionKind().Empty() {
+		gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve kind of %T: %w", obj, err)
+		}
+		obj = obj.DeepCopyObject()
+		obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+	}
+	doc, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %T: %w", obj, err)
+	}
+	unstructured.RemoveNestedField(doc, "status")
+	unstructured.RemoveNestedField(doc, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(doc, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(doc, "metadata", "uid")
+	unstructured.RemoveNestedField(doc, "metadata", "managedFields")
+	return doc, nil
+}
+
+// pushAttempts is how often a commit is re-applied on top of upstream when
+// its push is rejected, e.g. because someone else pushed first
+const pushAttempts = 3
+
+// commit makes dir hold exactly files and commits the result. With Push, the
+// commit is made on top of the fetched upstream branch and dropped again if
+// it cannot be pushed, so a later push never publishes it.
+func (e *Exporter) commit(ctx context.Context, dir string, files map[string][]byte, message string) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	wt, err := e.repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to open worktree: %w", err)
+	}
+	if !e.cfg.Push {
+		return e.commitFiles(wt, dir, files, message)
+	}
+
+	head, err := e.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return "", fmt.Errorf("gitops repository must have a branch checked out to push")
+	}
+	branch := head.Name()
+
+	for attempt := 1; ; attempt++ {
+		base, err := e.pull(ctx, wt, branch)
+		if err != nil {
+			return "", err
+		}
+		revision, err := e.commitFiles(wt, dir, files, message)
+		if err != nil {
+			return "", errors.Join(err, e.reset(wt, base))
+		}
+
+		refspec := config.RefSpec(branch.String() + ":" + branch.String())
+		err = e.repo.PushContext(ctx, &git.PushOptions{RemoteName: git.DefaultRemoteName, RefSpecs: []config.RefSpec{refspec}})
+		if err == nil || errors.Is(err, git.NoErrAlreadyUpToDate) {
+			return revision, nil
+		}
+		if resetErr := e.reset(wt, base); resetErr != nil {
+			return "", errors.Join(fmt.Errorf("failed to push %s: %w", revision, err), resetErr)
+		}
+		if attempt == pushAttempts {
+			return "", fmt.Errorf("failed to push %s: %w", revision, err)
+		}
+	}
+}
+
+// pull fetches origin and moves branch to its upstream tip, dropping any
+// commit that was never pushed. Returns the new HEAD.
+func (e *Exporter) pull(ctx context.Context, wt *git.Worktree, branch plumbing.ReferenceName) (plumbing.Hash, error) {
+	err := e.repo.FetchContext(ctx, &git.FetchOptions{RemoteName: git.DefaultRemoteName})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return plumbing.ZeroHash, fmt.Errorf("failed to fetch: %w", err)
+	}
+
+	upstream, err := e.repo.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, branch.Short()), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		// The first push creates the branch upstream
+		head, err := e.repo.Head()
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to resolve HEAD: %w", err)
+		}
+		return head.Hash(), nil
+	}
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to resolve upstream of %s: %w", branch.Short(), err)
+	}
+	if err := e.reset(wt, upstream.Hash()); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return upstream.Hash(), nil
+}
+
+// reset hard-resets the checked-out branch, index and working tree to hash
+func (e *Exporter) reset(wt *git.Worktree, hash plumbing.Hash) error {
+	if err := wt.Reset(&git.ResetOptions{Commit: hash, Mode: git.HardReset}); err != nil {
+		return fmt.Errorf("failed to reset to %s: %w", hash, err)
+	}
+	return nil
+}
+
+// commitFiles makes dir hold exactly files and commits the change, if any.
+// Returns the new commit, or HEAD if nothing changed.
+func (e *Exporter) commitFiles(wt *git.Worktree, dir string, files map[string][]byte, message string) (string, error) {
+	abs := filepath.Join(wt.Filesystem.Root(), filepath.FromSlash(dir))
+
+	// Drop files the new export no longer produces
+	existing, err := os.ReadDir(abs)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	for _, entry := range existing {
+		if _, keep := files[entry.Name()]; keep || entry.IsDir() {
+			continue
+		}
+		if _, err := wt.Remove(path.Join(dir, entry.Name())); err != nil && !errors.Is(err, index.ErrEntryNotFound) {
+			return "", fmt.Errorf("failed to remove %s: %w", entry.Name(), err)
+		}
+		if err := os.Remove(filepath.Join(abs, entry.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to remove %s: %w", entry.Name(), err)
+		}
+	}
+
+	if len(files) == 0 {
+		if err := os.Remove(abs); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to remove %s: %w", dir, err)
+		}
+	} else if err := os.MkdirAll(abs, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(abs, name), data, 0o644); err != nil {
+			return "", fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if _, err := wt.Add(path.Join(dir, name)); err != nil {
+			return "", fmt.Errorf("failed to stage %s: %w", name, err)
+		}
+	}
+
+	changed, err := staged(wt, dir)
+	if err != nil {
+		return "", err
+	}
+	if !changed {
+		head, err := e.repo.Head()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve HEAD: %w", err)
+		}
+		return head.Hash().String(), nil
+	}
+	hash, err := wt.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: e.cfg.AuthorName, Email: e.cfg.AuthorEmail, When: time.Now()},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to commit %s: %w", dir, err)
+	}
+	return hash.String(), nil
+}
+
+// staged reports whether the index holds changes under dir
+func staged(wt *git.Worktree, dir string) (bool, error) {
+	status, err := wt.Status()
+	if err != nil {
+		return false, fmt.Errorf("failed to read worktree status: %w", err)
+	}
+	for file, s := range status {
+		if !strings.HasPrefix(file, dir+"/") {
+			continue
+		}
+		if s.Staging != git.Unmodified && s.Staging != git.Untracked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,155 @@
+package gitops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Controllers whose sync can be watched.
+const (
+	SourceArgoCD = "argocd"
+	SourceFlux   = "flux"
+)
+
+// ClusterPlaceholder in a source name is replaced with the tenant's cluster,
+// for setups with one Application or Kustomization per cluster.
+const ClusterPlaceholder = "{cluster}"
+
+var (
+	argoApplications   = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+	fluxKustomizations = schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}
+)
+
+// Source is the Argo CD Application or Flux Kustomization that applies the
+// tenant directories to a cluster. It is read on the tenant's cluster.
+type Source struct {
+	Kind      string // SourceArgoCD or SourceFlux
+	Namespace string
+	Name      string // May contain ClusterPlaceholder
+}
+
+// ParseSource parses "argocd:<namespace>/<application>" or
+// "flux:<namespace>/<kustomization>".
+func ParseSource(s string) (Source, error) {
+	kind, ref, ok := strings.Cut(s, ":")
+	namespace, name, hasName := strings.Cut(ref, "/")
+	if !ok || !hasName || namespace == "" || name == "" {
+		return Source{}, fmt.Errorf("invalid gitops sync source %q (want argocd:<namespace>/<name> or flux:<namespace>/<name>)", s)
+	}
+	if kind != SourceArgoCD && kind != SourceFlux {
+		return Source{}, fmt.Errorf("unknown gitops sync controller %q (must be %s or %s)", kind, SourceArgoCD, SourceFlux)
+	}
+	return Source{Kind: kind, Namespace: namespace, Name: name}, nil
+}
+
+// Synced reports whether the controller on cluster has applied revision, or
+// a later commit that contains it, and found the result healthy. Without a
+// configured source every commit counts as synced.
+func (e *Exporter) Synced(ctx context.Context, dyn dynamic.Interface, cluster, revision string) (bool, error) {
+	if e.source == nil {
+		return true, nil
+	}
+	name := strings.ReplaceAll(e.source.Name, ClusterPlaceholder, cluster)
+
+	var applied string
+	var err error
+	switch e.source.Kind {
+	case SourceArgoCD:
+		applied, err = argoRevision(ctx, dyn, e.source.Namespace, name)
+	case SourceFlux:
+		applied, err = fluxRevision(ctx, dyn, e.source.Namespace, name)
+	}
+	if err != nil || applied == "" {
+		return false, err
+	}
+	return e.contains(applied, revision)
+}
+
+// argoRevision returns the revision of a synced and healthy Application
+func argoRevision(ctx context.Context, dyn dynamic.Interface, namespace, name string) (string, error) {
+	app, err := getSource(ctx, dyn, argoApplications, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	syncStatus, _, _ := unstructured.NestedString(app.Object, "status", "sync", "status")
+	health, _, _ := unstructured.NestedString(app.Object, "status", "health", "status")
+	if syncStatus != "Synced" || health != "Healthy" {
+		return "", nil
+	}
+	revision, _, _ := unstructured.NestedString(app.Object, "status", "sync", "revision")
+	return revision, nil
+}
+
+// fluxRevision returns the last applied revision of a ready Kustomization
+func fluxRevision(ctx context.Context, dyn dynamic.Interface, namespace, name string) (string, error) {
+	ks, err := getSource(ctx, dyn, fluxKustomizations, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	conditions, _, _ := unstructured.NestedSlice(ks.Object, "status", "conditions")
+	ready := false
+	for _, c := range conditions {
+		cond, _ := c.(map[string]interface{})
+		if cond["type"] == "Ready" && cond["status"] == "True" {
+			ready = true
+		}
+	}
+	if !ready {
+		return "", nil
+	}
+	revision, _, _ := unstructured.NestedString(ks.Object, "status", "lastAppliedRevision")
+	return parseFluxRevision(revision), nil
+}
+
+// parseFluxRevision returns the commit of a Flux revision, which is
+// "<branch>@sha1:<hash>" or, before Flux 2.0, "<branch>/<hash>"
+func parseFluxRevision(revision string) string {
+	if _, hash, ok := strings.Cut(revision, "sha1:"); ok {
+		return hash
+	}
+	return revision[strings.LastIndex(revision, "/")+1:]
+}
+
+func getSource(ctx context.Context, dyn dynamic.Interface, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	obj, err := dyn.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("gitops sync source %s %s/%s not found", gvr.Resource, namespace, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", gvr.Resource, namespace, name, err)
+	}
+	return obj, nil
+}
+
+// contains reports whether applied is revision or one of its descendants. A
+// commit that is not in the local repository has not been pulled yet and is
+// treated as not containing revision.
+func (e *Exporter) contains(applied, revision string) (bool, error) {
+	if applied == revision {
+		return true, nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ours, err := e.repo.CommitObject(plumbing.NewHash(revision))
+	if err != nil {
+		return false, fmt.Errorf("failed to read commit %s: %w", revision, err)
+	}
+	theirs, err := e.repo.CommitObject(plumbing.NewHash(applied))
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read commit %s: %w", applied, err)
+	}
+	return ours.IsAncestor(theirs)
+}
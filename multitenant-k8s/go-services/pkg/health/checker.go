@@ -0,0 +1,151 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Probe statuses reported per dependency.
+const (
+	StatusOK       = "ok"
+	StatusFailed   = "failed"
+	StatusDegraded = "degraded" // An optional dependency failed; the server stays ready
+)
+
+// Overall readiness.
+const (
+	StatusReady       = "ready"
+	StatusUnavailable = "unavailable"
+)
+
+// Probe checks one dependency of a server.
+type Probe struct {
+	Name     string
+	Check    func(ctx context.Context) error
+	Optional bool // Failures are reported but do not make the server unready
+}
+
+// Result is the outcome of one probe.
+type Result struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+}
+
+// Report is the readiness of a server and the detail of every probe.
+type Report struct {
+	Status    string    `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+	Checks    []Result  `json:"checks"`
+}
+
+// Ready reports whether every required probe passed.
+func (r Report) Ready() bool {
+	return r.Status == StatusReady
+}
+
+// Checker runs the probes of a server and caches the report briefly so that
+// frequent kubelet and load balancer checks do not hammer the dependencies.
+type Checker struct {
+	probes   []Probe
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu     sync.Mutex
+	last   Report
+	lastAt time.Time
+}
+
+// NewChecker creates a checker that gives each probe up to timeout.
+func NewChecker(timeout time.Duration, probes ...Probe) (*Checker, error) {
+	if timeout <= 0 {
+		return nil, errors.New("timeout must be positive")
+	}
+	for _, p := range probes {
+		if p.Name == "" || p.Check == nil {
+			return nil, errors.New("probes must have a name and a check")
+		}
+	}
+	return &Checker{probes: probes, timeout: timeout, cacheTTL: time.Second}, nil
+}
+
+// Check runs every probe concurrently, or returns the report from the last
+// second.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.lastAt.IsZero() && time.Since(c.lastAt) < c.cacheTTL {
+		return c.last
+	}
+
+	results := make([]Result, len(c.probes))
+	var wg sync.WaitGroup
+	for i, p := range c.probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, p)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusReady, CheckedAt: time.Now().UTC(), Checks: results}
+	for _, r := range results {
+		if r.Status == StatusFailed {
+			report.Status = StatusUnavailable
+		}
+	}
+	c.last, c.lastAt = report, time.Now()
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, p Probe) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := p.Check(ctx)
+	result := Result{Name: p.Name, Status: StatusOK, LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status, result.Error = StatusFailed, err.Error()
+		if p.Optional {
+			result.Status = StatusDegraded
+		}
+	}
+	return result
+}
+
+// Register mounts /healthz, /readyz, grpc.health.v1.Health and the
+// HealthService from scheduler.proto on mux. services are the fully-qualified
+// service names the server exposes, for per-service gRPC health checks.
+// None of these endpoints require authentication.
+func (c *Checker) Register(mux *http.ServeMux, services ...string) {
+	// Liveness only says the process is serving; dependencies do not matter
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		report := c.Check(r.Context())
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	})
+
+	for path, handler := range c.grpcHandlers(services) {
+		mux.Handle(path, handler)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
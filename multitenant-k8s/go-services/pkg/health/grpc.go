@@ -0,0 +1,100 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	schedv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/mcp-scheduler/v1"
+	schedconnect "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/mcp-scheduler/v1/mcpschedulerv1connect"
+)
+
+// Procedures of the standard gRPC health service (grpc.health.v1.Health).
+const (
+	grpcHealthCheckProcedure = "/grpc.health.v1.Health/Check"
+	grpcHealthWatchProcedure = "/grpc.health.v1.Health/Watch"
+)
+
+// watchInterval is how often Watch re-checks readiness
+const watchInterval = 5 * time.Second
+
+// grpcHandlers returns the Connect handlers for grpc.health.v1.Health and
+// schedulerapi.v1.HealthService, keyed by path
+func (c *Checker) grpcHandlers(services []string) map[string]http.Handler {
+	known := map[string]bool{"": true}
+	for _, s := range services {
+		known[s] = true
+	}
+
+	// status maps readiness to the gRPC status of a service; ok is false for
+	// services this server does not expose
+	status := func(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, bool) {
+		if !known[service] {
+			return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, false
+		}
+		if c.Check(ctx).Ready() {
+			return healthpb.HealthCheckResponse_SERVING, true
+		}
+		return healthpb.HealthCheckResponse_NOT_SERVING, true
+	}
+
+	handlers := map[string]http.Handler{}
+	handlers[grpcHealthCheckProcedure] = connect.NewUnaryHandler(grpcHealthCheckProcedure,
+		func(ctx context.Context, req *connect.Request[healthpb.HealthCheckRequest]) (*connect.Response[healthpb.HealthCheckResponse], error) {
+			s, ok := status(ctx, req.Msg.GetService())
+			if !ok {
+				return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("unknown service %q", req.Msg.GetService()))
+			}
+			return connect.NewResponse(&healthpb.HealthCheckResponse{Status: s}), nil
+		},
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+	)
+	handlers[grpcHealthWatchProcedure] = connect.NewServerStreamHandler(grpcHealthWatchProcedure,
+		func(ctx context.Context, req *connect.Request[healthpb.HealthCheckRequest], stream *connect.ServerStream[healthpb.HealthCheckResponse]) error {
+			ticker := time.NewTicker(watchInterval)
+			defer ticker.Stop()
+
+			// Send the current status, then only changes, until the client goes away
+			last := healthpb.HealthCheckResponse_ServingStatus(-1)
+			for {
+				s, _ := status(ctx, req.Msg.GetService())
+				if s != last {
+					if err := stream.Send(&healthpb.HealthCheckResponse{Status: s}); err != nil {
+						return err
+					}
+					last = s
+				}
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		},
+	)
+
+	path, handler := schedconnect.NewHealthServiceHandler(&healthService{checker: c, known: known})
+	handlers[path] = handler
+	return handlers
+}
+
+// healthService implements schedulerapi.v1.HealthService on top of the checker
+type healthService struct {
+	checker *Checker
+	known   map[string]bool
+}
+
+func (h *healthService) Check(ctx context.Context, req *connect.Request[schedv1.CheckRequest]) (*connect.Response[schedv1.CheckResponse], error) {
+	if !h.known[req.Msg.GetService()] {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("unknown service %q", req.Msg.GetService()))
+	}
+	status := schedv1.CheckResponse_SERVING_STATUS_NOT_SERVING
+	if h.checker.Check(ctx).Ready() {
+		status = schedv1.CheckResponse_SERVING_STATUS_SERVING
+	}
+	return connect.NewResponse(&schedv1.CheckResponse{Status: status}), nil
+}
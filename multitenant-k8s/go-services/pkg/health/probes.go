@@ -0,0 +1,87 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+	"k8s.io/client-go/kubernetes"
+)
+
+// KubernetesProbe checks the API server's own /readyz endpoint.
+func KubernetesProbe(name string, kc kubernetes.Interface) Probe {
+	return Probe{
+		Name: name,
+		Check: func(ctx context.Context) error {
+			_, err := kc.Discovery().RESTClient().Get().AbsPath("/readyz").DoRaw(ctx)
+			return err
+		},
+	}
+}
+
+// IAMProbe checks that AWS credentials are valid with STS GetCallerIdentity,
+// which needs no IAM permissions.
+func IAMProbe(cfg aws.Config) Probe {
+	client := sts.NewFromConfig(cfg)
+	return Probe{
+		Name: "iam",
+		Check: func(ctx context.Context) error {
+			_, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+			return err
+		},
+	}
+}
+
+// RedisProbe pings Redis.
+func RedisProbe(client *redis.Client) Probe {
+	return Probe{
+		Name: "redis",
+		Check: func(ctx context.Context) error {
+			return client.Ping(ctx).Err()
+		},
+	}
+}
+
+// KafkaProbe fetches cluster metadata from the first reachable broker and,
+// when topic is set, checks that the topic has partitions.
+func KafkaProbe(brokers []string, topic string) Probe {
+	return Probe{
+		Name: "kafka",
+		Check: func(ctx context.Context) error {
+			if len(brokers) == 0 {
+				return errors.New("no brokers configured")
+			}
+			var dialer kafka.Dialer
+			var lastErr error
+			for _, broker := range brokers {
+				conn, err := dialer.DialContext(ctx, "tcp", broker)
+				if err != nil {
+					lastErr = err
+					continue
+				}
+				defer conn.Close()
+				if deadline, ok := ctx.Deadline(); ok {
+					conn.SetDeadline(deadline)
+				}
+
+				if topic == "" {
+					_, err = conn.Brokers()
+					return err
+				}
+				partitions, err := conn.ReadPartitions(topic)
+				if err != nil {
+					return fmt.Errorf("failed to read metadata of topic %s: %w", topic, err)
+				}
+				if len(partitions) == 0 {
+					return fmt.Errorf("topic %s has no partitions", topic)
+				}
+				return nil
+			}
+			return fmt.Errorf("no broker reachable: %w", lastErr)
+		},
+	}
+}
@@ -0,0 +1,159 @@
+package identity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GroupSync keeps identity provider groups in step with tenant membership, so
+// the groups (and the org and role claims the IdP derives from them) match the
+// members recorded by the account service.
+type GroupSync interface {
+	// SyncGroup makes the group's members exactly the users with these emails,
+	// creating the group if needed.
+	SyncGroup(ctx context.Context, group string, emails []string) error
+}
+
+// SCIM schema URNs.
+const (
+	scimGroupSchema = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimPatchSchema = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+)
+
+// ErrUnknownUsers is returned when some members have no user in the IdP yet.
+// The group is still synced with the users that exist.
+var ErrUnknownUsers = errors.New("users not found in identity provider")
+
+// SCIMClient syncs groups through a SCIM 2.0 endpoint (RFC 7644), such as AWS
+// IAM Identity Center, Entra ID or Keycloak with a SCIM extension. Users are
+// matched on userName, which must be their email.
+type SCIMClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewSCIMClient creates a client for the SCIM endpoint at baseURL that
+// authenticates with a bearer token.
+func NewSCIMClient(baseURL, token string) (*SCIMClient, error) {
+	if baseURL == "" {
+		return nil, errors.New("scim base url must not be empty")
+	}
+	if token == "" {
+		return nil, errors.New("scim token must not be empty")
+	}
+	return &SCIMClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+type scimResource struct {
+	ID string `json:"id"`
+}
+
+type scimList struct {
+	Resources []scimResource `json:"Resources"`
+}
+
+// SyncGroup implements GroupSync.
+func (c *SCIMClient) SyncGroup(ctx context.Context, group string, emails []string) error {
+	groupID, err := c.find(ctx, "Groups", "displayName", group)
+	if err != nil {
+		return err
+	}
+	if groupID == "" {
+		var created scimResource
+		body := map[string]any{"schemas": []string{scimGroupSchema}, "displayName": group}
+		if err := c.do(ctx, http.MethodPost, "/Groups", body, &created); err != nil {
+			return fmt.Errorf("failed to create group %s: %w", group, err)
+		}
+		groupID = created.ID
+	}
+
+	members := []map[string]string{}
+	var unknown []string
+	for _, email := range emails {
+		userID, err := c.find(ctx, "Users", "userName", email)
+		if err != nil {
+			return err
+		}
+		if userID == "" {
+			unknown = append(unknown, email)
+			continue
+		}
+		members = append(members, map[string]string{"value": userID})
+	}
+
+	patch := map[string]any{
+		"schemas": []string{scimPatchSchema},
+		"Operations": []map[string]any{{
+			"op":    "replace",
+			"path":  "members",
+			"value": members,
+		}},
+	}
+	if err := c.do(ctx, http.MethodPatch, "/Groups/"+url.PathEscape(groupID), patch, nil); err != nil {
+		return fmt.Errorf("failed to update members of group %s: %w", group, err)
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownUsers, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// find returns the ID of the resource whose attribute equals value, or "" if none
+func (c *SCIMClient) find(ctx context.Context, resource, attribute, value string) (string, error) {
+	filter := fmt.Sprintf("%s eq %q", attribute, value)
+	var list scimList
+	if err := c.do(ctx, http.MethodGet, "/"+resource+"?filter="+url.QueryEscape(filter), nil, &list); err != nil {
+		return "", fmt.Errorf("failed to look up %s %s: %w", strings.ToLower(strings.TrimSuffix(resource, "s")), value, err)
+	}
+	if len(list.Resources) == 0 {
+		return "", nil
+	}
+	return list.Resources[0].ID, nil
+}
+
+func (c *SCIMClient) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/scim+json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/scim+json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("scim %s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,328 @@
+package ingress
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// Names of the objects created in each tenant namespace.
+const (
+	ReferenceGrantName = "tenant-ingress"
+	RouteQuotaName     = "tenant-routes"
+)
+
+// VerificationPrefix is prepended to a custom domain to form the name of the
+// TXT record that proves ownership of it.
+const VerificationPrefix = "_tenant-verification."
+
+var (
+	gatewayGVR        = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}
+	referenceGrantGVR = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "referencegrants"}
+	certificateGVR    = schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+)
+
+// RouteQuotaResource is the object count quota key for HTTPRoutes.
+const RouteQuotaResource = "count/httproutes.gateway.networking.k8s.io"
+
+// routes maps plan tier names (proto enum names) to how many HTTPRoutes a
+// tenant namespace may hold.
+var routes = map[string]int64{
+	"PLAN_TIER_FREE":       5,
+	"PLAN_TIER_STARTER":    20,
+	"PLAN_TIER_PRO":        50,
+	"PLAN_TIER_ENTERPRISE": 200,
+}
+
+// RoutesForTier returns the HTTPRoute quota for a plan tier name such as
+// "PLAN_TIER_PRO".
+func RoutesForTier(tier string) (int64, bool) {
+	n, ok := routes[tier]
+	return n, ok
+}
+
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z][a-z0-9-]{0,62}$`)
+
+// Config describes where tenant hostnames live and how they are served.
+type Config struct {
+	Domain             string // Tenants get <org>.apps.<Domain>
+	GatewayNamespace   string // Namespace of the shared Gateway that gets a listener per hostname
+	GatewayName        string
+	Issuer             string // ClusterIssuer for tenant wildcards; must solve DNS-01
+	CustomDomainIssuer string // ClusterIssuer for custom domains (default Issuer)
+}
+
+// Site is a set of hostnames served for a tenant under one certificate: a
+// Gateway listener per hostname, each terminating TLS with the certificate in
+// SecretName.
+type Site struct {
+	Listener   string // Name of the first hostname's listener; later ones get .1, .2, ...
+	Hostnames  []string
+	SecretName string
+	Issuer     string
+}
+
+// Manager renders tenant sites into the shared Gateway and tenant namespaces.
+type Manager struct {
+	cfg      Config
+	resolver *net.Resolver
+}
+
+// NewManager creates a manager that verifies domains with the system resolver.
+func NewManager(cfg Config) (*Manager, error) {
+	cfg.Domain = strings.TrimSuffix(strings.ToLower(cfg.Domain), ".")
+	if !domainPattern.MatchString(cfg.Domain) {
+		return nil, fmt.Errorf("invalid ingress domain %q", cfg.Domain)
+	}
+	if cfg.GatewayNamespace == "" || cfg.GatewayName == "" {
+		return nil, errors.New("gateway namespace and name must not be empty")
+	}
+	if cfg.Issuer == "" {
+		return nil, errors.New("issuer must not be empty")
+	}
+	if cfg.CustomDomainIssuer == "" {
+		cfg.CustomDomainIssuer = cfg.Issuer
+	}
+	return &Manager{cfg: cfg, resolver: net.DefaultResolver}, nil
+}
+
+// Hostname returns the subdomain of an organization, <org>.apps.<domain>.
+func (m *Manager) Hostname(orgID string) string {
+	return fmt.Sprintf("%s.apps.%s", orgID, m.cfg.Domain)
+}
+
+// TenantSite serves the organization's subdomain and every name under it. A
+// wildcard listener does not match the subdomain itself, so it gets a listener
+// of its own.
+func (m *Manager) TenantSite(orgID string) Site {
+	return Site{
+		Listener:   listenerPrefix(orgID) + "apps",
+		Hostnames:  []string{"*." + m.Hostname(orgID), m.Hostname(orgID)},
+		SecretName: "tenant-ingress-tls",
+		Issuer:     m.cfg.Issuer,
+	}
+}
+
+// DomainSite serves a verified custom domain of the organization.
+func (m *Manager) DomainSite(orgID, domain string) Site {
+	sum := sha256.Sum256([]byte(domain))
+	suffix := hex.EncodeToString(sum[:4])
+	return Site{
+		Listener:   listenerPrefix(orgID) + suffix,
+		Hostnames:  []string{domain},
+		SecretName: "tenant-domain-" + suffix,
+		Issuer:     m.cfg.CustomDomainIssuer,
+	}
+}
+
+// listenerPrefix starts every listener name of an organization. Namespace
+// names cannot contain dots, so no organization's prefix extends another's.
+func listenerPrefix(orgID string) string {
+	return "tenant." + orgID + "."
+}
+
+// NormalizeDomain lower-cases a custom domain and rejects malformed ones and
+// names under the platform's own domain.
+func (m *Manager) NormalizeDomain(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if !domainPattern.MatchString(domain) {
+		return "", fmt.Errorf("invalid domain %q", domain)
+	}
+	if domain == m.cfg.Domain || strings.HasSuffix(domain, "."+m.cfg.Domain) {
+		return "", fmt.Errorf("domain %q is under the platform domain", domain)
+	}
+	return domain, nil
+}
+
+// Verify reports whether domain has a TXT record carrying token.
+func (m *Manager) Verify(ctx context.Context, domain, token string) (bool, error) {
+	records, err := m.resolver.LookupTXT(ctx, VerificationPrefix+domain)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to look up verification record of %s: %w", domain, err)
+	}
+	for _, r := range records {
+		if strings.TrimSpace(r) == token {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Apply makes the Gateway serve exactly sites for the tenant: each site gets a
+// Certificate in namespace and a listener that only accepts routes from that
+// namespace. Listeners of sites no longer wanted are removed.
+func (m *Manager) Apply(ctx context.Context, dyn dynamic.Interface, orgID, namespace string, sites []Site) error {
+	for _, site := range sites {
+		if err := applyUnstructured(ctx, dyn, certificateGVR, certificate(namespace, orgID, site)); err != nil {
+			return err
+		}
+	}
+	if err := applyUnstructured(ctx, dyn, referenceGrantGVR, referenceGrant(namespace, orgID, m.cfg.GatewayNamespace, sites)); err != nil {
+		return err
+	}
+
+	var listeners []interface{}
+	for _, site := range sites {
+		for i, hostname := range site.Hostnames {
+			name := site.Listener
+			if i > 0 {
+				name = fmt.Sprintf("%s.%d", site.Listener, i)
+			}
+			listeners = append(listeners, listener(namespace, name, hostname, site.SecretName))
+		}
+	}
+	return m.updateListeners(ctx, dyn, orgID, listeners)
+}
+
+// Remove deletes the tenant's listeners from the Gateway. Certificates and
+// the ReferenceGrant go with the tenant namespace.
+func (m *Manager) Remove(ctx context.Context, dyn dynamic.Interface, orgID string) error {
+	return m.updateListeners(ctx, dyn, orgID, nil)
+}
+
+// updateListeners replaces the tenant's listeners on the Gateway, leaving
+// every other listener as it is
+func (m *Manager) updateListeners(ctx context.Context, dyn dynamic.Interface, orgID string, tenant []interface{}) error {
+	client := dyn.Resource(gatewayGVR).Namespace(m.cfg.GatewayNamespace)
+	prefix := listenerPrefix(orgID)
+
+	// Every tenant edits the same Gateway, so conflicts are expected
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		gateway, err := client.Get(ctx, m.cfg.GatewayName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get gateway %s/%s: %w", m.cfg.GatewayNamespace, m.cfg.GatewayName, err)
+		}
+		existing, _, err := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+		if err != nil {
+			return fmt.Errorf("failed to read listeners of gateway %s: %w", m.cfg.GatewayName, err)
+		}
+
+		var listeners []interface{}
+		for _, l := range existing {
+			name, _ := l.(map[string]interface{})["name"].(string)
+			if !strings.HasPrefix(name, prefix) {
+				listeners = append(listeners, l)
+			}
+		}
+		if len(listeners) == len(existing) && len(tenant) == 0 {
+			return nil
+		}
+		listeners = append(listeners, tenant...)
+
+		if err := unstructured.SetNestedSlice(gateway.Object, listeners, "spec", "listeners"); err != nil {
+			return err
+		}
+		_, err = client.Update(ctx, gateway, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func listener(namespace, name, hostname, secretName string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":     name,
+		"hostname": hostname,
+		"port":     int64(443),
+		"protocol": "HTTPS",
+		"tls": map[string]interface{}{
+			"mode": "Terminate",
+			"certificateRefs": []interface{}{
+				map[string]interface{}{"kind": "Secret", "name": secretName, "namespace": namespace},
+			},
+		},
+		"allowedRoutes": map[string]interface{}{
+			"kinds": []interface{}{map[string]interface{}{"kind": "HTTPRoute"}},
+			"namespaces": map[string]interface{}{
+				"from": "Selector",
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"kubernetes.io/metadata.name": namespace},
+				},
+			},
+		},
+	}
+}
+
+func certificate(namespace, orgID string, site Site) *unstructured.Unstructured {
+	dnsNames := make([]interface{}, len(site.Hostnames))
+	for i, hostname := range site.Hostnames {
+		dnsNames[i] = hostname
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cert-manager.io/v1",
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name":      site.SecretName,
+			"namespace": namespace,
+			"labels":    map[string]interface{}{"tenant-id": orgID},
+		},
+		"spec": map[string]interface{}{
+			"secretName": site.SecretName,
+			"dnsNames":   dnsNames,
+			"issuerRef": map[string]interface{}{
+				"kind": "ClusterIssuer",
+				"name": site.Issuer,
+			},
+		},
+	}}
+}
+
+// referenceGrant lets the Gateway read the tenant's certificate secrets and
+// nothing else in the namespace
+func referenceGrant(namespace, orgID, gatewayNamespace string, sites []Site) *unstructured.Unstructured {
+	var to []interface{}
+	for _, site := range sites {
+		to = append(to, map[string]interface{}{"group": "", "kind": "Secret", "name": site.SecretName})
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1beta1",
+		"kind":       "ReferenceGrant",
+		"metadata": map[string]interface{}{
+			"name":      ReferenceGrantName,
+			"namespace": namespace,
+			"labels":    map[string]interface{}{"tenant-id": orgID},
+		},
+		"spec": map[string]interface{}{
+			"from": []interface{}{
+				map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "Gateway", "namespace": gatewayNamespace},
+			},
+			"to": to,
+		},
+	}}
+}
+
+// applyUnstructured creates obj or replaces the existing object of the same name
+func applyUnstructured(ctx context.Context, dyn dynamic.Interface, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	client := dyn.Resource(gvr).Namespace(obj.GetNamespace())
+
+	existing, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err := client.Create(ctx, obj, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create %s %s: %w", gvr.Resource, obj.GetName(), err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s: %w", gvr.Resource, obj.GetName(), err)
+	}
+
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	if _, err := client.Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update %s %s: %w", gvr.Resource, obj.GetName(), err)
+	}
+	return nil
+}
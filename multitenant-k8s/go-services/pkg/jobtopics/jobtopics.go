@@ -0,0 +1,222 @@
+package jobtopics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// TopicPrefix starts the name of every tenant-dedicated job topic.
+const TopicPrefix = "mcp-jobs."
+
+// partitions maps plan tier names (proto enum names) to the partition count of
+// a dedicated topic. Higher tiers can run more consumers in parallel.
+var partitions = map[string]int{
+	"PLAN_TIER_FREE":       1,
+	"PLAN_TIER_STARTER":    3,
+	"PLAN_TIER_PRO":        6,
+	"PLAN_TIER_ENTERPRISE": 12,
+}
+
+// PartitionsForTier returns the partition count for a plan tier name such as
+// "PLAN_TIER_PRO".
+func PartitionsForTier(tier string) (int, bool) {
+	n, ok := partitions[tier]
+	return n, ok
+}
+
+// TopicName returns the dedicated job topic of an organization.
+func TopicName(orgID string) string {
+	return TopicPrefix + orgID
+}
+
+// Admin creates and removes tenant job topics and the ACLs that let each
+// tenant's principal consume only its own topic.
+type Admin struct {
+	client            *kafka.Client
+	replicationFactor int
+	principalPrefix   string
+}
+
+// NewAdmin creates an Admin for the cluster at brokers. Tenant principals are
+// principalPrefix followed by the organization ID, e.g. "User:tenant-acme".
+func NewAdmin(brokers []string, replicationFactor int, principalPrefix string) (*Admin, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("brokers must not be empty")
+	}
+	if replicationFactor <= 0 {
+		return nil, errors.New("replication factor must be positive")
+	}
+	if principalPrefix == "" {
+		return nil, errors.New("principal prefix must not be empty")
+	}
+	return &Admin{
+		client: &kafka.Client{
+			Addr:    kafka.TCP(brokers...),
+			Timeout: 10 * time.Second,
+		},
+		replicationFactor: replicationFactor,
+		principalPrefix:   principalPrefix,
+	}, nil
+}
+
+// Principal returns the Kafka principal of an organization.
+func (a *Admin) Principal(orgID string) string {
+	return a.principalPrefix + orgID
+}
+
+// CreateTopic creates a topic with n partitions. An existing topic is grown
+// to n partitions instead.
+func (a *Admin) CreateTopic(ctx context.Context, topic string, n int) error {
+	resp, err := a.client.CreateTopics(ctx, &kafka.CreateTopicsRequest{
+		Topics: []kafka.TopicConfig{{
+			Topic:             topic,
+			NumPartitions:     n,
+			ReplicationFactor: a.replicationFactor,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create topic %s: %w", topic, err)
+	}
+	if err := resp.Errors[topic]; err != nil {
+		if errors.Is(err, kafka.TopicAlreadyExists) {
+			return a.GrowTopic(ctx, topic, n)
+		}
+		return fmt.Errorf("failed to create topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+// GrowTopic raises the partition count of topic to n. Kafka cannot remove
+// partitions, so topics that already have n or more are left alone.
+func (a *Admin) GrowTopic(ctx context.Context, topic string, n int) error {
+	current, err := a.partitionCount(ctx, topic)
+	if err != nil {
+		return err
+	}
+	if current >= n {
+		return nil
+	}
+
+	resp, err := a.client.CreatePartitions(ctx, &kafka.CreatePartitionsRequest{
+		Topics: []kafka.TopicPartitionsConfig{{Name: topic, Count: int32(n)}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add partitions to topic %s: %w", topic, err)
+	}
+	if err := resp.Errors[topic]; err != nil {
+		return fmt.Errorf("failed to add partitions to topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+func (a *Admin) partitionCount(ctx context.Context, topic string) (int, error) {
+	resp, err := a.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read metadata of topic %s: %w", topic, err)
+	}
+	for _, t := range resp.Topics {
+		if t.Name != topic {
+			continue
+		}
+		if t.Error != nil {
+			return 0, fmt.Errorf("failed to read metadata of topic %s: %w", topic, t.Error)
+		}
+		return len(t.Partitions), nil
+	}
+	return 0, fmt.Errorf("topic %s not found", topic)
+}
+
+// DeleteTopic deletes a topic. Topics that do not exist are ignored.
+func (a *Admin) DeleteTopic(ctx context.Context, topic string) error {
+	resp, err := a.client.DeleteTopics(ctx, &kafka.DeleteTopicsRequest{Topics: []string{topic}})
+	if err != nil {
+		return fmt.Errorf("failed to delete topic %s: %w", topic, err)
+	}
+	if err := resp.Errors[topic]; err != nil && !errors.Is(err, kafka.UnknownTopicOrPartition) {
+		return fmt.Errorf("failed to delete topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+// GroupPrefix is the prefix of the consumer groups a tenant may use on topic.
+// The trailing dot keeps the prefix of mcp-jobs.acme from also matching the
+// groups of mcp-jobs.acmecorp.
+func GroupPrefix(topic string) string {
+	return topic + "."
+}
+
+// tenantACLs are the grants of an organization's principal: consuming its
+// topic, with consumer groups under GroupPrefix.
+func (a *Admin) tenantACLs(orgID, topic string) []kafka.ACLEntry {
+	principal := a.Principal(orgID)
+	acl := func(resourceType kafka.ResourceType, pattern kafka.PatternType, name string, op kafka.ACLOperationType) kafka.ACLEntry {
+		return kafka.ACLEntry{
+			ResourceType:        resourceType,
+			ResourceName:        name,
+			ResourcePatternType: pattern,
+			Principal:           principal,
+			Host:                "*",
+			Operation:           op,
+			PermissionType:      kafka.ACLPermissionTypeAllow,
+		}
+	}
+	return []kafka.ACLEntry{
+		acl(kafka.ResourceTypeTopic, kafka.PatternTypeLiteral, topic, kafka.ACLOperationTypeRead),
+		acl(kafka.ResourceTypeTopic, kafka.PatternTypeLiteral, topic, kafka.ACLOperationTypeDescribe),
+		acl(kafka.ResourceTypeGroup, kafka.PatternTypePrefixed, GroupPrefix(topic), kafka.ACLOperationTypeRead),
+	}
+}
+
+// GrantTopic lets the organization's principal consume topic. Granting twice
+// is harmless.
+func (a *Admin) GrantTopic(ctx context.Context, orgID, topic string) error {
+	resp, err := a.client.CreateACLs(ctx, &kafka.CreateACLsRequest{ACLs: a.tenantACLs(orgID, topic)})
+	if err != nil {
+		return fmt.Errorf("failed to create ACLs for topic %s: %w", topic, err)
+	}
+	if err := errors.Join(resp.Errors...); err != nil {
+		return fmt.Errorf("failed to create ACLs for topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+// RevokeTopic removes every ACL GrantTopic created, including the group grant
+// on the bare topic name that earlier versions made.
+func (a *Admin) RevokeTopic(ctx context.Context, orgID, topic string) error {
+	acls := a.tenantACLs(orgID, topic)
+	for _, acl := range acls {
+		if acl.ResourceType == kafka.ResourceTypeGroup {
+			acl.ResourceName = topic
+			acls = append(acls, acl)
+		}
+	}
+	var filters []kafka.DeleteACLsFilter
+	for _, acl := range acls {
+		filters = append(filters, kafka.DeleteACLsFilter{
+			ResourceTypeFilter:        acl.ResourceType,
+			ResourceNameFilter:        acl.ResourceName,
+			ResourcePatternTypeFilter: acl.ResourcePatternType,
+			PrincipalFilter:           acl.Principal,
+			HostFilter:                acl.Host,
+			Operation:                 acl.Operation,
+			PermissionType:            acl.PermissionType,
+		})
+	}
+
+	resp, err := a.client.DeleteACLs(ctx, &kafka.DeleteACLsRequest{Filters: filters})
+	if err != nil {
+		return fmt.Errorf("failed to delete ACLs for topic %s: %w", topic, err)
+	}
+	var errs []error
+	for _, result := range resp.Results {
+		errs = append(errs, result.Error)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to delete ACLs for topic %s: %w", topic, err)
+	}
+	return nil
+}
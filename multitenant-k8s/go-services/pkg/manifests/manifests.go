@@ -0,0 +1,225 @@
+// Package manifests renders the tenant manifests under tenant/ into typed
+// Kubernetes objects. The files use ${VAR} placeholders, so they still work
+// with envsubst and kubectl for clusters managed by hand.
+package manifests
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+)
+
+//go:embed tenant/*.yaml
+var tenantFiles embed.FS
+
+// Tenant manifest files.
+const (
+	RBACFiles         = "tenant/rbac-tenant-*.yaml"
+	NetworkPolicyFile = "tenant/network-policy-final.yaml"
+	ResourceQuotaFile = "tenant/resourceQuota.yaml"
+)
+
+var placeholder = regexp.MustCompile(`\$\{([A-Z0-9_]+)\}`)
+
+// Vars are the values of the manifest placeholders. A manifest that uses a
+// placeholder whose value is not set fails to render.
+type Vars struct {
+	TenantName string                // ${TENANT_NAME}: the tenant namespace
+	OrgID      string                // ${ORG_ID}
+	PlanTier   string                // ${PLAN_TIER}: plan tier enum name, e.g. "PLAN_TIER_PRO"
+	Quota      *acctv1.ResourceQuota // ${TENANT_CPU}, ${TENANT_RAM}, ... ${TENANT_STATEFULSET_LIMIT}
+}
+
+func (v Vars) lookup(name string) (string, error) {
+	value, err := v.value(name)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return "", fmt.Errorf("no value for ${%s}", name)
+	}
+	return value, nil
+}
+
+func (v Vars) value(name string) (string, error) {
+	switch name {
+	case "TENANT_NAME":
+		return v.TenantName, nil
+	case "ORG_ID":
+		return v.OrgID, nil
+	case "PLAN_TIER":
+		return v.PlanTier, nil
+	}
+
+	q := v.Quota
+	if q == nil {
+		q = &acctv1.ResourceQuota{}
+	}
+	count := func(n int32) string {
+		if n <= 0 {
+			return ""
+		}
+		return strconv.Itoa(int(n))
+	}
+	switch name {
+	case "TENANT_CPU":
+		return q.GetRequestsCpu(), nil
+	case "TENANT_RAM":
+		return q.GetRequestsMemory(), nil
+	case "TENANT_CPU_LIMIT":
+		return q.GetLimitsCpu(), nil
+	case "TENANT_RAM_LIMIT":
+		return q.GetLimitsMemory(), nil
+	case "TENANT_PVC_LIMIT":
+		return count(q.GetMaxPvcs()), nil
+	case "TENANT_SERVICE_LIMIT":
+		return count(q.GetMaxServices()), nil
+	case "TENANT_DEPLOYMENT_LIMIT":
+		return count(q.GetMaxDeployments()), nil
+	case "TENANT_STATEFULSET_LIMIT":
+		return count(q.GetMaxStatefulsets()), nil
+	}
+	return "", fmt.Errorf("unknown placeholder ${%s}", name)
+}
+
+// RBAC renders the tenant-admin, tenant-user and tenant-viewer Roles and
+// their RoleBindings.
+func RBAC(v Vars) ([]*rbacv1.Role, []*rbacv1.RoleBinding, error) {
+	objs, err := render[runtime.Object](RBACFiles, v)
+	if err != nil {
+		return nil, nil, err
+	}
+	var roles []*rbacv1.Role
+	var bindings []*rbacv1.RoleBinding
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *rbacv1.Role:
+			roles = append(roles, o)
+		case *rbacv1.RoleBinding:
+			bindings = append(bindings, o)
+		default:
+			return nil, nil, fmt.Errorf("%s: unexpected %s", RBACFiles, obj.GetObjectKind().GroupVersionKind().Kind)
+		}
+	}
+	return roles, bindings, nil
+}
+
+// NetworkPolicy renders the tenant-isolation NetworkPolicy.
+func NetworkPolicy(v Vars) (*networkingv1.NetworkPolicy, error) {
+	return renderOne[*networkingv1.NetworkPolicy](NetworkPolicyFile, v)
+}
+
+// ResourceQuota renders the tenant-quota ResourceQuota; v.Quota must be set.
+func ResourceQuota(v Vars) (*corev1.ResourceQuota, error) {
+	return renderOne[*corev1.ResourceQuota](ResourceQuotaFile, v)
+}
+
+func renderOne[T runtime.Object](pattern string, v Vars) (T, error) {
+	objs, err := render[T](pattern, v)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if len(objs) != 1 {
+		var zero T
+		return zero, fmt.Errorf("%s: expected one object, got %d", pattern, len(objs))
+	}
+	return objs[0], nil
+}
+
+// render substitutes v into the files matching pattern and decodes every
+// document into T. Documents of another kind are an error.
+func render[T runtime.Object](pattern string, v Vars) ([]T, error) {
+	paths, err := fs.Glob(tenantFiles, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no manifest matches %s", pattern)
+	}
+
+	var objs []T
+	for _, path := range paths {
+		data, err := tenantFiles.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		data, err = substitute(data, v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		decoded, err := decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, obj := range decoded {
+			typed, ok := obj.(T)
+			if !ok {
+				return nil, fmt.Errorf("%s: unexpected %s", path, obj.GetObjectKind().GroupVersionKind().Kind)
+			}
+			objs = append(objs, typed)
+		}
+	}
+	return objs, nil
+}
+
+// substitute replaces every ${VAR} in data, failing on unknown or unset ones
+func substitute(data []byte, v Vars) ([]byte, error) {
+	var errs []error
+	out := placeholder.ReplaceAllFunc(data, func(m []byte) []byte {
+		value, err := v.lookup(string(placeholder.FindSubmatch(m)[1]))
+		if err != nil {
+			errs = append(errs, err)
+			return m
+		}
+		return []byte(value)
+	})
+	return out, errors.Join(errs...)
+}
+
+// decode parses a multi-document YAML file into typed objects with their
+// apiVersion and kind set, skipping documents that hold only comments
+func decode(data []byte) ([]runtime.Object, error) {
+	var objs []runtime.Object
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var content map[string]interface{}
+		if err := yaml.Unmarshal(doc, &content); err != nil {
+			return nil, err
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		obj, gvk, err := scheme.Codecs.UniversalDeserializer().Decode(doc, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		obj.GetObjectKind().SetGroupVersionKind(*gvk)
+		objs = append(objs, obj)
+	}
+}
@@ -0,0 +1,145 @@
+package manifests
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	acctv1 "github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/gen/proto/acct-management/v1"
+)
+
+func TestSubstitute(t *testing.T) {
+	vars := Vars{
+		TenantName: "tenant-acme",
+		OrgID:      "acme",
+		Quota:      &acctv1.ResourceQuota{RequestsCpu: "4", MaxPvcs: 10},
+	}
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "no placeholders", in: "kind: Role", want: "kind: Role"},
+		{name: "repeated", in: "${TENANT_NAME}/${ORG_ID}/${TENANT_NAME}", want: "tenant-acme/acme/tenant-acme"},
+		{name: "quota", in: `cpu: "${TENANT_CPU}", pvcs: "${TENANT_PVC_LIMIT}"`, want: `cpu: "4", pvcs: "10"`},
+		{name: "lower case is not a placeholder", in: "${tenant_name}", want: "${tenant_name}"},
+		{name: "unset", in: "${PLAN_TIER}", wantErr: true},
+		{name: "unset count", in: "${TENANT_SERVICE_LIMIT}", wantErr: true},
+		{name: "unknown", in: "${TENANT_GPU}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := substitute([]byte(tt.in), vars)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("substitute(%q) = %q, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("substitute(%q): %v", tt.in, err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("substitute(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	quota := &acctv1.ResourceQuota{
+		RequestsCpu: "4", RequestsMemory: "8Gi", LimitsCpu: "8", LimitsMemory: "16Gi",
+		MaxPvcs: 10, MaxServices: 20, MaxDeployments: 30, MaxStatefulsets: 5,
+	}
+	vars := Vars{TenantName: "tenant-acme", OrgID: "acme", PlanTier: "PLAN_TIER_PRO", Quota: quota}
+
+	t.Run("rbac", func(t *testing.T) {
+		roles, bindings, err := RBAC(vars)
+		if err != nil {
+			t.Fatalf("RBAC: %v", err)
+		}
+		wantRoles := map[string]bool{"tenant-admin": true, "tenant-user": true, "tenant-viewer": true}
+		if len(roles) != len(wantRoles) {
+			t.Fatalf("RBAC returned %d roles, want %d", len(roles), len(wantRoles))
+		}
+		for _, r := range roles {
+			if !wantRoles[r.Name] || r.Namespace != "tenant-acme" {
+				t.Errorf("unexpected role %s/%s", r.Namespace, r.Name)
+			}
+		}
+		if len(bindings) != len(wantRoles) {
+			t.Fatalf("RBAC returned %d bindings, want %d", len(bindings), len(wantRoles))
+		}
+		for _, b := range bindings {
+			if !wantRoles[b.RoleRef.Name] || b.Namespace != "tenant-acme" {
+				t.Errorf("unexpected binding %s/%s to %s", b.Namespace, b.Name, b.RoleRef.Name)
+			}
+		}
+	})
+
+	t.Run("network policy", func(t *testing.T) {
+		policy, err := NetworkPolicy(vars)
+		if err != nil {
+			t.Fatalf("NetworkPolicy: %v", err)
+		}
+		if policy.Name != "tenant-isolation" || policy.Namespace != "tenant-acme" {
+			t.Errorf("NetworkPolicy = %s/%s", policy.Namespace, policy.Name)
+		}
+		if got := policy.Spec.Ingress[0].From[0].NamespaceSelector.MatchLabels["tenant"]; got != "tenant-acme" {
+			t.Errorf("ingress admits tenant %q, want tenant-acme", got)
+		}
+
+		// DNS must reach kube-system by the label Kubernetes sets on it, over both protocols
+		dns := map[corev1.Protocol]bool{}
+		for _, rule := range policy.Spec.Egress {
+			for _, port := range rule.Ports {
+				if port.Port == nil || port.Port.IntValue() != 53 || port.Protocol == nil {
+					continue
+				}
+				for _, peer := range rule.To {
+					if peer.NamespaceSelector != nil && peer.NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"] == "kube-system" {
+						dns[*port.Protocol] = true
+					}
+				}
+			}
+		}
+		if !dns[corev1.ProtocolUDP] || !dns[corev1.ProtocolTCP] {
+			t.Errorf("egress to kube-system DNS = %v, want UDP and TCP 53", dns)
+		}
+	})
+
+	t.Run("resource quota", func(t *testing.T) {
+		rq, err := ResourceQuota(vars)
+		if err != nil {
+			t.Fatalf("ResourceQuota: %v", err)
+		}
+		want := map[corev1.ResourceName]string{
+			corev1.ResourceRequestsCPU:    "4",
+			corev1.ResourceRequestsMemory: "8Gi",
+			corev1.ResourceLimitsCPU:      "8",
+			corev1.ResourceLimitsMemory:   "16Gi",
+			"persistentvolumeclaims":      "10",
+			"services":                    "20",
+			"count/deployments.apps":      "30",
+			"count/statefulsets.apps":     "5",
+		}
+		for name, value := range want {
+			got := rq.Spec.Hard[name]
+			if got.String() != value {
+				t.Errorf("quota %s = %s, want %s", name, got.String(), value)
+			}
+		}
+		if rq.Labels["plan-tier"] != "PLAN_TIER_PRO" {
+			t.Errorf("quota plan-tier label = %q", rq.Labels["plan-tier"])
+		}
+	})
+
+	t.Run("resource quota without quota", func(t *testing.T) {
+		if _, err := ResourceQuota(Vars{TenantName: "tenant-acme", PlanTier: "PLAN_TIER_PRO"}); err == nil {
+			t.Fatal("ResourceQuota rendered without quota values")
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package periodic
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every calls fn immediately and then once per interval until ctx is
+// cancelled. A failed call is logged as "<what> failed" and retried on the
+// next tick.
+func Every(ctx context.Context, interval time.Duration, what string, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := fn(ctx); err != nil {
+			log.Printf("%s failed: %v", what, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package policy
+
+import (
+	"bytes"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// Supported admission engines.
+const (
+	EngineKyverno = "kyverno"
+	EngineVAP     = "vap" // ValidatingAdmissionPolicy (admissionregistration.k8s.io/v1, Kubernetes 1.30+)
+)
+
+// PolicyName is the name of the generated policy (and binding).
+const PolicyName = "tenant-isolation"
+
+// tenantNamespaceSelector matches every namespace created by the account service
+var tenantNamespaceSelector = map[string]interface{}{
+	"matchLabels": map[string]interface{}{
+		"managed-by": "account-provisioning-service",
+	},
+}
+
+// requiredNodeAffinity is the path of the node selector terms a pod must satisfy
+const requiredNodeAffinity = "spec.affinity.nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution"
+
+// namespaceTenantContext looks up the tenant of the request's namespace. Rules
+// compare with it, as sub-namespaces and imported namespaces are not named
+// tenant-<id>.
+var namespaceTenantContext = []interface{}{
+	map[string]interface{}{
+		"name": "namespaceTenant",
+		"apiCall": map[string]interface{}{
+			"urlPath":  "/api/v1/namespaces/{{ request.namespace }}",
+			"jmesPath": `metadata.labels."tenant-id" || ''`,
+		},
+	},
+}
+
+// Generate returns cluster-wide admission objects that block hostPath volumes,
+// hostNetwork, and nodeSelectors or required node affinity targeting another
+// tenant's dedicated nodes in tenant namespaces. They complement Pod Security Admission, which cannot see
+// tenant boundaries.
+func Generate(engine string) ([]*unstructured.Unstructured, error) {
+	switch engine {
+	case EngineKyverno:
+		return []*unstructured.Unstructured{kyvernoPolicy()}, nil
+	case EngineVAP:
+		return validatingAdmissionPolicy(), nil
+	default:
+		return nil, fmt.Errorf("unknown policy engine %q (want %q or %q)", engine, EngineKyverno, EngineVAP)
+	}
+}
+
+// YAML renders objects as a multi-document YAML stream for kubectl apply.
+func YAML(objects []*unstructured.Unstructured) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, obj := range objects {
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", obj.GetName(), err)
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+func kyvernoPolicy() *unstructured.Unstructured {
+	match := map[string]interface{}{
+		"any": []interface{}{
+			map[string]interface{}{
+				"resources": map[string]interface{}{
+					"kinds":             []interface{}{"Pod"},
+					"namespaceSelector": tenantNamespaceSelector,
+				},
+			},
+		},
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kyverno.io/v1",
+		"kind":       "ClusterPolicy",
+		"metadata": map[string]interface{}{
+			"name": PolicyName,
+		},
+		"spec": map[string]interface{}{
+			"validationFailureAction": "Enforce",
+			"background":              true,
+			"rules": []interface{}{
+				map[string]interface{}{
+					"name":  "disallow-host-path",
+					"match": match,
+					"validate": map[string]interface{}{
+						"message": "hostPath volumes are not allowed in tenant namespaces",
+						"pattern": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"=(volumes)": []interface{}{
+									map[string]interface{}{"X(hostPath)": "null"},
+								},
+							},
+						},
+					},
+				},
+				map[string]interface{}{
+					"name":  "disallow-host-network",
+					"match": match,
+					"validate": map[string]interface{}{
+						"message": "hostNetwork is not allowed in tenant namespaces",
+						"pattern": map[string]interface{}{
+							"spec": map[string]interface{}{
+								"=(hostNetwork)": false,
+							},
+						},
+					},
+				},
+				map[string]interface{}{
+					"name":    "restrict-tenant-node-selector",
+					"match":   match,
+					"context": namespaceTenantContext,
+					"preconditions": map[string]interface{}{
+						"all": []interface{}{
+							map[string]interface{}{
+								"key":      `{{ request.object.spec.nodeSelector."tenant-id" || '' }}`,
+								"operator": "NotEquals",
+								"value":    "",
+							},
+						},
+					},
+					"validate": map[string]interface{}{
+						"message": "pods may only select their own tenant's dedicated nodes",
+						"deny": map[string]interface{}{
+							"conditions": map[string]interface{}{
+								"any": []interface{}{
+									map[string]interface{}{
+										"key":      `{{ request.object.spec.nodeSelector."tenant-id" }}`,
+										"operator": "NotEquals",
+										"value":    "{{ namespaceTenant }}",
+									},
+								},
+							},
+						},
+					},
+				},
+				map[string]interface{}{
+					"name":    "restrict-tenant-node-affinity",
+					"match":   match,
+					"context": namespaceTenantContext,
+					"preconditions": map[string]interface{}{
+						"all": []interface{}{
+							map[string]interface{}{
+								"key":      "{{ request.object." + requiredNodeAffinity + ".nodeSelectorTerms[].matchExpressions[?key=='tenant-id'][] || `[]` | length(@) }}",
+								"operator": "GreaterThan",
+								"value":    0,
+							},
+						},
+					},
+					// Only In the own tenant and DoesNotExist stay off other
+					// tenants' nodes; NotIn and Exists match them
+					"validate": map[string]interface{}{
+						"message": "pods may only require their own tenant's dedicated nodes",
+						"foreach": []interface{}{
+							map[string]interface{}{
+								"list": "request.object." + requiredNodeAffinity + ".nodeSelectorTerms[].matchExpressions[?key=='tenant-id'][]",
+								"deny": map[string]interface{}{
+									"conditions": map[string]interface{}{
+										"any": []interface{}{
+											map[string]interface{}{
+												"key":      "{{ element.operator }}",
+												"operator": "AnyNotIn",
+												"value":    []interface{}{"In", "DoesNotExist"},
+											},
+											map[string]interface{}{
+												"key":      "{{ element.values || `[]` }}",
+												"operator": "AnyNotIn",
+												"value":    []interface{}{"{{ namespaceTenant }}"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func validatingAdmissionPolicy() []*unstructured.Unstructured {
+	validation := func(expression, message string) map[string]interface{} {
+		return map[string]interface{}{"expression": expression, "message": message}
+	}
+
+	policy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "admissionregistration.k8s.io/v1",
+		"kind":       "ValidatingAdmissionPolicy",
+		"metadata": map[string]interface{}{
+			"name": PolicyName,
+		},
+		"spec": map[string]interface{}{
+			"failurePolicy": "Fail",
+			"matchConstraints": map[string]interface{}{
+				"namespaceSelector": tenantNamespaceSelector,
+				"resourceRules": []interface{}{
+					map[string]interface{}{
+						"apiGroups":   []interface{}{""},
+						"apiVersions": []interface{}{"v1"},
+						"operations":  []interface{}{"CREATE", "UPDATE"},
+						"resources":   []interface{}{"pods"},
+					},
+				},
+			},
+			"validations": []interface{}{
+				validation(
+					"!has(object.spec.volumes) || object.spec.volumes.all(v, !has(v.hostPath))",
+					"hostPath volumes are not allowed in tenant namespaces",
+				),
+				validation(
+					"!has(object.spec.hostNetwork) || !object.spec.hostNetwork",
+					"hostNetwork is not allowed in tenant namespaces",
+				),
+				validation(
+					"!has(object.spec.nodeSelector) || !('tenant-id' in object.spec.nodeSelector) || "+
+						"object.spec.nodeSelector['tenant-id'] == namespaceObject.metadata.labels['tenant-id']",
+					"pods may only select their own tenant's dedicated nodes",
+				),
+				validation(
+					"!has(object.spec.affinity) || !has(object.spec.affinity.nodeAffinity) || "+
+						"!has(object.spec.affinity.nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution) || "+
+						"object.spec.affinity.nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution.nodeSelectorTerms.all(t, "+
+						"!has(t.matchExpressions) || t.matchExpressions.all(e, e.key != 'tenant-id' || e.operator == 'DoesNotExist' || "+
+						"(e.operator == 'In' && has(e.values) && e.values.all(v, v == namespaceObject.metadata.labels['tenant-id']))))",
+					"pods may only require their own tenant's dedicated nodes",
+				),
+			},
+		},
+	}}
+
+	binding := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "admissionregistration.k8s.io/v1",
+		"kind":       "ValidatingAdmissionPolicyBinding",
+		"metadata": map[string]interface{}{
+			"name": PolicyName,
+		},
+		"spec": map[string]interface{}{
+			"policyName":        PolicyName,
+			"validationActions": []interface{}{"Deny"},
+		},
+	}}
+
+	return []*unstructured.Unstructured{policy, binding}
+}
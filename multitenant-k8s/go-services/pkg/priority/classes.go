@@ -0,0 +1,56 @@
+package priority
+
+import "sort"
+
+// Class is the cluster-wide PriorityClass used by tenants on one plan tier.
+type Class struct {
+	Name        string
+	Value       int32
+	Preempt     bool // Whether pods may preempt lower-priority pods
+	Description string
+}
+
+// classes maps plan tier names (proto enum names) to their PriorityClass.
+// FREE pods never preempt anyone; paying tiers preempt lower tiers.
+var classes = map[string]Class{
+	"PLAN_TIER_FREE": {
+		Name:        "tenant-free",
+		Value:       1000,
+		Preempt:     false,
+		Description: "Pods of FREE tier tenants; never preempts other pods",
+	},
+	"PLAN_TIER_STARTER": {
+		Name:        "tenant-starter",
+		Value:       2000,
+		Preempt:     true,
+		Description: "Pods of STARTER tier tenants",
+	},
+	"PLAN_TIER_PRO": {
+		Name:        "tenant-pro",
+		Value:       5000,
+		Preempt:     true,
+		Description: "Pods of PRO tier tenants",
+	},
+	"PLAN_TIER_ENTERPRISE": {
+		Name:        "tenant-enterprise",
+		Value:       10000,
+		Preempt:     true,
+		Description: "Pods of ENTERPRISE tier tenants",
+	},
+}
+
+// ForTier returns the PriorityClass for a plan tier name such as "PLAN_TIER_PRO".
+func ForTier(tier string) (Class, bool) {
+	c, ok := classes[tier]
+	return c, ok
+}
+
+// All returns every tenant PriorityClass ordered by value.
+func All() []Class {
+	all := make([]Class, 0, len(classes))
+	for _, c := range classes {
+		all = append(all, c)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Value < all[j].Value })
+	return all
+}
@@ -19,6 +19,7 @@ type Service struct {
 	locker   DistributedLocker
 	throttle Throttler
 	lockTTL  time.Duration
+	tenants  TenantDirectory // Optional; resolves namespace and priority class
 }
 
 // DistributedLocker defines a minimal interface for a distributed lock backend.
@@ -47,6 +48,13 @@ type JobQueue interface {
 	Enqueue(ctx context.Context, key []byte, payload []byte) error
 }
 
+// TopicQueue is a JobQueue that can also write to a named topic, which is how
+// jobs of tenants with a dedicated topic are routed.
+type TopicQueue interface {
+	JobQueue
+	EnqueueTo(ctx context.Context, topic string, key []byte, payload []byte) error
+}
+
 // New creates a new scheduler Service.
 // The caller is responsible for providing a concrete JobQueue (e.g., Kafka producer),
 // a DistributedLocker (e.g., Redis), and a Throttler (e.g., Redis rate limiter).
@@ -74,16 +82,20 @@ func New(queue JobQueue, locker DistributedLocker, throttle Throttler, lockTTL t
 
 // ScheduledJobEnvelope is the payload pushed to Kafka for downstream processors.
 type ScheduledJobEnvelope struct {
-	JobID          string                           `json:"job_id"`
-	OrganizationID string                           `json:"organization_id"`
-	JobType        string                           `json:"job_type"`
-	Prompt         string                           `json:"prompt"`
-	Parameters     map[string]interface{}           `json:"parameters"`
-	Payload        string                           `json:"payload"`
-	TimeoutSeconds int32                            `json:"timeout_seconds"`
-	CallbackURL    string                           `json:"callback_url"`
-	CreatedAt      time.Time                        `json:"created_at"`
-	RawRequest     *mcpschedulerv1.CreateJobRequest `json:"-"`
+	JobID          string                 `json:"job_id"`
+	OrganizationID string                 `json:"organization_id"`
+	JobType        string                 `json:"job_type"`
+	Prompt         string                 `json:"prompt"`
+	Parameters     map[string]interface{} `json:"parameters"`
+	Payload        string                 `json:"payload"`
+	TimeoutSeconds int32                  `json:"timeout_seconds"`
+	CallbackURL    string                 `json:"callback_url"`
+	Namespace      string                 `json:"namespace,omitempty"`
+	// PriorityClassName is the tenant's tier PriorityClass; workers must set it
+	// on the pods they create or the namespace quota rejects them.
+	PriorityClassName string                           `json:"priority_class_name,omitempty"`
+	CreatedAt         time.Time                        `json:"created_at"`
+	RawRequest        *mcpschedulerv1.CreateJobRequest `json:"-"`
 }
 
 // ScheduleJob validates the request, checks throttle, acquires lock, and enqueues job.
@@ -134,18 +146,29 @@ func (s *Service) ScheduleJob(ctx context.Context, req *mcpschedulerv1.CreateJob
 	}()
 
 	// Step 4: Create job envelope
+	tenant := &Tenant{}
+	if s.tenants != nil {
+		tenant, err = s.tenants.Tenant(ctx, req.GetOrganizationId())
+		if err != nil {
+			enqueueErr = err
+			return nil, fmt.Errorf("failed to look up tenant: %w", err)
+		}
+	}
+
 	jobID := uuid.NewString()
 	env := &ScheduledJobEnvelope{
-		JobID:          jobID,
-		OrganizationID: req.GetOrganizationId(),
-		JobType:        req.GetJobType(),
-		Prompt:         req.GetPrompt(),
-		Parameters:     convertParameters(req.GetParameters()),
-		Payload:        req.GetPayload(),
-		TimeoutSeconds: req.GetTimeoutSeconds(),
-		CallbackURL:    req.GetCallbackUrl(),
-		CreatedAt:      time.Now().UTC(),
-		RawRequest:     req,
+		JobID:             jobID,
+		OrganizationID:    req.GetOrganizationId(),
+		JobType:           req.GetJobType(),
+		Prompt:            req.GetPrompt(),
+		Parameters:        convertParameters(req.GetParameters()),
+		Payload:           req.GetPayload(),
+		TimeoutSeconds:    req.GetTimeoutSeconds(),
+		CallbackURL:       req.GetCallbackUrl(),
+		Namespace:         tenant.Namespace,
+		PriorityClassName: tenant.PriorityClassName,
+		CreatedAt:         time.Now().UTC(),
+		RawRequest:        req,
 	}
 
 	payload, err := json.Marshal(env)
@@ -154,8 +177,8 @@ func (s *Service) ScheduleJob(ctx context.Context, req *mcpschedulerv1.CreateJob
 		return nil, fmt.Errorf("failed to marshal job payload: %w", err)
 	}
 
-	// Step 5: Enqueue to Kafka
-	if err := s.queue.Enqueue(ctx, []byte(req.GetOrganizationId()), payload); err != nil {
+	// Step 5: Enqueue to Kafka, on the tenant's own topic if it has one
+	if err := s.enqueue(ctx, tenant.Topic, []byte(req.GetOrganizationId()), payload); err != nil {
 		enqueueErr = err
 		return nil, fmt.Errorf("failed to enqueue job: %w", err)
 	}
@@ -165,7 +188,7 @@ func (s *Service) ScheduleJob(ctx context.Context, req *mcpschedulerv1.CreateJob
 		JobId:               jobID,
 		OrganizationId:      req.GetOrganizationId(),
 		Status:              mcpschedulerv1.JobStatus_JOB_STATUS_PENDING,
-		Namespace:           "",
+		Namespace:           tenant.Namespace,
 		PodName:             "",
 		CreatedAt:           nil,
 		EstimatedTtlSeconds: req.GetTimeoutSeconds(),
@@ -174,6 +197,19 @@ func (s *Service) ScheduleJob(ctx context.Context, req *mcpschedulerv1.CreateJob
 	return resp, nil
 }
 
+// enqueue writes to topic, or to the queue's shared topic when topic is empty
+func (s *Service) enqueue(ctx context.Context, topic string, key, payload []byte) error {
+	if topic == "" {
+		return s.queue.Enqueue(ctx, key, payload)
+	}
+	// Falling back to the shared topic would hide the job from the tenant's workers
+	routed, ok := s.queue.(TopicQueue)
+	if !ok {
+		return fmt.Errorf("queue cannot route jobs to topic %s", topic)
+	}
+	return routed.EnqueueTo(ctx, topic, key, payload)
+}
+
 // validateRequest checks that required fields are present
 func validateRequest(req *mcpschedulerv1.CreateJobRequest) error {
 	if req == nil {
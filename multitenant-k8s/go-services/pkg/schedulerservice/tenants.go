@@ -0,0 +1,55 @@
+package schedulerservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/priority"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+// Tenant holds the scheduling attributes of an organization.
+type Tenant struct {
+	Namespace         string
+	PriorityClassName string
+	Topic             string // Dedicated job topic; empty means the queue's shared topic
+}
+
+// TenantDirectory looks up where and at what priority an organization's jobs run.
+type TenantDirectory interface {
+	Tenant(ctx context.Context, organizationID string) (*Tenant, error)
+}
+
+// AccountDirectory is a TenantDirectory backed by the account registry.
+type AccountDirectory struct {
+	accounts *storage.AccountStore
+}
+
+// NewAccountDirectory creates a TenantDirectory that reads the shared account registry.
+func NewAccountDirectory(accounts *storage.AccountStore) (*AccountDirectory, error) {
+	if accounts == nil {
+		return nil, fmt.Errorf("accounts must not be nil")
+	}
+	return &AccountDirectory{accounts: accounts}, nil
+}
+
+// Tenant implements TenantDirectory. The priority class is the one provisioned
+// for the account's plan tier, which is the only class its namespace admits.
+func (d *AccountDirectory) Tenant(ctx context.Context, organizationID string) (*Tenant, error) {
+	record, err := d.accounts.Get(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	class, ok := priority.ForTier(record.PlanTier)
+	if !ok {
+		return nil, fmt.Errorf("no priority class for plan tier %s", record.PlanTier)
+	}
+	return &Tenant{Namespace: record.Namespace, PriorityClassName: class.Name, Topic: record.JobTopic}, nil
+}
+
+// SetTenantDirectory makes scheduled jobs carry the tenant's namespace and
+// priority class, and routes them to the tenant's dedicated topic if it has
+// one. Without a directory every job goes to the shared topic.
+func (s *Service) SetTenantDirectory(tenants TenantDirectory) {
+	s.tenants = tenants
+}
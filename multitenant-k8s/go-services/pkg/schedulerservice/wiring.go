@@ -12,9 +12,11 @@ import (
 	"github.com/segmentio/kafka-go"
 )
 
-// KafkaQueue is a JobQueue implementation backed by Kafka.
+// KafkaQueue is a JobQueue implementation backed by Kafka. It also implements
+// TopicQueue for tenants with a dedicated topic.
 type KafkaQueue struct {
 	writer *kafka.Writer
+	topic  string
 }
 
 // NewKafkaQueue constructs a Kafka-backed JobQueue.
@@ -26,60 +28,32 @@ func NewKafkaQueue(brokers []string, topic string) (*KafkaQueue, error) {
 		return nil, fmt.Errorf("topic must not be empty")
 	}
 
+	// The topic is set per message so tenant topics share the writer
 	writer := &kafka.Writer{
 		Addr:         kafka.TCP(brokers...),
-		Topic:        topic,
 		Balancer:     &kafka.Hash{},
 		RequiredAcks: kafka.RequireAll,
 		Async:        false,
 	}
 
-	return &KafkaQueue{writer: writer}, nil
+	return &KafkaQueue{writer: writer, topic: topic}, nil
 }
 
 // Enqueue implements JobQueue for Kafka.
 func (k *KafkaQueue) Enqueue(ctx context.Context, key []byte, payload []byte) error {
+	return k.EnqueueTo(ctx, k.topic, key, payload)
+}
+
+// EnqueueTo implements TopicQueue for Kafka.
+func (k *KafkaQueue) EnqueueTo(ctx context.Context, topic string, key []byte, payload []byte) error {
 	msg := kafka.Message{
+		Topic: topic,
 		Key:   key,
 		Value: payload,
 	}
 	return k.writer.WriteMessages(ctx, msg)
 }
 
-// RedisLocker is a Redis-based implementation of DistributedLocker.
-type RedisLocker struct {
-	client *redis.Client
-}
-
-// NewRedisLocker constructs a Redis-backed DistributedLocker.
-func NewRedisLocker(addr, password string, db int) (*RedisLocker, error) {
-	if addr == "" {
-		return nil, fmt.Errorf("redis addr must not be empty")
-	}
-
-	client := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: password,
-		DB:       db,
-	})
-
-	// Best-effort connectivity check.
-	if err := client.Ping(context.Background()).Err(); err != nil {
-		return nil, fmt.Errorf("failed to ping redis: %w", err)
-	}
-
-	return &RedisLocker{client: client}, nil
-}
-
-// Acquire implements DistributedLocker using SETNX with TTL.
-func (r *RedisLocker) Acquire(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
-	ok, err := r.client.SetNX(ctx, key, owner, ttl).Result()
-	if err != nil {
-		return false, err
-	}
-	return ok, nil
-}
-
 // NewFromEnv wires the MCP scheduler service using environment variables:
 //
 //	KAFKA_BROKERS       - comma-separated list of brokers (host:port)
@@ -88,6 +62,8 @@ func (r *RedisLocker) Acquire(ctx context.Context, key, owner string, ttl time.D
 //	REDIS_PASSWORD      - Redis password (optional)
 //	REDIS_DB            - Redis DB index (optional, default 0)
 //	LOCK_TTL_SECONDS    - TTL for duplicate-protection lock (optional, default 300)
+//	THROTTLE_PER_MINUTE - jobs allowed per organization per minute (optional, default 60)
+//	THROTTLE_PER_HOUR   - jobs allowed per organization per hour (optional, default 1000)
 func NewFromEnv() (*Service, error) {
 	brokersEnv := os.Getenv("KAFKA_BROKERS")
 	topic := os.Getenv("KAFKA_TOPIC")
@@ -95,6 +71,8 @@ func NewFromEnv() (*Service, error) {
 	redisPassword := os.Getenv("REDIS_PASSWORD")
 	redisDBEnv := os.Getenv("REDIS_DB")
 	lockTTLEnv := os.Getenv("LOCK_TTL_SECONDS")
+	perMinuteEnv := os.Getenv("THROTTLE_PER_MINUTE")
+	perHourEnv := os.Getenv("THROTTLE_PER_HOUR")
 
 	var brokers []string
 	for _, b := range strings.Split(brokersEnv, ",") {
@@ -118,15 +96,36 @@ func NewFromEnv() (*Service, error) {
 		}
 	}
 
+	perMinute := int64(60)
+	if v, err := strconv.ParseInt(perMinuteEnv, 10, 64); err == nil && v > 0 {
+		perMinute = v
+	}
+	perHour := int64(1000)
+	if v, err := strconv.ParseInt(perHourEnv, 10, 64); err == nil && v > 0 {
+		perHour = v
+	}
+
 	queue, err := NewKafkaQueue(brokers, topic)
 	if err != nil {
 		return nil, err
 	}
 
-	locker, err := NewRedisLocker(redisAddr, redisPassword, redisDB)
-	if err != nil {
-		return nil, err
+	if redisAddr == "" {
+		return nil, fmt.Errorf("redis addr must not be empty")
 	}
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     redisAddr,
+		Password: redisPassword,
+		DB:       redisDB,
+	})
+
+	// Best-effort connectivity check.
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	locker := NewRedisLocker(redisClient)
+	throttle := NewRedisThrottler(redisClient, perMinute, perHour)
 
-	return New(queue, locker, lockTTL)
+	return New(queue, locker, throttle, lockTTL)
 }
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Account status values.
+const (
+	AccountStatusProvisioning = "PROVISIONING" // Committed to the GitOps repository but not yet synced
+	AccountStatusActive       = "ACTIVE"
+	AccountStatusSuspended    = "SUSPENDED"
+)
+
+// AccountRecord is the registry entry for a provisioned tenant.
+// Enum fields hold the proto enum names (e.g. "PLAN_TIER_PRO").
+type AccountRecord struct {
+	OrganizationID   string    `json:"organization_id"`
+	Namespace        string    `json:"namespace"`
+	Cluster          string    `json:"cluster,omitempty"` // Registry name of the cluster hosting the tenant
+	OrganizationType string    `json:"organization_type"`
+	PlanTier         string    `json:"plan_tier"`
+	IAMRoleARN       string    `json:"iam_role_arn"`
+	IAMRoleLinked    bool      `json:"iam_role_linked,omitempty"` // Role existed before import; deleting the account leaves it in place
+	S3Bucket         string    `json:"s3_bucket,omitempty"`
+	S3Prefix         string    `json:"s3_prefix,omitempty"`
+	EgressHostnames  []string  `json:"egress_hostnames,omitempty"` // External hosts pods may reach; empty with no CIDRs means none
+	EgressCIDRs      []string  `json:"egress_cidrs,omitempty"`
+	Registry         string    `json:"registry,omitempty"`  // Image repository prefix of the tenant, e.g. <account>.dkr.ecr.<region>.amazonaws.com/tenants/<id>
+	JobTopic         string    `json:"job_topic,omitempty"` // Dedicated Kafka topic for the tenant's jobs; empty means the shared topic
+	Status           string    `json:"status"`
+	SuspendReason    string    `json:"suspend_reason,omitempty"`    // Why a SUSPENDED account was suspended
+	TrialEndsAt      time.Time `json:"trial_ends_at,omitempty"`     // FREE accounts are suspended after this time; zero means no trial
+	PlanExpiresAt    time.Time `json:"plan_expires_at,omitempty"`   // Paid plans are downgraded or suspended after this time; zero means never
+	ExpiryWarnedAt   time.Time `json:"expiry_warned_at,omitempty"`  // When the pending deadline was last warned about
+	ExpiredFromPlan  string    `json:"expired_from_plan,omitempty"` // Plan tier before the last expiry, restored by RevertExpiry
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	// Tenant ingress
+	IngressHostname string         `json:"ingress_hostname,omitempty"` // Subdomain served from the shared Gateway; empty means no ingress
+	CustomDomains   []CustomDomain `json:"custom_domains,omitempty"`
+
+	// Environment template seeded into the namespace; nil when none was requested
+	Template *AppliedTemplate `json:"template,omitempty"`
+
+	// Commit of the GitOps repository holding the tenant's objects; empty when
+	// they are applied to the cluster directly
+	GitOpsRevision string `json:"gitops_revision,omitempty"`
+}
+
+// CustomDomain is a tenant's own domain, served once its DNS proves ownership.
+type CustomDomain struct {
+	Domain            string    `json:"domain"`
+	VerificationToken string    `json:"verification_token"` // Expected value of the domain's verification TXT record
+	AddedAt           time.Time `json:"added_at"`
+	VerifiedAt        time.Time `json:"verified_at,omitempty"` // Zero until the TXT record was found
+}
+
+// AppliedTemplate is the environment template installed in a tenant namespace.
+type AppliedTemplate struct {
+	Name      string           `json:"name"`
+	Version   string           `json:"version"`
+	AppliedAt time.Time        `json:"applied_at"`
+	Objects   []TemplateObject `json:"objects,omitempty"` // What this version applied; pruned when an upgrade drops them
+}
+
+// TemplateObject identifies an object a template created in the tenant namespace.
+type TemplateObject struct {
+	APIVersion string `json:"api_version"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
+
+// AccountStore is the registry of provisioned accounts.
+type AccountStore struct {
+	backend Backend
+}
+
+// NewAccountStore creates an account registry on top of backend.
+func NewAccountStore(backend Backend) *AccountStore {
+	return &AccountStore{backend: backend}
+}
+
+// Get returns the account for orgID, or ErrNotFound.
+func (s *AccountStore) Get(ctx context.Context, orgID string) (*AccountRecord, error) {
+	raw, err := s.backend.Get(ctx, accountKey(orgID))
+	if err != nil {
+		return nil, err
+	}
+
+	var rec AccountRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode account %s: %w", orgID, err)
+	}
+	return &rec, nil
+}
+
+// List returns all accounts ordered by organization ID.
+func (s *AccountStore) List(ctx context.Context) ([]AccountRecord, error) {
+	entries, err := s.backend.List(ctx, "accounts/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	records := make([]AccountRecord, 0, len(entries))
+	for _, e := range entries {
+		var rec AccountRecord
+		if err := json.Unmarshal(e.Value, &rec); err != nil {
+			return nil, fmt.Errorf("failed to decode account %s: %w", e.Key, err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// Save creates or replaces an account and stages events in the same transaction.
+// It overwrites whatever is stored; change an existing account with Modify.
+func (s *AccountStore) Save(ctx context.Context, rec *AccountRecord, events ...OutboxMessage) error {
+	now := time.Now().UTC()
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = now
+	}
+	rec.UpdatedAt = now
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode account: %w", err)
+	}
+
+	return s.backend.Update(ctx, nil, func(tx Tx) error {
+		tx.Put(accountKey(rec.OrganizationID), data)
+		return stageOutbox(tx, events)
+	})
+}
+
+// Modify re-reads the account for orgID inside a transaction, applies fn and
+// saves the result with events. The account is watched, so a concurrent write
+// retries fn on the newer record instead of being overwritten; fn must only
+// change rec. Returns the saved record, or ErrNotFound.
+func (s *AccountStore) Modify(ctx context.Context, orgID string, fn func(rec *AccountRecord) error, events ...OutboxMessage) (*AccountRecord, error) {
+	key := accountKey(orgID)
+	var rec *AccountRecord
+
+	err := s.backend.Update(ctx, []string{key}, func(tx Tx) error {
+		raw, err := tx.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		rec = &AccountRecord{}
+		if err := json.Unmarshal(raw, rec); err != nil {
+			return fmt.Errorf("failed to decode account %s: %w", orgID, err)
+		}
+
+		if err := fn(rec); err != nil {
+			return err
+		}
+		rec.UpdatedAt = time.Now().UTC()
+
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf("failed to encode account: %w", err)
+		}
+		tx.Put(key, data)
+		return stageOutbox(tx, events)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// Delete removes an account and stages events in the same transaction.
+func (s *AccountStore) Delete(ctx context.Context, orgID string, events ...OutboxMessage) error {
+	key := accountKey(orgID)
+	return s.backend.Update(ctx, []string{key}, func(tx Tx) error {
+		tx.Delete(key)
+		return stageOutbox(tx, events)
+	})
+}
+
+func accountKey(orgID string) string {
+	return "accounts/" + orgID
+}
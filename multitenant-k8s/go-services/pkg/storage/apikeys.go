@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// APIKeyRecord is a machine-to-machine credential for one organization.
+// Only a hash of the secret is stored; the plaintext is returned once at creation
+// or rotation and cannot be recovered.
+type APIKeyRecord struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	Name           string    `json:"name"`
+	SecretHash     string    `json:"secret_hash"`
+	Scopes         []string  `json:"scopes"`
+	CreatedAt      time.Time `json:"created_at"`
+	RotatedAt      time.Time `json:"rotated_at,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at,omitempty"` // zero means no expiry
+	RevokedAt      time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the key can currently authenticate.
+func (r *APIKeyRecord) Active(now time.Time) bool {
+	if !r.RevokedAt.IsZero() {
+		return false
+	}
+	return r.ExpiresAt.IsZero() || now.Before(r.ExpiresAt)
+}
+
+// APIKeyStore persists API keys keyed by their ID.
+type APIKeyStore struct {
+	backend Backend
+}
+
+// NewAPIKeyStore creates an API key store on top of backend.
+func NewAPIKeyStore(backend Backend) *APIKeyStore {
+	return &APIKeyStore{backend: backend}
+}
+
+// Get returns the key with the given ID, or ErrNotFound.
+func (s *APIKeyStore) Get(ctx context.Context, id string) (*APIKeyRecord, error) {
+	raw, err := s.backend.Get(ctx, apiKeyKey(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var rec APIKeyRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode api key %s: %w", id, err)
+	}
+	return &rec, nil
+}
+
+// List returns the keys of one organization, including revoked ones.
+func (s *APIKeyStore) List(ctx context.Context, orgID string) ([]APIKeyRecord, error) {
+	entries, err := s.backend.List(ctx, "apikeys/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	var records []APIKeyRecord
+	for _, e := range entries {
+		var rec APIKeyRecord
+		if err := json.Unmarshal(e.Value, &rec); err != nil {
+			return nil, fmt.Errorf("failed to decode api key %s: %w", e.Key, err)
+		}
+		if rec.OrganizationID == orgID {
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+// Create stores a new key. The key is watched, so it fails instead of
+// replacing a key with the same ID.
+func (s *APIKeyStore) Create(ctx context.Context, rec *APIKeyRecord) error {
+	key := apiKeyKey(rec.ID)
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode api key: %w", err)
+	}
+
+	return s.backend.Update(ctx, []string{key}, func(tx Tx) error {
+		if _, err := tx.Get(ctx, key); err == nil {
+			return fmt.Errorf("api key %s already exists", rec.ID)
+		} else if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		tx.Put(key, data)
+		return nil
+	})
+}
+
+// Modify re-reads the key with the given ID inside a transaction, applies fn
+// and saves the result. The key is watched, so a concurrent rotation or
+// revocation retries fn on the newer record instead of being overwritten; fn
+// must only change rec. Returns the saved record, or ErrNotFound.
+func (s *APIKeyStore) Modify(ctx context.Context, id string, fn func(rec *APIKeyRecord) error) (*APIKeyRecord, error) {
+	key := apiKeyKey(id)
+	var rec *APIKeyRecord
+
+	err := s.backend.Update(ctx, []string{key}, func(tx Tx) error {
+		raw, err := tx.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		rec = &APIKeyRecord{}
+		if err := json.Unmarshal(raw, rec); err != nil {
+			return fmt.Errorf("failed to decode api key %s: %w", id, err)
+		}
+
+		if err := fn(rec); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf("failed to encode api key: %w", err)
+		}
+		tx.Put(key, data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func apiKeyKey(id string) string {
+	return "apikeys/" + id
+}
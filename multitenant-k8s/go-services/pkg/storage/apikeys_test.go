@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAPIKeyStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewAPIKeyStore(NewMemoryBackend())
+	key := &APIKeyRecord{ID: "k1", OrganizationID: "acme", SecretHash: "h0", CreatedAt: time.Now().UTC()}
+
+	if err := store.Create(ctx, key); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := store.Create(ctx, &APIKeyRecord{ID: "k1", OrganizationID: "other"}); err == nil {
+		t.Fatal("Create replaced an existing key")
+	}
+
+	t.Run("concurrent changes are not lost", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := store.Modify(ctx, "k1", func(rec *APIKeyRecord) error {
+					rec.Scopes = append(rec.Scopes, fmt.Sprintf("scope-%d", i))
+					return nil
+				})
+				if err != nil {
+					t.Errorf("Modify: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		got, err := store.Get(ctx, "k1")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if len(got.Scopes) != 20 || got.OrganizationID != "acme" {
+			t.Errorf("after 20 modifications key has %d scopes and organization %q", len(got.Scopes), got.OrganizationID)
+		}
+	})
+
+	t.Run("failed change is not saved", func(t *testing.T) {
+		failed := errors.New("revoked")
+		_, err := store.Modify(ctx, "k1", func(rec *APIKeyRecord) error {
+			rec.SecretHash = "h1"
+			return failed
+		})
+		if !errors.Is(err, failed) {
+			t.Fatalf("Modify error = %v, want %v", err, failed)
+		}
+		got, err := store.Get(ctx, "k1")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if got.SecretHash != "h0" {
+			t.Errorf("secret hash = %q after a failed change, want h0", got.SecretHash)
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := store.Modify(ctx, "nope", func(*APIKeyRecord) error { return nil })
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Modify error = %v, want ErrNotFound", err)
+		}
+	})
+}
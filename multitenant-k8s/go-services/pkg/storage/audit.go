@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const auditHeadKey = "audit/head"
+
+// AuditRecord is one entry of the hash-chained audit log.
+type AuditRecord struct {
+	Sequence uint64          `json:"sequence"`
+	PrevHash string          `json:"prev_hash"`
+	Hash     string          `json:"hash"`
+	Event    json.RawMessage `json:"event"`
+}
+
+// AuditHead points at the most recent audit record.
+type AuditHead struct {
+	Sequence uint64 `json:"sequence"`
+	Hash     string `json:"hash"`
+}
+
+// AuditStore is an append-only log of audit records. It deliberately has no
+// update or delete operations.
+type AuditStore struct {
+	backend Backend
+}
+
+// NewAuditStore creates an audit store on top of backend.
+func NewAuditStore(backend Backend) *AuditStore {
+	return &AuditStore{backend: backend}
+}
+
+// Append adds event to the end of the log. seal computes the record hash from the
+// assigned sequence number, the previous record's hash and the event.
+func (s *AuditStore) Append(ctx context.Context, event []byte, seal func(seq uint64, prevHash string, event []byte) string) (*AuditRecord, error) {
+	var record *AuditRecord
+
+	err := s.backend.Update(ctx, []string{auditHeadKey}, func(tx Tx) error {
+		var head AuditHead
+		raw, err := tx.Get(ctx, auditHeadKey)
+		switch {
+		case errors.Is(err, ErrNotFound):
+		case err != nil:
+			return fmt.Errorf("failed to read audit head: %w", err)
+		default:
+			if err := json.Unmarshal(raw, &head); err != nil {
+				return fmt.Errorf("failed to decode audit head: %w", err)
+			}
+		}
+
+		seq := head.Sequence + 1
+		record = &AuditRecord{
+			Sequence: seq,
+			PrevHash: head.Hash,
+			Hash:     seal(seq, head.Hash, event),
+			Event:    event,
+		}
+
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit record: %w", err)
+		}
+		headData, err := json.Marshal(AuditHead{Sequence: seq, Hash: record.Hash})
+		if err != nil {
+			return fmt.Errorf("failed to encode audit head: %w", err)
+		}
+
+		tx.Put(auditRecordKey(seq), data)
+		tx.Put(auditHeadKey, headData)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// Head returns the pointer to the latest record. An empty log has a zero head.
+func (s *AuditStore) Head(ctx context.Context) (AuditHead, error) {
+	var head AuditHead
+	raw, err := s.backend.Get(ctx, auditHeadKey)
+	if errors.Is(err, ErrNotFound) {
+		return head, nil
+	}
+	if err != nil {
+		return head, fmt.Errorf("failed to read audit head: %w", err)
+	}
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return head, fmt.Errorf("failed to decode audit head: %w", err)
+	}
+	return head, nil
+}
+
+// List returns all records in sequence order.
+func (s *AuditStore) List(ctx context.Context) ([]AuditRecord, error) {
+	entries, err := s.backend.List(ctx, "audit/records/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit records: %w", err)
+	}
+
+	records := make([]AuditRecord, 0, len(entries))
+	for _, e := range entries {
+		var r AuditRecord
+		if err := json.Unmarshal(e.Value, &r); err != nil {
+			return nil, fmt.Errorf("failed to decode audit record %s: %w", e.Key, err)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// auditRecordKey zero-pads the sequence so keys list in order.
+func auditRecordKey(seq uint64) string {
+	return fmt.Sprintf("audit/records/%020d", seq)
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// BillingRecord links an organization to its customer and subscription in the
+// billing provider.
+type BillingRecord struct {
+	OrganizationID string    `json:"organization_id"`
+	CustomerID     string    `json:"customer_id"`
+	SubscriptionID string    `json:"subscription_id,omitempty"`
+	PlanItemID     string    `json:"plan_item_id,omitempty"`    // Subscription item carrying the plan price
+	MeteredItemID  string    `json:"metered_item_id,omitempty"` // Subscription item that job usage is reported against
+	Plan           string    `json:"plan"`                      // Plan tier the subscription is billed at
+	Status         string    `json:"status,omitempty"`          // Last subscription status seen, e.g. "active" or "past_due"
+	LastEventAt    time.Time `json:"last_event_at,omitempty"`   // Time of the newest webhook event applied; older ones are ignored
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// BillingStore persists billing records keyed by organization ID.
+type BillingStore struct {
+	backend Backend
+}
+
+// NewBillingStore creates a billing store on top of backend.
+func NewBillingStore(backend Backend) *BillingStore {
+	return &BillingStore{backend: backend}
+}
+
+// Get returns the billing record of orgID, or ErrNotFound.
+func (s *BillingStore) Get(ctx context.Context, orgID string) (*BillingRecord, error) {
+	raw, err := s.backend.Get(ctx, billingKey(orgID))
+	if err != nil {
+		return nil, err
+	}
+
+	var rec BillingRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode billing record %s: %w", orgID, err)
+	}
+	return &rec, nil
+}
+
+// Save creates or replaces a billing record, stamping UpdatedAt.
+func (s *BillingStore) Save(ctx context.Context, rec *BillingRecord) error {
+	rec.UpdatedAt = time.Now().UTC()
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = rec.UpdatedAt
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode billing record: %w", err)
+	}
+	return s.backend.Put(ctx, billingKey(rec.OrganizationID), data)
+}
+
+// Delete removes a billing record. Deleting a missing record is not an error.
+func (s *BillingStore) Delete(ctx context.Context, orgID string) error {
+	return s.backend.Delete(ctx, billingKey(orgID))
+}
+
+func billingKey(orgID string) string {
+	return "billing/" + orgID
+}
@@ -1 +1,75 @@
 package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ErrNotFound is returned when a key does not exist in the backend.
+var ErrNotFound = errors.New("storage: key not found")
+
+// Entry is a single key/value pair returned by Backend.List.
+type Entry struct {
+	Key   string
+	Value []byte
+}
+
+// Backend is the minimal key/value store the typed stores in this package are built on.
+// Redis is used in production; the in-memory backend is meant for local development.
+type Backend interface {
+	// Get returns the value stored at key, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put stores value at key, overwriting any existing value.
+	Put(ctx context.Context, key string, value []byte) error
+
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns every entry whose key starts with prefix, ordered by key.
+	List(ctx context.Context, prefix string) ([]Entry, error)
+
+	// Update runs fn atomically. Keys that fn reads to decide what to write must be
+	// passed in watch so concurrent writers are detected; fn is retried on conflict.
+	Update(ctx context.Context, watch []string, fn func(tx Tx) error) error
+}
+
+// Tx is the view of the backend available inside Backend.Update.
+// Writes are buffered and only applied if fn returns nil.
+type Tx interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(key string, value []byte)
+	Delete(key string)
+}
+
+// NewBackendFromEnv selects a storage backend using environment variables:
+//
+//	REDIS_ADDR          - Redis address (host:port); in-memory storage is used if empty
+//	REDIS_PASSWORD      - Redis password (optional)
+//	REDIS_DB            - Redis DB index (optional, default 0)
+//	STORAGE_KEY_PREFIX  - prefix for every key written (optional, default "tenants:")
+func NewBackendFromEnv() (Backend, error) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		return NewMemoryBackend(), nil
+	}
+
+	db := 0
+	if v := os.Getenv("REDIS_DB"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIS_DB %q: %w", v, err)
+		}
+		db = n
+	}
+
+	prefix := os.Getenv("STORAGE_KEY_PREFIX")
+	if prefix == "" {
+		prefix = "tenants:"
+	}
+
+	return NewRedisBackend(addr, os.Getenv("REDIS_PASSWORD"), db, prefix)
+}
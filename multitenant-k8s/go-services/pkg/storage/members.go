@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// MemberRecord is a user's membership in an organization. Users are identified
+// by email, which is also their Kubernetes user name and IdP user name.
+type MemberRecord struct {
+	OrganizationID string    `json:"organization_id"`
+	Email          string    `json:"email"`
+	Persona        string    `json:"persona"`           // admin, user or viewer
+	Subject        string    `json:"subject,omitempty"` // Token subject of the user who accepted the invitation
+	InvitationID   string    `json:"invitation_id,omitempty"`
+	InvitedBy      string    `json:"invited_by,omitempty"`
+	AddedAt        time.Time `json:"added_at"`
+}
+
+// InvitationRecord is an invitation to join an organization. The token sent to
+// the invitee is signed and not stored.
+type InvitationRecord struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	Email          string    `json:"email"`
+	Persona        string    `json:"persona"`
+	InvitedBy      string    `json:"invited_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	AcceptedAt     time.Time `json:"accepted_at,omitempty"`
+	RevokedAt      time.Time `json:"revoked_at,omitempty"`
+}
+
+// Pending reports whether the invitation can still be accepted.
+func (r *InvitationRecord) Pending(now time.Time) bool {
+	return r.AcceptedAt.IsZero() && r.RevokedAt.IsZero() && now.Before(r.ExpiresAt)
+}
+
+// MemberStore persists memberships keyed by organization and email.
+type MemberStore struct {
+	backend Backend
+}
+
+// NewMemberStore creates a member store on top of backend.
+func NewMemberStore(backend Backend) *MemberStore {
+	return &MemberStore{backend: backend}
+}
+
+// Get returns one membership, or ErrNotFound.
+func (s *MemberStore) Get(ctx context.Context, orgID, email string) (*MemberRecord, error) {
+	raw, err := s.backend.Get(ctx, memberKey(orgID, email))
+	if err != nil {
+		return nil, err
+	}
+
+	var rec MemberRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode member %s/%s: %w", orgID, email, err)
+	}
+	return &rec, nil
+}
+
+// List returns the members of one organization ordered by email.
+func (s *MemberStore) List(ctx context.Context, orgID string) ([]MemberRecord, error) {
+	entries, err := s.backend.List(ctx, "members/"+orgID+"/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+
+	records := make([]MemberRecord, 0, len(entries))
+	for _, e := range entries {
+		var rec MemberRecord
+		if err := json.Unmarshal(e.Value, &rec); err != nil {
+			return nil, fmt.Errorf("failed to decode member %s: %w", e.Key, err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// Save creates or replaces a membership.
+func (s *MemberStore) Save(ctx context.Context, rec *MemberRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode member: %w", err)
+	}
+	return s.backend.Put(ctx, memberKey(rec.OrganizationID, rec.Email), data)
+}
+
+// Delete removes a membership. Deleting a missing membership is not an error.
+func (s *MemberStore) Delete(ctx context.Context, orgID, email string) error {
+	return s.backend.Delete(ctx, memberKey(orgID, email))
+}
+
+func memberKey(orgID, email string) string {
+	return "members/" + orgID + "/" + email
+}
+
+// InvitationStore persists invitations keyed by their ID.
+type InvitationStore struct {
+	backend Backend
+}
+
+// NewInvitationStore creates an invitation store on top of backend.
+func NewInvitationStore(backend Backend) *InvitationStore {
+	return &InvitationStore{backend: backend}
+}
+
+// Get returns the invitation with the given ID, or ErrNotFound.
+func (s *InvitationStore) Get(ctx context.Context, id string) (*InvitationRecord, error) {
+	raw, err := s.backend.Get(ctx, invitationKey(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var rec InvitationRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode invitation %s: %w", id, err)
+	}
+	return &rec, nil
+}
+
+// List returns the invitations of one organization, including accepted and expired ones.
+func (s *InvitationStore) List(ctx context.Context, orgID string) ([]InvitationRecord, error) {
+	entries, err := s.backend.List(ctx, "invitations/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+
+	var records []InvitationRecord
+	for _, e := range entries {
+		var rec InvitationRecord
+		if err := json.Unmarshal(e.Value, &rec); err != nil {
+			return nil, fmt.Errorf("failed to decode invitation %s: %w", e.Key, err)
+		}
+		if rec.OrganizationID == orgID {
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+// Save creates or replaces an invitation.
+func (s *InvitationStore) Save(ctx context.Context, rec *InvitationRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode invitation: %w", err)
+	}
+	return s.backend.Put(ctx, invitationKey(rec.ID), data)
+}
+
+// Delete removes an invitation. Deleting a missing invitation is not an error.
+func (s *InvitationStore) Delete(ctx context.Context, id string) error {
+	return s.backend.Delete(ctx, invitationKey(id))
+}
+
+func invitationKey(id string) string {
+	return "invitations/" + id
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryBackend is an in-process Backend. Data is lost on restart.
+type MemoryBackend struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+// NewMemoryBackend creates an empty in-memory backend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{data: make(map[string][]byte)}
+}
+
+// Get implements Backend.
+func (m *MemoryBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.get(key)
+}
+
+func (m *MemoryBackend) get(key string) ([]byte, error) {
+	v, ok := m.data[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), v...), nil
+}
+
+// Put implements Backend.
+func (m *MemoryBackend) Put(ctx context.Context, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete implements Backend.
+func (m *MemoryBackend) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, key)
+	return nil
+}
+
+// List implements Backend.
+func (m *MemoryBackend) List(ctx context.Context, prefix string) ([]Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var entries []Entry
+	for k, v := range m.data {
+		if strings.HasPrefix(k, prefix) {
+			entries = append(entries, Entry{Key: k, Value: append([]byte(nil), v...)})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+// Update implements Backend. The whole store is locked for the duration of fn,
+// so watch is not needed to detect conflicts.
+func (m *MemoryBackend) Update(ctx context.Context, watch []string, fn func(tx Tx) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx := &memoryTx{backend: m, writes: make(map[string][]byte)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	for k, v := range tx.writes {
+		if v == nil {
+			delete(m.data, k)
+		} else {
+			m.data[k] = v
+		}
+	}
+	return nil
+}
+
+// memoryTx buffers writes; a nil value marks a delete.
+type memoryTx struct {
+	backend *MemoryBackend
+	writes  map[string][]byte
+}
+
+func (t *memoryTx) Get(ctx context.Context, key string) ([]byte, error) {
+	if v, ok := t.writes[key]; ok {
+		if v == nil {
+			return nil, ErrNotFound
+		}
+		return append([]byte(nil), v...), nil
+	}
+	return t.backend.get(key)
+}
+
+func (t *memoryTx) Put(key string, value []byte) {
+	t.writes[key] = append([]byte{}, value...)
+}
+
+func (t *memoryTx) Delete(key string) {
+	t.writes[key] = nil
+}
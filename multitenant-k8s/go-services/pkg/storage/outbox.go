@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OutboxMessage is an event waiting to be published. It is written in the same
+// transaction as the state change it describes, so events are never lost or
+// published for changes that did not commit.
+type OutboxMessage struct {
+	ID        string    `json:"id"`
+	Key       []byte    `json:"key"`
+	Payload   []byte    `json:"payload"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OutboxStore reads and acknowledges pending outbox messages.
+type OutboxStore struct {
+	backend Backend
+}
+
+// NewOutboxStore creates an outbox store on top of backend.
+func NewOutboxStore(backend Backend) *OutboxStore {
+	return &OutboxStore{backend: backend}
+}
+
+// Pending returns up to limit unpublished messages, oldest first.
+func (s *OutboxStore) Pending(ctx context.Context, limit int) ([]OutboxMessage, error) {
+	entries, err := s.backend.List(ctx, "outbox/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox: %w", err)
+	}
+
+	var msgs []OutboxMessage
+	for _, e := range entries {
+		var m OutboxMessage
+		if err := json.Unmarshal(e.Value, &m); err != nil {
+			return nil, fmt.Errorf("failed to decode outbox message %s: %w", e.Key, err)
+		}
+		msgs = append(msgs, m)
+		if limit > 0 && len(msgs) == limit {
+			break
+		}
+	}
+	return msgs, nil
+}
+
+// Ack removes a message once it has been published.
+func (s *OutboxStore) Ack(ctx context.Context, msg OutboxMessage) error {
+	return s.backend.Delete(ctx, outboxKey(msg))
+}
+
+// stageOutbox adds messages to tx so they commit together with the caller's writes.
+func stageOutbox(tx Tx, msgs []OutboxMessage) error {
+	for _, m := range msgs {
+		if m.ID == "" {
+			return fmt.Errorf("outbox message id is required")
+		}
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = time.Now().UTC()
+		}
+		data, err := json.Marshal(m)
+		if err != nil {
+			return fmt.Errorf("failed to encode outbox message: %w", err)
+		}
+		tx.Put(outboxKey(m), data)
+	}
+	return nil
+}
+
+// outboxKey orders messages by creation time, then ID.
+func outboxKey(m OutboxMessage) string {
+	return fmt.Sprintf("outbox/%020d-%s", m.CreatedAt.UnixNano(), m.ID)
+}
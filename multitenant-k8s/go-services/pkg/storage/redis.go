@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxTxRetries bounds optimistic-lock retries in RedisBackend.Update.
+const maxTxRetries = 10
+
+// RedisBackend implements Backend using plain Redis strings.
+// Transactions use WATCH/MULTI/EXEC, so every key read inside Update must be watched.
+type RedisBackend struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisBackend connects to Redis and returns a backend that namespaces every key with prefix.
+func NewRedisBackend(addr, password string, db int, prefix string) (*RedisBackend, error) {
+	if addr == "" {
+		return nil, fmt.Errorf("redis addr must not be empty")
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	// Best-effort connectivity check.
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	return &RedisBackend{client: client, prefix: prefix}, nil
+}
+
+// Client returns the underlying Redis client.
+func (r *RedisBackend) Client() *redis.Client {
+	return r.client
+}
+
+// Get implements Backend.
+func (r *RedisBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	v, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	return v, nil
+}
+
+// Put implements Backend.
+func (r *RedisBackend) Put(ctx context.Context, key string, value []byte) error {
+	if err := r.client.Set(ctx, r.prefix+key, value, 0).Err(); err != nil {
+		return fmt.Errorf("failed to put %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete implements Backend.
+func (r *RedisBackend) Delete(ctx context.Context, key string) error {
+	if err := r.client.Del(ctx, r.prefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// List implements Backend using SCAN followed by MGET.
+func (r *RedisBackend) List(ctx context.Context, prefix string) ([]Entry, error) {
+	var keys []string
+	iter := r.client.Scan(ctx, 0, escapeGlob(r.prefix+prefix)+"*", 500).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", prefix, err)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	sort.Strings(keys)
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", prefix, err)
+	}
+
+	entries := make([]Entry, 0, len(keys))
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			// Deleted between SCAN and MGET.
+			continue
+		}
+		entries = append(entries, Entry{Key: strings.TrimPrefix(keys[i], r.prefix), Value: []byte(s)})
+	}
+	return entries, nil
+}
+
+// Update implements Backend with optimistic locking on the watched keys.
+func (r *RedisBackend) Update(ctx context.Context, watch []string, fn func(tx Tx) error) error {
+	watched := make([]string, len(watch))
+	for i, k := range watch {
+		watched[i] = r.prefix + k
+	}
+
+	for attempt := 0; attempt < maxTxRetries; attempt++ {
+		err := r.client.Watch(ctx, func(rtx *redis.Tx) error {
+			tx := &redisTx{rtx: rtx, prefix: r.prefix, writes: make(map[string][]byte)}
+			if err := fn(tx); err != nil {
+				return err
+			}
+			_, err := rtx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				for k, v := range tx.writes {
+					if v == nil {
+						pipe.Del(ctx, r.prefix+k)
+					} else {
+						pipe.Set(ctx, r.prefix+k, v, 0)
+					}
+				}
+				return nil
+			})
+			return err
+		}, watched...)
+
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		return err
+	}
+
+	return fmt.Errorf("transaction aborted after %d conflicting attempts", maxTxRetries)
+}
+
+// redisTx buffers writes; a nil value marks a delete.
+type redisTx struct {
+	rtx    *redis.Tx
+	prefix string
+	writes map[string][]byte
+}
+
+func (t *redisTx) Get(ctx context.Context, key string) ([]byte, error) {
+	if v, ok := t.writes[key]; ok {
+		if v == nil {
+			return nil, ErrNotFound
+		}
+		return append([]byte(nil), v...), nil
+	}
+	v, err := t.rtx.Get(ctx, t.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	return v, err
+}
+
+func (t *redisTx) Put(key string, value []byte) {
+	t.writes[key] = append([]byte{}, value...)
+}
+
+func (t *redisTx) Delete(key string) {
+	t.writes[key] = nil
+}
+
+// escapeGlob escapes Redis SCAN MATCH metacharacters.
+func escapeGlob(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+	return r.Replace(s)
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ReplicationRecord tracks how far a tenant's standby copy trails the primary.
+type ReplicationRecord struct {
+	OrganizationID string    `json:"organization_id"`
+	StandbyCluster string    `json:"standby_cluster"`
+	StandbyRoleARN string    `json:"standby_role_arn,omitempty"`
+	LastSyncedAt   time.Time `json:"last_synced_at,omitempty"` // zero until the first successful pass
+	LastAttemptAt  time.Time `json:"last_attempt_at"`
+	LastError      string    `json:"last_error,omitempty"` // error of the last attempt; empty when it succeeded
+	FailedOverAt   time.Time `json:"failed_over_at,omitempty"`
+}
+
+// ReplicationStore persists replication state keyed by organization ID.
+type ReplicationStore struct {
+	backend Backend
+}
+
+// NewReplicationStore creates a replication store on top of backend.
+func NewReplicationStore(backend Backend) *ReplicationStore {
+	return &ReplicationStore{backend: backend}
+}
+
+// Get returns the replication state of orgID, or ErrNotFound.
+func (s *ReplicationStore) Get(ctx context.Context, orgID string) (*ReplicationRecord, error) {
+	raw, err := s.backend.Get(ctx, replicationKey(orgID))
+	if err != nil {
+		return nil, err
+	}
+
+	var rec ReplicationRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode replication state %s: %w", orgID, err)
+	}
+	return &rec, nil
+}
+
+// List returns the replication state of every tenant ordered by organization ID.
+func (s *ReplicationStore) List(ctx context.Context) ([]ReplicationRecord, error) {
+	entries, err := s.backend.List(ctx, "replication/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replication state: %w", err)
+	}
+
+	records := make([]ReplicationRecord, 0, len(entries))
+	for _, e := range entries {
+		var rec ReplicationRecord
+		if err := json.Unmarshal(e.Value, &rec); err != nil {
+			return nil, fmt.Errorf("failed to decode replication state %s: %w", e.Key, err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// Save creates or replaces a tenant's replication state.
+func (s *ReplicationStore) Save(ctx context.Context, rec *ReplicationRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode replication state: %w", err)
+	}
+	return s.backend.Put(ctx, replicationKey(rec.OrganizationID), data)
+}
+
+// Delete removes a tenant's replication state.
+func (s *ReplicationStore) Delete(ctx context.Context, orgID string) error {
+	return s.backend.Delete(ctx, replicationKey(orgID))
+}
+
+func replicationKey(orgID string) string {
+	return "replication/" + orgID
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// usageBucketLayout names hourly rollup buckets so keys sort chronologically.
+const usageBucketLayout = "2006010215"
+
+// usageMonthLayout names the monthly partition rollups are keyed under, so a
+// report lists only the months it covers.
+const usageMonthLayout = "200601"
+
+// UsageRollup is the resource consumption of one organization aggregated over one hour.
+type UsageRollup struct {
+	OrganizationID   string    `json:"organization_id"`
+	Start            time.Time `json:"start"`
+	CPUCoreSeconds   float64   `json:"cpu_core_seconds"`
+	MemoryGiBSeconds float64   `json:"memory_gib_seconds"`
+	NodeSeconds      float64   `json:"node_seconds"`
+	Samples          int       `json:"samples"`
+}
+
+// UsageSample is a single point-in-time measurement for an organization.
+// Interval is the time the sample stands for (normally the collector period).
+type UsageSample struct {
+	OrganizationID string
+	Timestamp      time.Time
+	Interval       time.Duration
+	CPUCores       float64
+	MemoryBytes    float64
+	Nodes          int
+}
+
+// UsageStore keeps hourly usage rollups per organization.
+type UsageStore struct {
+	backend Backend
+}
+
+// NewUsageStore creates a usage store on top of backend.
+func NewUsageStore(backend Backend) *UsageStore {
+	return &UsageStore{backend: backend}
+}
+
+// Record folds a sample into the hourly rollup it falls in.
+func (s *UsageStore) Record(ctx context.Context, sample UsageSample) error {
+	if sample.OrganizationID == "" {
+		return fmt.Errorf("organization id is required")
+	}
+
+	start := sample.Timestamp.UTC().Truncate(time.Hour)
+	key := usageKey(sample.OrganizationID, start)
+	seconds := sample.Interval.Seconds()
+
+	return s.backend.Update(ctx, []string{key}, func(tx Tx) error {
+		rollup := UsageRollup{OrganizationID: sample.OrganizationID, Start: start}
+
+		raw, err := tx.Get(ctx, key)
+		switch {
+		case errors.Is(err, ErrNotFound):
+		case err != nil:
+			return fmt.Errorf("failed to read usage rollup: %w", err)
+		default:
+			if err := json.Unmarshal(raw, &rollup); err != nil {
+				return fmt.Errorf("failed to decode usage rollup: %w", err)
+			}
+		}
+
+		rollup.CPUCoreSeconds += sample.CPUCores * seconds
+		rollup.MemoryGiBSeconds += sample.MemoryBytes / (1 << 30) * seconds
+		rollup.NodeSeconds += float64(sample.Nodes) * seconds
+		rollup.Samples++
+
+		data, err := json.Marshal(rollup)
+		if err != nil {
+			return fmt.Errorf("failed to encode usage rollup: %w", err)
+		}
+		tx.Put(key, data)
+		return nil
+	})
+}
+
+// Rollups returns the hourly rollups in [from, to). If orgID is empty, all organizations are returned.
+func (s *UsageStore) Rollups(ctx context.Context, orgID string, from, to time.Time) ([]UsageRollup, error) {
+	var rollups []UsageRollup
+	for month := monthStart(from); month.Before(to); month = month.AddDate(0, 1, 0) {
+		prefix := "usage/" + month.Format(usageMonthLayout) + "/"
+		if orgID != "" {
+			prefix += orgID + "/"
+		}
+
+		entries, err := s.backend.List(ctx, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list usage rollups: %w", err)
+		}
+		for _, e := range entries {
+			var r UsageRollup
+			if err := json.Unmarshal(e.Value, &r); err != nil {
+				return nil, fmt.Errorf("failed to decode usage rollup %s: %w", e.Key, err)
+			}
+			if r.Start.Before(from) || !r.Start.Before(to) {
+				continue
+			}
+			rollups = append(rollups, r)
+		}
+	}
+	return rollups, nil
+}
+
+// DeleteBefore removes rollups older than cutoff for every organization.
+func (s *UsageStore) DeleteBefore(ctx context.Context, cutoff time.Time) error {
+	entries, err := s.backend.List(ctx, "usage/")
+	if err != nil {
+		return fmt.Errorf("failed to list usage rollups: %w", err)
+	}
+
+	bucket := cutoff.UTC().Truncate(time.Hour).Format(usageBucketLayout)
+	for _, e := range entries {
+		if e.Key[strings.LastIndex(e.Key, "/")+1:] < bucket {
+			if err := s.backend.Delete(ctx, e.Key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// usageKey partitions rollups by month, then organization
+func usageKey(orgID string, start time.Time) string {
+	return fmt.Sprintf("usage/%s/%s/%s", start.Format(usageMonthLayout), orgID, start.Format(usageBucketLayout))
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,170 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+)
+
+// FieldManager owns the fields templates set through server-side apply.
+const FieldManager = "account-service-templates"
+
+// Labels set on every object a template creates.
+const (
+	LabelTemplate = "tenant-template"
+	LabelVersion  = "tenant-template-version"
+)
+
+// Object identifies an object a template created in the tenant namespace.
+type Object struct {
+	APIVersion string
+	Kind       string
+	Name       string
+}
+
+// Applier server-side applies rendered templates to one cluster.
+type Applier struct {
+	dyn    dynamic.Interface
+	mapper meta.RESTMapper
+}
+
+// NewApplier creates an applier that resolves kinds through the cluster's discovery API.
+func NewApplier(kc kubernetes.Interface, dyn dynamic.Interface) *Applier {
+	return &Applier{
+		dyn:    dyn,
+		mapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(kc.Discovery())),
+	}
+}
+
+// Apply places objs in namespace, labelled with the template's name and
+// version, and returns what it applied. Templates may only create namespaced
+// objects; cluster-scoped kinds are rejected before anything is applied.
+func (a *Applier) Apply(ctx context.Context, t *Template, namespace string, objs []*unstructured.Unstructured) ([]Object, error) {
+	gvrs, err := a.prepare(t, namespace, objs)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]Object, 0, len(objs))
+	for i, obj := range objs {
+		_, err := a.dyn.Resource(gvrs[i]).Namespace(namespace).Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{
+			FieldManager: FieldManager,
+			Force:        true,
+		})
+		if err != nil {
+			return applied, fmt.Errorf("failed to apply %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		applied = append(applied, objectOf(obj))
+	}
+	return applied, nil
+}
+
+// Prepare places objs in namespace and labels them as Apply does, without
+// applying them, for callers that hand the objects to another applier such as
+// a GitOps controller. It returns the objects in the same form as Apply.
+func (a *Applier) Prepare(t *Template, namespace string, objs []*unstructured.Unstructured) ([]Object, error) {
+	if _, err := a.prepare(t, namespace, objs); err != nil {
+		return nil, err
+	}
+	prepared := make([]Object, 0, len(objs))
+	for _, obj := range objs {
+		prepared = append(prepared, objectOf(obj))
+	}
+	return prepared, nil
+}
+
+// prepare resolves the resource of every object, rejecting cluster-scoped
+// kinds, then sets the namespace and template labels
+func (a *Applier) prepare(t *Template, namespace string, objs []*unstructured.Unstructured) ([]schema.GroupVersionResource, error) {
+	gvrs := make([]schema.GroupVersionResource, len(objs))
+	for i, obj := range objs {
+		gvr, err := a.namespacedResource(obj.GroupVersionKind())
+		if err != nil {
+			return nil, err
+		}
+		gvrs[i] = gvr
+	}
+
+	for _, obj := range objs {
+		obj.SetNamespace(namespace)
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[LabelTemplate] = t.Name
+		labels[LabelVersion] = t.Version
+		obj.SetLabels(labels)
+	}
+	return gvrs, nil
+}
+
+func objectOf(obj *unstructured.Unstructured) Object {
+	return Object{APIVersion: obj.GetAPIVersion(), Kind: obj.GetKind(), Name: obj.GetName()}
+}
+
+// Prune deletes the objects in previous that current no longer contains, so
+// that an upgrade also removes what the new version dropped. Objects are
+// matched by group, kind and name: moving an object to another API version is
+// not a removal.
+func (a *Applier) Prune(ctx context.Context, namespace string, previous, current []Object) error {
+	keep := make(map[objectKey]bool, len(current))
+	for _, o := range current {
+		gvk, err := o.groupVersionKind()
+		if err != nil {
+			return err
+		}
+		keep[objectKey{gvk.GroupKind(), o.Name}] = true
+	}
+	for _, o := range previous {
+		gvk, err := o.groupVersionKind()
+		if err != nil {
+			return err
+		}
+		if keep[objectKey{gvk.GroupKind(), o.Name}] {
+			continue
+		}
+		gvr, err := a.namespacedResource(gvk)
+		if err != nil {
+			return err
+		}
+		err = a.dyn.Resource(gvr).Namespace(namespace).Delete(ctx, o.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete %s %s: %w", o.Kind, o.Name, err)
+		}
+	}
+	return nil
+}
+
+// objectKey identifies an object independent of the API version it was written in
+type objectKey struct {
+	kind schema.GroupKind
+	name string
+}
+
+func (o Object) groupVersionKind() (schema.GroupVersionKind, error) {
+	gv, err := schema.ParseGroupVersion(o.APIVersion)
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid apiVersion %q: %w", o.APIVersion, err)
+	}
+	return gv.WithKind(o.Kind), nil
+}
+
+func (a *Applier) namespacedResource(gvk schema.GroupVersionKind) (schema.GroupVersionResource, error) {
+	mapping, err := a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("failed to resolve %s: %w", gvk, err)
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return schema.GroupVersionResource{}, fmt.Errorf("%s is cluster-scoped; templates may only create namespaced objects", gvk.Kind)
+	}
+	return mapping.Resource, nil
+}
@@ -0,0 +1,93 @@
+package templates
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestPrune(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, version := range []string{"v1alpha1", "v1"} {
+		mapper.AddSpecific(
+			schema.GroupVersionKind{Group: "example.com", Version: version, Kind: "Widget"},
+			widgets.GroupResource().WithVersion(version),
+			schema.GroupVersionResource{Group: "example.com", Version: version, Resource: "widget"},
+			meta.RESTScopeNamespace,
+		)
+	}
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+
+	object := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetName(name)
+		obj.SetNamespace("tenant-acme")
+		return obj
+	}
+
+	tests := []struct {
+		name     string
+		previous []Object
+		current  []Object
+		want     []string // Deleted resource/name
+	}{
+		{
+			name:     "version change keeps the object",
+			previous: []Object{{APIVersion: "example.com/v1alpha1", Kind: "Widget", Name: "web"}},
+			current:  []Object{{APIVersion: "example.com/v1", Kind: "Widget", Name: "web"}},
+			want:     nil,
+		},
+		{
+			name: "dropped object is deleted",
+			previous: []Object{
+				{APIVersion: "example.com/v1alpha1", Kind: "Widget", Name: "web"},
+				{APIVersion: "v1", Kind: "ConfigMap", Name: "settings"},
+			},
+			current: []Object{{APIVersion: "example.com/v1", Kind: "Widget", Name: "web"}},
+			want:    []string{"configmaps/settings"},
+		},
+		{
+			name:     "renamed object is deleted",
+			previous: []Object{{APIVersion: "example.com/v1", Kind: "Widget", Name: "web"}},
+			current:  []Object{{APIVersion: "example.com/v1", Kind: "Widget", Name: "api"}},
+			want:     []string{"widgets/web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{widgets: "WidgetList", configMaps: "ConfigMapList"},
+				object("example.com/v1", "Widget", "web"),
+				object("v1", "ConfigMap", "settings"),
+			)
+			a := &Applier{dyn: dyn, mapper: mapper}
+
+			if err := a.Prune(context.Background(), "tenant-acme", tt.previous, tt.current); err != nil {
+				t.Fatalf("Prune: %v", err)
+			}
+
+			var got []string
+			for _, action := range dyn.Actions() {
+				if del, ok := action.(clienttesting.DeleteAction); ok {
+					got = append(got, del.GetResource().Resource+"/"+del.GetName())
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Prune deleted %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
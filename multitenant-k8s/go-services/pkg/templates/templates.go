@@ -0,0 +1,235 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// MetadataFile describes a template inside its directory.
+const MetadataFile = "template.yaml"
+
+// ValuesFile holds the Helm values of a chart template, rendered like a manifest.
+const ValuesFile = "values.yaml"
+
+// ErrUnknownTemplate is returned for a template name not in the catalog.
+var ErrUnknownTemplate = errors.New("unknown environment template")
+
+var namePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Values are what manifests and Helm values are templated with, e.g.
+// {{ .Namespace }} or {{ if eq .Tier "PLAN_TIER_PRO" }}.
+type Values struct {
+	OrgID     string
+	Namespace string
+	Tier      string // Plan tier enum name, e.g. "PLAN_TIER_PRO"
+	Cluster   string
+}
+
+// Metadata is the content of a template's template.yaml.
+type Metadata struct {
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+	Chart       string `json:"chart,omitempty"` // Helm chart directory, relative to the template; empty for plain manifests
+}
+
+// Template is a named, versioned starter stack for tenant namespaces. It is
+// either the Go-templated manifests under manifests/ or a Helm chart with
+// Go-templated values.
+type Template struct {
+	Name string
+	Metadata
+
+	dir       string
+	manifests []*template.Template
+	values    *template.Template // Chart templates only
+}
+
+// Catalog holds the templates of a directory with one subdirectory per template:
+//
+//	<dir>/<name>/template.yaml
+//	<dir>/<name>/manifests/*.yaml   (manifest templates)
+//	<dir>/<name>/values.yaml        (chart templates)
+type Catalog struct {
+	templates map[string]*Template
+	helm      string
+}
+
+// LoadCatalog reads every template under dir. Chart templates are rendered with
+// the helm binary; helmBinary may be empty if the catalog has none.
+func LoadCatalog(dir, helmBinary string) (*Catalog, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template directory: %w", err)
+	}
+
+	c := &Catalog{templates: map[string]*Template{}, helm: helmBinary}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		t, err := loadTemplate(filepath.Join(dir, e.Name()), e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load template %s: %w", e.Name(), err)
+		}
+		if t.Chart != "" && helmBinary == "" {
+			return nil, fmt.Errorf("template %s is a Helm chart but no helm binary is configured", t.Name)
+		}
+		c.templates[t.Name] = t
+	}
+	return c, nil
+}
+
+func loadTemplate(dir, name string) (*Template, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("name must be a DNS label")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, MetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	t := &Template{Name: name, dir: dir}
+	if err := yaml.Unmarshal(data, &t.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", MetadataFile, err)
+	}
+	if t.Version == "" {
+		return nil, fmt.Errorf("%s has no version", MetadataFile)
+	}
+
+	if t.Chart != "" {
+		t.values, err = parseFile(filepath.Join(dir, ValuesFile))
+		if errors.Is(err, os.ErrNotExist) {
+			t.values, err = template.New(ValuesFile).Parse("")
+		}
+		return t, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "manifests", "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no manifests and no chart")
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		tmpl, err := parseFile(p)
+		if err != nil {
+			return nil, err
+		}
+		t.manifests = append(t.manifests, tmpl)
+	}
+	return t, nil
+}
+
+func parseFile(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return template.New(filepath.Base(path)).Parse(string(data))
+}
+
+// Get returns the template called name.
+func (c *Catalog) Get(name string) (*Template, error) {
+	t, ok := c.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownTemplate, name)
+	}
+	return t, nil
+}
+
+// List returns every template, ordered by name.
+func (c *Catalog) List() []*Template {
+	out := make([]*Template, 0, len(c.templates))
+	for _, t := range c.templates {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Render returns the objects a template creates for one tenant.
+func (c *Catalog) Render(ctx context.Context, t *Template, v Values) ([]*unstructured.Unstructured, error) {
+	var docs bytes.Buffer
+	if t.Chart != "" {
+		var values bytes.Buffer
+		if err := t.values.Execute(&values, v); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", ValuesFile, err)
+		}
+		if err := c.helmTemplate(ctx, t, v, values.Bytes(), &docs); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, m := range t.manifests {
+			if err := m.Execute(&docs, v); err != nil {
+				return nil, fmt.Errorf("failed to render %s: %w", m.Name(), err)
+			}
+			docs.WriteString("\n---\n")
+		}
+	}
+	return decode(&docs)
+}
+
+// helmTemplate renders the chart with `helm template`, releasing it under the
+// template's name in the tenant namespace.
+func (c *Catalog) helmTemplate(ctx context.Context, t *Template, v Values, values []byte, out io.Writer) error {
+	f, err := os.CreateTemp("", "tenant-values-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to write helm values: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(values); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write helm values: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write helm values: %w", err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.helm, "template", t.Name, filepath.Join(t.dir, t.Chart),
+		"--namespace", v.Namespace, "--values", f.Name())
+	cmd.Stdout = out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to render chart %s: %w: %s", t.Chart, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// decode splits a multi-document YAML stream into objects, skipping empty documents
+func decode(r io.Reader) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var content map[string]interface{}
+		if err := decoder.Decode(&content); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objs, nil
+			}
+			return nil, fmt.Errorf("failed to parse rendered manifests: %w", err)
+		}
+		if len(content) == 0 {
+			continue
+		}
+		obj := &unstructured.Unstructured{Object: content}
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("rendered object is missing apiVersion, kind or metadata.name")
+		}
+		objs = append(objs, obj)
+	}
+}
@@ -0,0 +1,156 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/periodic"
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Collector periodically samples tenant resource usage from the Kubernetes
+// metrics API and records it in a UsageStore.
+type Collector struct {
+	k8sClient kubernetes.Interface
+	store     *storage.UsageStore
+	interval  time.Duration
+}
+
+// NewCollector creates a usage collector that samples every interval.
+func NewCollector(k8sClient kubernetes.Interface, store *storage.UsageStore, interval time.Duration) (*Collector, error) {
+	if k8sClient == nil {
+		return nil, fmt.Errorf("k8s client must not be nil")
+	}
+	if store == nil {
+		return nil, fmt.Errorf("usage store must not be nil")
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &Collector{
+		k8sClient: k8sClient,
+		store:     store,
+		interval:  interval,
+	}, nil
+}
+
+// Run samples usage until ctx is cancelled.
+func (c *Collector) Run(ctx context.Context) {
+	periodic.Every(ctx, c.interval, "usage collection", c.Collect)
+}
+
+// RunRetention deletes rollups older than horizon every hour until ctx is
+// cancelled.
+func RunRetention(ctx context.Context, store *storage.UsageStore, horizon time.Duration) {
+	periodic.Every(ctx, time.Hour, "usage retention", func(ctx context.Context) error {
+		return store.DeleteBefore(ctx, time.Now().Add(-horizon))
+	})
+}
+
+// Collect takes one sample for every tenant namespace.
+func (c *Collector) Collect(ctx context.Context) error {
+	namespaces, err := c.k8sClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{
+		LabelSelector: "managed-by=account-provisioning-service",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list tenant namespaces: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for _, ns := range namespaces.Items {
+		orgID := ns.Labels["tenant-id"]
+		if orgID == "" {
+			continue
+		}
+
+		cpuCores, memoryBytes, err := c.podUsage(ctx, ns.Name)
+		if err != nil {
+			// One tenant's metrics being unavailable should not block the others.
+			log.Printf("usage: skipping namespace %s: %v", ns.Name, err)
+			continue
+		}
+
+		// Sub-namespaces carry the organization type too; count a tenant's
+		// nodes once, against its primary namespace
+		nodes := 0
+		_, child := ns.Labels["parent-namespace"]
+		if ns.Labels["organization-type"] == "ORGANIZATION_TYPE_NODE" && !child {
+			nodes, err = c.dedicatedNodes(ctx, orgID)
+			if err != nil {
+				log.Printf("usage: failed to count nodes for %s: %v", orgID, err)
+			}
+		}
+
+		err = c.store.Record(ctx, storage.UsageSample{
+			OrganizationID: orgID,
+			Timestamp:      now,
+			Interval:       c.interval,
+			CPUCores:       cpuCores,
+			MemoryBytes:    memoryBytes,
+			Nodes:          nodes,
+		})
+		if err != nil {
+			log.Printf("usage: failed to record usage for %s: %v", orgID, err)
+			continue
+		}
+	}
+
+	return nil
+}
+
+// podMetricsList is the subset of metrics.k8s.io/v1beta1 PodMetricsList we read.
+type podMetricsList struct {
+	Items []struct {
+		Containers []struct {
+			Usage map[string]string `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// podUsage sums CPU (cores) and memory (bytes) over all pods in the namespace.
+func (c *Collector) podUsage(ctx context.Context, namespace string) (float64, float64, error) {
+	raw, err := c.k8sClient.Discovery().RESTClient().Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", namespace, "pods").
+		DoRaw(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query metrics API: %w", err)
+	}
+
+	var list podMetricsList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return 0, 0, fmt.Errorf("failed to decode pod metrics: %w", err)
+	}
+
+	var cpuCores, memoryBytes float64
+	for _, pod := range list.Items {
+		for _, container := range pod.Containers {
+			if q, err := resource.ParseQuantity(container.Usage["cpu"]); err == nil {
+				cpuCores += float64(q.MilliValue()) / 1000
+			}
+			if q, err := resource.ParseQuantity(container.Usage["memory"]); err == nil {
+				memoryBytes += float64(q.Value())
+			}
+		}
+	}
+
+	return cpuCores, memoryBytes, nil
+}
+
+// dedicatedNodes counts the nodes reserved for a NODE-isolated tenant.
+func (c *Collector) dedicatedNodes(ctx context.Context, orgID string) (int, error) {
+	nodes, err := c.k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("tenant-id=%s", orgID),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(nodes.Items), nil
+}
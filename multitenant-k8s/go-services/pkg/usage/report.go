@@ -0,0 +1,193 @@
+package usage
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/DevOps-In-Motion/DevOps/multitenant-k8s/go-services/pkg/storage"
+)
+
+// PriceSheet holds unit prices used to turn usage into cost.
+//
+// Example JSON:
+//
+//	{
+//	  "currency": "USD",
+//	  "cpu_core_hour": 0.04,
+//	  "memory_gib_hour": 0.005,
+//	  "node_hour": 0.35
+//	}
+type PriceSheet struct {
+	Currency      string  `json:"currency"`
+	CPUCoreHour   float64 `json:"cpu_core_hour"`
+	MemoryGiBHour float64 `json:"memory_gib_hour"`
+	NodeHour      float64 `json:"node_hour"`
+}
+
+// DefaultPriceSheet is used when no price sheet file is configured.
+var DefaultPriceSheet = PriceSheet{
+	Currency:      "USD",
+	CPUCoreHour:   0.04,
+	MemoryGiBHour: 0.005,
+	NodeHour:      0.35,
+}
+
+// LoadPriceSheet reads a JSON price sheet. An empty path returns DefaultPriceSheet.
+func LoadPriceSheet(path string) (*PriceSheet, error) {
+	if path == "" {
+		sheet := DefaultPriceSheet
+		return &sheet, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price sheet: %w", err)
+	}
+
+	var sheet PriceSheet
+	if err := json.Unmarshal(data, &sheet); err != nil {
+		return nil, fmt.Errorf("failed to parse price sheet: %w", err)
+	}
+	if sheet.Currency == "" {
+		sheet.Currency = DefaultPriceSheet.Currency
+	}
+
+	return &sheet, nil
+}
+
+// CostLine is the cost of one organization for a billing period.
+type CostLine struct {
+	OrganizationID string
+	CPUCoreHours   float64
+	MemoryGiBHours float64
+	NodeHours      float64
+	CPUCost        float64
+	MemoryCost     float64
+	NodeCost       float64
+	TotalCost      float64
+}
+
+// CostReport is the chargeback report for a billing period.
+type CostReport struct {
+	BillingPeriod string
+	PeriodStart   time.Time
+	PeriodEnd     time.Time
+	Currency      string
+	Lines         []CostLine
+	TotalCost     float64
+}
+
+// Reporter builds cost reports from stored usage rollups.
+type Reporter struct {
+	store  *storage.UsageStore
+	prices *PriceSheet
+}
+
+// NewReporter creates a reporter that prices usage with the given price sheet.
+func NewReporter(store *storage.UsageStore, prices *PriceSheet) *Reporter {
+	return &Reporter{store: store, prices: prices}
+}
+
+// Report builds the cost report for billingPeriod ("YYYY-MM").
+// If orgID is non-empty, only that organization is included.
+func (r *Reporter) Report(ctx context.Context, billingPeriod, orgID string) (*CostReport, error) {
+	start, end, err := ParseBillingPeriod(billingPeriod)
+	if err != nil {
+		return nil, err
+	}
+	billingPeriod = start.Format("2006-01")
+
+	rollups, err := r.store.Rollups(ctx, orgID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	byOrg := make(map[string]*CostLine)
+	for _, rollup := range rollups {
+		line, ok := byOrg[rollup.OrganizationID]
+		if !ok {
+			line = &CostLine{OrganizationID: rollup.OrganizationID}
+			byOrg[rollup.OrganizationID] = line
+		}
+		line.CPUCoreHours += rollup.CPUCoreSeconds / 3600
+		line.MemoryGiBHours += rollup.MemoryGiBSeconds / 3600
+		line.NodeHours += rollup.NodeSeconds / 3600
+	}
+
+	report := &CostReport{
+		BillingPeriod: billingPeriod,
+		PeriodStart:   start,
+		PeriodEnd:     end,
+		Currency:      r.prices.Currency,
+	}
+	for _, line := range byOrg {
+		line.CPUCost = line.CPUCoreHours * r.prices.CPUCoreHour
+		line.MemoryCost = line.MemoryGiBHours * r.prices.MemoryGiBHour
+		line.NodeCost = line.NodeHours * r.prices.NodeHour
+		line.TotalCost = line.CPUCost + line.MemoryCost + line.NodeCost
+		report.TotalCost += line.TotalCost
+		report.Lines = append(report.Lines, *line)
+	}
+	sort.Slice(report.Lines, func(i, j int) bool {
+		return report.Lines[i].OrganizationID < report.Lines[j].OrganizationID
+	})
+
+	return report, nil
+}
+
+// WriteCSV writes the report as CSV with one row per organization.
+func (c *CostReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{
+		"billing_period", "organization_id",
+		"cpu_core_hours", "memory_gib_hours", "node_hours",
+		"cpu_cost", "memory_cost", "node_cost", "total_cost", "currency",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, line := range c.Lines {
+		row := []string{
+			c.BillingPeriod, line.OrganizationID,
+			formatFloat(line.CPUCoreHours), formatFloat(line.MemoryGiBHours), formatFloat(line.NodeHours),
+			formatFloat(line.CPUCost), formatFloat(line.MemoryCost), formatFloat(line.NodeCost), formatFloat(line.TotalCost),
+			c.Currency,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ParseBillingPeriod turns "YYYY-MM" into the [start, end) range of that month in UTC.
+// An empty period means the current month.
+func ParseBillingPeriod(period string) (time.Time, time.Time, error) {
+	var start time.Time
+	if period == "" {
+		now := time.Now().UTC()
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	} else {
+		t, err := time.Parse("2006-01", period)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid billing period %q, expected YYYY-MM", period)
+		}
+		start = t.UTC()
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}